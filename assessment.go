@@ -0,0 +1,142 @@
+package commoncartridge
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// resourcesEndPattern matches the closing tag of the `<resources>` node of a manifest, or the node itself when it is empty and self-closing, e.g. `<resources/>`, along with its namespace prefix, if any, and the attributes of the self-closing tag.
+var resourcesEndPattern = regexp.MustCompile(`</(\w+:)?resources\s*>|<(\w+:)?resources(\s[^>]*?)?\s*/>`)
+
+// AssessmentType returns the resource type of a QTI assessment for the given version of the IMSCC standard (e.g. `1.3.0`), defaulting to the one of version 1.3.
+func AssessmentType(schemaVersion string) string {
+	return "imsqti_xmlv1p2/imscc_xmlv" + types.CCNamespaces(schemaVersion).Version + "/assessment"
+}
+
+// AddAssessment writes to w a copy of the cartridge in which the given QTI assessment is added as a resource. The assessment is written to `<ident>/assessment_qti.xml`, next to the manifest, and the resource, whose identifier is the ident of the assessment, is appended to the `<resources>` of the manifest, which is otherwise left untouched. It returns an error when a resource with the same identifier, or a file at the same path, already exists. It returns the identifier of the new resource.
+func (cc IMSCC) AddAssessment(w io.Writer, qti types.Questestinterop) (string, error) {
+	id := qti.Assessment.Ident
+	if id == "" {
+		return id, fmt.Errorf("the assessment has no ident")
+	}
+
+	for _, r := range cc.manifest.Resources.Resource {
+		if r.Identifier == id {
+			return id, fmt.Errorf("a resource with id %s already exists", id)
+		}
+	}
+
+	manifestFile := cc.manifestFile()
+	if manifestFile == nil {
+		return id, fmt.Errorf("the cartridge has no imsmanifest.xml file")
+	}
+
+	href := path.Join(id, "assessment_qti.xml")
+	if cc.HasFile(path.Join(path.Dir(manifestFile.Name), href)) {
+		return id, fmt.Errorf("the file %s already exists", path.Join(path.Dir(manifestFile.Name), href))
+	}
+
+	content, err := types.MarshalDocument(qti)
+	if err != nil {
+		return id, err
+	}
+
+	zw := zip.NewWriter(w)
+	modified := time.Now()
+	for _, f := range cc.Reader.File {
+		if f != manifestFile {
+			if err := zw.Copy(f); err != nil {
+				return id, err
+			}
+			continue
+		}
+
+		manifest, err := cc.addResource(f, id, href)
+		if err != nil {
+			return id, err
+		}
+
+		modified = f.Modified
+		if err := writeFile(zw, f.Name, modified, manifest); err != nil {
+			return id, err
+		}
+	}
+
+	if err := writeFile(zw, path.Join(path.Dir(manifestFile.Name), href), modified, append([]byte(xml.Header), content...)); err != nil {
+		return id, err
+	}
+
+	return id, zw.Close()
+}
+
+// addResource returns the content of the manifest file with a new assessment resource inserted at the end of its `<resources>`.
+func (cc IMSCC) addResource(f *zip.File, id string, href string) ([]byte, error) {
+	file, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	manifest, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := resourcesEndPattern.FindAllSubmatchIndex(manifest, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("could not find the resources of the manifest")
+	}
+
+	end := matches[len(matches)-1]
+	prefix, selfClosing := "", !bytes.HasPrefix(manifest[end[0]:end[1]], []byte("</"))
+	if end[2] >= 0 {
+		prefix = string(manifest[end[2]:end[3]])
+	} else if end[4] >= 0 {
+		prefix = string(manifest[end[4]:end[5]])
+	}
+
+	resource := fmt.Sprintf("  <%sresource identifier=\"%s\" type=\"%s\">\n      <%sfile href=\"%s\"/>\n    </%sresource>\n  ",
+		prefix, html.EscapeString(id), AssessmentType(cc.manifest.Metadata.Schemaversion), prefix, html.EscapeString(href), prefix)
+
+	out := make([]byte, 0, len(manifest)+len(resource))
+	if selfClosing {
+		//-- the empty node is opened with its attributes, and closed after the new resource
+		attrs := ""
+		if end[6] >= 0 {
+			attrs = string(manifest[end[6]:end[7]])
+		}
+		out = append(out, manifest[:end[0]]...)
+		out = append(out, fmt.Sprintf("<%sresources%s>\n  ", prefix, attrs)...)
+		out = append(out, resource...)
+		out = append(out, fmt.Sprintf("</%sresources>", prefix)...)
+		out = append(out, manifest[end[1]:]...)
+
+		return out, nil
+	}
+
+	out = append(out, manifest[:end[0]]...)
+	out = append(out, resource...)
+	out = append(out, manifest[end[0]:]...)
+
+	return out, nil
+}
+
+// writeFile adds a compressed file to the archive, with the given modification time.
+func writeFile(zw *zip.Writer, name string, modified time.Time, content []byte) error {
+	fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return err
+	}
+
+	_, err = fw.Write(content)
+	return err
+}
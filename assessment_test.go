@@ -0,0 +1,120 @@
+package commoncartridge
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssessmentType(t *testing.T) {
+	assert.Equal(t, AssessmentType("1.3.0"), "imsqti_xmlv1p2/imscc_xmlv1p3/assessment")
+	assert.Equal(t, AssessmentType("1.1.0"), "imsqti_xmlv1p2/imscc_xmlv1p1/assessment")
+	assert.Equal(t, AssessmentType(""), "imsqti_xmlv1p2/imscc_xmlv1p3/assessment")
+}
+
+func TestAddAssessment(t *testing.T) {
+	cc := load(t, singleTestFile).(IMSCC)
+	qti := loadQTI(t, qtiExample)
+	qti.Assessment.Ident = "i_added_assessment"
+
+	dst := filepath.Join(t.TempDir(), "added.imscc")
+	file, err := os.Create(dst)
+	require.Nil(t, err)
+
+	id, err := cc.AddAssessment(file, qti)
+	require.Nil(t, err)
+	require.Nil(t, file.Close())
+	assert.Equal(t, id, "i_added_assessment")
+
+	added := load(t, dst).(IMSCC)
+	assert.Equal(t, added.Title(), cc.Title())
+	assert.Equal(t, len(added.manifest.Resources.Resource), len(cc.manifest.Resources.Resource)+1)
+
+	res := added.manifest.Resources.Resource[len(added.manifest.Resources.Resource)-1]
+	assert.Equal(t, res.Type, "imsqti_xmlv1p2/imscc_xmlv1p3/assessment")
+	assert.Equal(t, res.File[0].Href, "i_added_assessment/assessment_qti.xml")
+
+	found, err := added.Find(id)
+	require.Nil(t, err)
+	require.IsType(t, found, types.Questestinterop{})
+	assert.Equal(t, found.(types.Questestinterop).Assessment.Title, "ALL QUESTION TYPES QUIZ")
+
+	quizzes, err := added.Quizzes()
+	require.Nil(t, err)
+	assert.Equal(t, len(quizzes), 18)
+	assert.Equal(t, len(quizzes[17].Questions), 6)
+
+	_, err = added.AddAssessment(file, qti)
+	assert.NotNil(t, err)
+
+	//-- a file left at the path of the assessment, without any resource, is not overwritten
+	r, err := zip.OpenReader(testcc.Rewrite(t, singleTestFile, map[string]string{"i_added_assessment/assessment_qti.xml": "<questestinterop/>"}))
+	require.Nil(t, err)
+	defer r.Close()
+	leftover := IMSCC{Reader: &r.Reader}
+	leftover.manifest, err = leftover.parseManifest()
+	require.Nil(t, err)
+	_, err = leftover.AddAssessment(io.Discard, qti)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestAddAssessmentManifest(t *testing.T) {
+	qti := loadQTI(t, qtiExample)
+	qti.Assessment.Ident = "i_added_assessment"
+
+	cc := archive(t, map[string]string{
+		"course/imsmanifest.xml": fmt.Sprintf(producerManifest, "", "", `<resource identifier="r1" type="webcontent" href="page.html"/>`),
+		"course/page.html":       "<p>Welcome</p>",
+	})
+	dst := filepath.Join(t.TempDir(), "added.imscc")
+	file, err := os.Create(dst)
+	require.Nil(t, err)
+	_, err = cc.AddAssessment(file, qti)
+	require.Nil(t, err)
+	require.Nil(t, file.Close())
+
+	r, err := zip.OpenReader(dst)
+	require.Nil(t, err)
+	defer r.Close()
+	added := IMSCC{Reader: &r.Reader}
+	assert.True(t, added.HasFile("course/i_added_assessment/assessment_qti.xml"))
+	manifest, err := added.ReadFile("course/imsmanifest.xml")
+	require.Nil(t, err)
+	assert.Contains(t, string(manifest), `href="i_added_assessment/assessment_qti.xml"`)
+
+	r, err = zip.OpenReader(testcc.Archive(t, map[string]string{"page.html": "<p>Welcome</p>"}))
+	require.Nil(t, err)
+	defer r.Close()
+	_, err = IMSCC{Reader: &r.Reader}.AddAssessment(io.Discard, qti)
+	assert.NotNil(t, err, "the cartridge has no manifest")
+
+	for _, empty := range []string{`<resources/>`, `<cp:resources xml:base="course/" />`} {
+		manifest := strings.Replace(fmt.Sprintf(producerManifest, "", `xmlns:cp="http://www.imsglobal.org/xsd/imsccv1p1/imscp_v1p1"`, ""), "<resources></resources>", empty, 1)
+		r, err = zip.OpenReader(testcc.Archive(t, map[string]string{"imsmanifest.xml": manifest}))
+		require.Nil(t, err)
+		defer r.Close()
+
+		var buf bytes.Buffer
+		_, err = IMSCC{Reader: &r.Reader}.AddAssessment(&buf, qti)
+		require.Nil(t, err, empty)
+
+		added, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.Nil(t, err)
+		cc := IMSCC{Reader: added}
+		parsed, err := cc.parseManifest()
+		require.Nil(t, err, empty)
+		require.Equal(t, len(parsed.Resources.Resource), 1, empty)
+		assert.Equal(t, parsed.Resources.Resource[0].Identifier, "i_added_assessment")
+	}
+}
@@ -0,0 +1,76 @@
+// The blackboard package decodes the conventions of the cartridges that Blackboard exports: the `.dat` documents of its content items, the files of its content collection in `csfiles/home_dir`, and the `@X@EmbeddedFile` tokens that the links of its HTML content use to refer to them. Importing the package registers its Extension, which sets the type and availability of the items of Blackboard cartridges.
+package blackboard
+
+import (
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+)
+
+func init() {
+	commoncartridge.RegisterExtension(Extension{})
+}
+
+// Extension is the commoncartridge.Extension of Blackboard exports.
+type Extension struct{}
+
+// Name returns `blackboard`.
+func (Extension) Name() string {
+	return commoncartridge.BlackboardProducer
+}
+
+// Detect returns true when the manifest or the folders of the cartridge point to Blackboard, e.g. because it has a `csfiles` folder or resources of type `resource/x-bb-document`. Only the manifest is read, see commoncartridge.IMSCC.ManifestProducer.
+func (Extension) Detect(cc commoncartridge.IMSCC) bool {
+	producer, err := cc.ManifestProducer()
+	return err == nil && producer.Name == commoncartridge.BlackboardProducer
+}
+
+// Supports returns true for the vendor extensions of Blackboard which this package decodes: its namespace, the resource types of its content items, its content collection and its embedded file tokens.
+func (Extension) Supports(v commoncartridge.VendorExtension) bool {
+	switch v.Kind {
+	case commoncartridge.NamespaceExtension:
+		return strings.Contains(v.Name, "blackboard.com")
+	case commoncartridge.ResourceTypeExtension:
+		return strings.HasPrefix(v.Name, handlerPrefix)
+	case commoncartridge.TokenExtension:
+		return strings.HasPrefix(v.Name, EmbeddedFile)
+	case commoncartridge.FileExtension:
+		return v.Name == homeDir+"/" || v.Name == filesDir+"/"
+	}
+
+	return false
+}
+
+// ExtendMetadata leaves the metadata as is.
+func (Extension) ExtendMetadata(cc commoncartridge.IMSCC, meta *commoncartridge.Metadata) error {
+	return nil
+}
+
+// ExtendItems sets the type of the items whose resource is a content item, e.g. `document`, `externallink` or `folder`, and marks those which are not available to learners as unpublished.
+func (Extension) ExtendItems(cc commoncartridge.IMSCC, items []commoncartridge.FullItem) ([]commoncartridge.FullItem, error) {
+	contents, err := Contents(cc)
+	if err != nil {
+		return items, err
+	}
+
+	byResource := make(map[string]Content)
+	for _, c := range contents {
+		byResource[c.Identifier] = c
+	}
+
+	var extend func(items []commoncartridge.FullItem)
+	extend = func(items []commoncartridge.FullItem) {
+		for i := range items {
+			if c, ok := byResource[items[i].Item.Identifierref]; ok {
+				items[i].Settings = &commoncartridge.ItemSettings{ContentType: c.Type, URL: c.URL, WorkflowState: "active"}
+				if !c.Available {
+					items[i].Settings.WorkflowState = "unpublished"
+				}
+			}
+			extend(items[i].Children)
+		}
+	}
+	extend(items)
+
+	return items, nil
+}
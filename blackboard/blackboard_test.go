@@ -0,0 +1,114 @@
+package blackboard
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blackboardFiles are the files of a cartridge following the conventions of Blackboard exports.
+var blackboardFiles = map[string]string{
+	"imsmanifest.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<manifest identifier="man00001" xmlns:bb="http://www.blackboard.com/content-packaging/">
+  <organizations default="toc00001">
+    <organization identifier="toc00001">
+      <item identifier="itm00000">
+        <item identifier="itm00001" identifierref="res00001">
+          <title>Week 1</title>
+          <item identifier="itm00002" identifierref="res00002"><title>Syllabus</title></item>
+          <item identifier="itm00003" identifierref="res00003"><title>Library</title></item>
+        </item>
+      </item>
+    </organization>
+  </organizations>
+  <resources>
+    <resource bb:file="res00001.dat" bb:title="Week 1" identifier="res00001" type="resource/x-bb-document" xml:base="res00001"/>
+    <resource bb:file="res00002.dat" bb:title="Syllabus" identifier="res00002" type="resource/x-bb-document" xml:base="res00002"/>
+    <resource bb:file="res00003.dat" bb:title="Library" identifier="res00003" type="resource/x-bb-document" xml:base="res00003"/>
+    <resource bb:file="res00004.dat" bb:title="Course settings" identifier="res00004" type="course/x-bb-coursesetting" xml:base="res00004"/>
+  </resources>
+</manifest>`,
+	"res00001.dat": `<?xml version="1.0" encoding="UTF-8"?>
+<CONTENT id="_1_1">
+  <TITLE value="Week 1"/>
+  <BODY><TEXT></TEXT><TYPE value="H"/></BODY>
+  <CONTENTHANDLER value="resource/x-bb-folder"/>
+  <FLAGS><ISAVAILABLE value="true"/><ISFOLDER value="true"/></FLAGS>
+</CONTENT>`,
+	"res00002.dat": `<?xml version="1.0" encoding="UTF-8"?>
+<CONTENT id="_2_1">
+  <TITLE value="Syllabus"/>
+  <BODY><TEXT>&lt;p&gt;Read the &lt;a href="@X@EmbeddedFile.requestUrlStub@X@bbcswebdav/xid-1234_1"&gt;syllabus&lt;/a&gt; and &lt;img src="@X@EmbeddedFile.requestUrlStub@X@bbcswebdav/xid-9999_1"/&gt; &lt;a href="@X@course.url@X@"&gt;the course&lt;/a&gt;.&lt;/p&gt;</TEXT><TYPE value="H"/></BODY>
+  <CONTENTHANDLER value="resource/x-bb-document"/>
+  <FLAGS><ISAVAILABLE value="false"/><ISFOLDER value="false"/></FLAGS>
+  <FILES>
+    <FILE id="_3_1"><NAME>/xid-1235_1</NAME><LINKNAME value="schedule.pdf"/></FILE>
+  </FILES>
+</CONTENT>`,
+	"res00003.dat": `<?xml version="1.0" encoding="UTF-8"?>
+<CONTENT id="_4_1">
+  <TITLE value="Library"/>
+  <BODY><TEXT></TEXT><TYPE value="H"/></BODY>
+  <CONTENTHANDLER value="resource/x-bb-externallink"/>
+  <URL value="https://library.example.edu"/>
+  <FLAGS><ISAVAILABLE value="true"/></FLAGS>
+</CONTENT>`,
+	"res00004.dat": `<?xml version="1.0" encoding="UTF-8"?><COURSE id="_1_1"/>`,
+	"csfiles/home_dir/syllabus__xid-1234_1.pdf":     `%PDF`,
+	"csfiles/home_dir/schedule__xid-1235_1.pdf":     `%PDF`,
+	"csfiles/home_dir/notes/week1__xid-1236_1.html": `<a href="@X@EmbeddedFile.requestUrlStub@X@bbcswebdav/courses/ENG101/syllabus.pdf">syllabus</a> <a href="missing.html">missing</a> <a href="#top">top</a>`,
+}
+
+func TestDetect(t *testing.T) {
+	cc := load(t, testcc.Archive(t, blackboardFiles))
+	require.Equal(t, len(cc.Extensions()), 1)
+	assert.Equal(t, cc.Extensions()[0].Name(), "blackboard")
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	assert.Empty(t, cc.Extensions())
+}
+
+func TestSupports(t *testing.T) {
+	cc := load(t, testcc.Archive(t, blackboardFiles))
+	producer, err := cc.Producer()
+	require.Nil(t, err)
+	require.Equal(t, producer.Name, commoncartridge.BlackboardProducer)
+
+	unsupported := make([]string, 0)
+	for _, v := range producer.Extensions {
+		if !v.Supported {
+			unsupported = append(unsupported, v.Kind+" "+v.Name)
+		}
+	}
+	assert.Equal(t, unsupported, []string{"resource_type course/x-bb-coursesetting", "token @X@course.url@X@"})
+}
+
+func TestExtendItems(t *testing.T) {
+	cc := load(t, testcc.Archive(t, blackboardFiles))
+	items, err := cc.Items()
+	require.Nil(t, err)
+
+	require.Equal(t, len(items), 1)
+	require.NotNil(t, items[0].Settings)
+	assert.Equal(t, items[0].Settings.ContentType, "folder")
+
+	require.Equal(t, len(items[0].Children), 2)
+	syllabus, library := items[0].Children[0], items[0].Children[1]
+	require.NotNil(t, syllabus.Settings)
+	assert.Equal(t, syllabus.Settings.ContentType, "document")
+	assert.Equal(t, syllabus.Settings.WorkflowState, "unpublished")
+	require.NotNil(t, library.Settings)
+	assert.Equal(t, library.Settings.ContentType, "externallink")
+	assert.Equal(t, library.Settings.URL, "https://library.example.edu")
+	assert.True(t, library.Settings.Published())
+}
+
+func load(t *testing.T, p string) commoncartridge.IMSCC {
+	cc, err := commoncartridge.Load(p)
+	require.Nil(t, err)
+	return cc
+}
@@ -0,0 +1,174 @@
+package blackboard
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+const (
+	// handlerPrefix is the prefix of the resource types of the content items of Blackboard, e.g. `resource/x-bb-document`.
+	handlerPrefix = "resource/x-bb-"
+	// filesDir is the folder in which Blackboard exports the content collection of a course, and homeDir the one of the files of the course itself.
+	filesDir = "csfiles"
+	homeDir  = "csfiles/home_dir"
+)
+
+// Content is a content item of a Blackboard course, such as a document, an external link or a folder.
+type Content struct {
+	// Identifier is the identifier of the resource of the content item.
+	Identifier string
+	Title      string
+	// Type is the content handler of the item without its prefix, e.g. `document`, `file`, `externallink`, `folder`, `lesson` or `assignment`.
+	Type string
+	// Path is the path of the `.dat` document of the content item in the archive.
+	Path string
+	// Body is HTML, whose links still hold the tokens of Blackboard.
+	Body string
+	// URL is the address of an external link.
+	URL       string
+	Available bool
+	// Links are the links of the body, resolved to the files of the cartridge.
+	Links []commoncartridge.Link
+	// Files are the files attached to the content item, resolved to the files of the content collection.
+	Files []commoncartridge.Link
+}
+
+// Contents returns the content items of a Blackboard course, from the `.dat` documents of the resources whose type starts with `resource/x-bb-`, in the order of the manifest. The other documents of the export, e.g. the settings of the course, are left out, and so are the content items whose document is missing from the archive, which Unresolved reports. It returns an error when the document of a content item cannot be decoded.
+func Contents(cc commoncartridge.IMSCC) ([]Content, error) {
+	contents, _, err := readContents(cc)
+	return contents, err
+}
+
+// readContents returns the content items of a Blackboard course, as Contents does, and the paths of the documents of content items which are missing from the archive.
+func readContents(cc commoncartridge.IMSCC) ([]Content, []string, error) {
+	contents, missing := make([]Content, 0), make([]string, 0)
+
+	manifest, err := readManifest(cc)
+	if err != nil {
+		return contents, missing, err
+	}
+
+	names := make(map[string]bool, len(cc.Reader.File))
+	for _, f := range cc.Reader.File {
+		names[f.Name] = true
+	}
+
+	lr := NewLinkResolver(cc, ".")
+	for _, r := range manifest.Resources.Resource {
+		p := r.File
+		if p == "" {
+			p = r.Href
+		}
+		if !strings.HasPrefix(r.Type, handlerPrefix) || !strings.EqualFold(path.Ext(p), ".dat") {
+			continue
+		}
+		if !names[p] {
+			missing = append(missing, p)
+			continue
+		}
+
+		bytesArray, err := cc.ReadFile(p)
+		if err != nil {
+			return contents, missing, err
+		}
+
+		var meta types.BlackboardContent
+		if err := xml.Unmarshal(bytesArray, &meta); err != nil {
+			return contents, missing, fmt.Errorf("could not read the content %s: %w", p, err)
+		}
+
+		content := NewContent(meta)
+		content.Identifier, content.Path = r.Identifier, p
+		if content.Title == "" {
+			content.Title = strings.TrimSpace(r.Title)
+		}
+		if content.Type == "" {
+			content.Type = strings.TrimPrefix(r.Type, handlerPrefix)
+		}
+
+		content.Links = lr.Links(content.Body)
+		for _, f := range meta.Files.File {
+			content.Files = append(content.Files, lr.Resolve("href", strings.TrimSpace(f.Name)))
+		}
+
+		contents = append(contents, content)
+	}
+
+	return contents, missing, nil
+}
+
+// NewContent returns a content item from its decoded document, without its links and files.
+func NewContent(c types.BlackboardContent) Content {
+	return Content{
+		Title:     strings.TrimSpace(c.Title.Value),
+		Type:      strings.TrimPrefix(strings.TrimSpace(c.ContentHandler.Value), handlerPrefix),
+		Body:      strings.TrimSpace(c.Body.Content),
+		URL:       strings.TrimSpace(c.URL.Value),
+		Available: strings.TrimSpace(c.Flags.IsAvailable.Value) != "false",
+		Links:     make([]commoncartridge.Link, 0),
+		Files:     make([]commoncartridge.Link, 0),
+	}
+}
+
+// Unresolved returns the links of the content items and of the HTML files of a Blackboard course, as well as the attached files, which could not be resolved to a file of the cartridge, e.g. because the file they refer to was not part of the export. The documents of content items which are missing from the archive come first, with the manifest as their source.
+func Unresolved(cc commoncartridge.IMSCC) ([]commoncartridge.UnresolvedLink, error) {
+	unresolved := make([]commoncartridge.UnresolvedLink, 0)
+
+	contents, missing, err := readContents(cc)
+	if err != nil {
+		return unresolved, err
+	}
+
+	for _, p := range missing {
+		unresolved = append(unresolved, commoncartridge.UnresolvedLink{Source: cc.ManifestPath(), Link: commoncartridge.Link{Attribute: "href", Raw: p}})
+	}
+
+	for _, c := range contents {
+		for _, l := range append(c.Links, c.Files...) {
+			if l.Unresolved() {
+				unresolved = append(unresolved, commoncartridge.UnresolvedLink{Source: c.Path, Link: l})
+			}
+		}
+	}
+
+	lr := NewLinkResolver(cc, ".")
+	for _, f := range cc.Reader.File {
+		if ext := strings.ToLower(path.Ext(f.Name)); ext != ".html" && ext != ".htm" {
+			continue
+		}
+
+		bytesArray, err := cc.ReadFile(f.Name)
+		if err != nil {
+			return unresolved, err
+		}
+
+		for _, l := range lr.WithBase(path.Dir(f.Name)).Links(string(bytesArray)) {
+			if l.Unresolved() {
+				unresolved = append(unresolved, commoncartridge.UnresolvedLink{Source: f.Name, Link: l})
+			}
+		}
+	}
+
+	return unresolved, nil
+}
+
+// readManifest decodes the manifest of the cartridge, with the attributes of the `bb` namespace.
+func readManifest(cc commoncartridge.IMSCC) (types.BlackboardManifest, error) {
+	var manifest types.BlackboardManifest
+
+	bytesArray, err := cc.ReadFile(cc.ManifestPath())
+	if err != nil {
+		return manifest, err
+	}
+
+	if err := xml.Unmarshal(bytesArray, &manifest); err != nil {
+		return manifest, fmt.Errorf("could not read the manifest: %w", err)
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,113 @@
+package blackboard
+
+import (
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContents(t *testing.T) {
+	cc := load(t, testcc.Archive(t, blackboardFiles))
+	contents, err := Contents(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(contents), 3)
+	assert.Equal(t, contents[0].Type, "folder")
+	assert.Equal(t, contents[1].Identifier, "res00002")
+	assert.Equal(t, contents[1].Title, "Syllabus")
+	assert.Equal(t, contents[1].Path, "res00002.dat")
+	assert.False(t, contents[1].Available)
+	assert.Equal(t, contents[2].URL, "https://library.example.edu")
+
+	require.Equal(t, len(contents[1].Links), 3)
+	assert.Equal(t, contents[1].Links[0].Path, "csfiles/home_dir/syllabus__xid-1234_1.pdf")
+	assert.Equal(t, contents[1].Links[0].Raw, "@X@EmbeddedFile.requestUrlStub@X@bbcswebdav/xid-1234_1")
+	assert.Empty(t, contents[1].Links[1].Path)
+	assert.Empty(t, contents[1].Links[2].Path)
+
+	require.Equal(t, len(contents[1].Files), 1)
+	assert.Equal(t, contents[1].Files[0].Path, "csfiles/home_dir/schedule__xid-1235_1.pdf")
+}
+
+func TestMalformedContent(t *testing.T) {
+	files := map[string]string{}
+	for name, content := range blackboardFiles {
+		files[name] = content
+	}
+	files["res00002.dat"] = `<CONTENT><TITLE>`
+
+	_, err := Contents(load(t, testcc.Archive(t, files)))
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "res00002.dat")
+}
+
+func TestResolve(t *testing.T) {
+	cc := load(t, testcc.Archive(t, blackboardFiles))
+	lr := NewLinkResolver(cc, "csfiles/home_dir/notes")
+
+	link := lr.Resolve("href", "@X@EmbeddedFile.requestUrlStub@X@bbcswebdav/courses/ENG101/syllabus.pdf")
+	assert.Equal(t, link.Path, "csfiles/home_dir/syllabus__xid-1234_1.pdf")
+
+	link = lr.Resolve("src", "@X@EmbeddedFile.location@X@")
+	assert.Empty(t, link.Path)
+	assert.False(t, link.External)
+
+	link = lr.Resolve("href", "https://example.com/@X@course.url@X@")
+	assert.True(t, link.External)
+
+	link = lr.Resolve("href", "week1__xid-1236_1.html")
+	assert.Equal(t, link.Path, "csfiles/home_dir/notes/week1__xid-1236_1.html")
+
+	link = lr.WithBase("csfiles/home_dir").Resolve("href", "notes/week1__xid-1236_1.html")
+	assert.Equal(t, link.Path, "csfiles/home_dir/notes/week1__xid-1236_1.html")
+
+	//-- a file without id keeps its name whole
+	files := map[string]string{"csfiles/home_dir/week__2.pdf": `%PDF`}
+	for name, content := range blackboardFiles {
+		files[name] = content
+	}
+	lr = NewLinkResolver(load(t, testcc.Archive(t, files)), "csfiles/home_dir")
+	link = lr.Resolve("href", "@X@EmbeddedFile.requestUrlStub@X@bbcswebdav/courses/ENG101/week__2.pdf")
+	assert.Equal(t, link.Path, "csfiles/home_dir/week__2.pdf")
+	link = lr.Resolve("href", "@X@EmbeddedFile.requestUrlStub@X@bbcswebdav/courses/ENG101/week2.pdf")
+	assert.Empty(t, link.Path)
+}
+
+func TestUnresolved(t *testing.T) {
+	cc := load(t, testcc.Archive(t, blackboardFiles))
+	unresolved, err := Unresolved(cc)
+	require.Nil(t, err)
+
+	raw := make([]string, 0)
+	for _, u := range unresolved {
+		raw = append(raw, u.Source+" "+u.Raw)
+	}
+	assert.Equal(t, raw, []string{
+		"res00002.dat @X@EmbeddedFile.requestUrlStub@X@bbcswebdav/xid-9999_1",
+		"res00002.dat @X@course.url@X@",
+		"csfiles/home_dir/notes/week1__xid-1236_1.html missing.html",
+	})
+}
+
+func TestUnresolvedContent(t *testing.T) {
+	files := map[string]string{}
+	for name, content := range blackboardFiles {
+		if name != "res00003.dat" {
+			files[name] = content
+		}
+	}
+	cc := load(t, testcc.Archive(t, files))
+
+	contents, err := Contents(cc)
+	require.Nil(t, err)
+	assert.Equal(t, len(contents), 2)
+
+	unresolved, err := Unresolved(cc)
+	require.Nil(t, err)
+	require.Equal(t, len(unresolved), 4)
+	assert.Equal(t, unresolved[0].Source, "imsmanifest.xml")
+	assert.Equal(t, unresolved[0].Raw, "res00003.dat")
+	assert.True(t, unresolved[0].Unresolved())
+}
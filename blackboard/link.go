@@ -0,0 +1,109 @@
+package blackboard
+
+import (
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+)
+
+// EmbeddedFile is the prefix of the tokens that Blackboard substitutes to the location of the files of its content collection, e.g. `@X@EmbeddedFile.requestUrlStub@X@bbcswebdav/xid-1234_1`.
+const EmbeddedFile = "@X@EmbeddedFile"
+
+// tokenMarker delimits the tokens of Blackboard.
+const tokenMarker = "@X@"
+
+// xidPattern matches the id of a file of the content collection, which Blackboard appends to the names of the files it exports, e.g. `notes__xid-1234_1.pdf`.
+var xidPattern = regexp.MustCompile(`xid-(\d+_\d+)`)
+
+// LinkResolver resolves the links found in the HTML content of a Blackboard course, including the tokens that Blackboard substitutes to the location of the files of its content collection.
+type LinkResolver struct {
+	files commoncartridge.LinkResolver
+	// xids are the paths of the files of the content collection, by their id, e.g. `1234_1`.
+	xids map[string]string
+	// names are the paths of the files of the content collection, by their name without their id.
+	names map[string]string
+}
+
+// NewLinkResolver returns a LinkResolver for the HTML of a Blackboard course, which indexes the files of its content collection by id and by name. The other links are resolved from the base directory, as commoncartridge.IMSCC.NewLinkResolver does.
+func NewLinkResolver(cc commoncartridge.IMSCC, base string) LinkResolver {
+	lr := LinkResolver{
+		files: cc.NewLinkResolver(base),
+		xids:  make(map[string]string),
+		names: make(map[string]string),
+	}
+
+	for _, f := range cc.Reader.File {
+		if !strings.HasPrefix(f.Name, filesDir+"/") || strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+
+		name := path.Base(f.Name)
+		if m := xidPattern.FindStringSubmatch(name); m != nil {
+			lr.xids[m[1]] = f.Name
+			name = strings.Replace(name, "__"+m[0], "", 1)
+		}
+		lr.names[name] = f.Name
+	}
+
+	return lr
+}
+
+// WithBase returns a copy of the LinkResolver whose relative links are resolved from another base directory, without indexing the files of the content collection again.
+func (lr LinkResolver) WithBase(base string) LinkResolver {
+	lr.files = lr.files.WithBase(base)
+	return lr
+}
+
+// Links returns all the `src` and `href` references found in the given HTML, resolved to the files of the cartridge.
+func (lr LinkResolver) Links(content string) []commoncartridge.Link {
+	links := lr.files.Links(content)
+	for i, l := range links {
+		links[i] = lr.Resolve(l.Attribute, l.Raw)
+	}
+
+	return links
+}
+
+// Resolve returns the Link corresponding to the raw value of the given attribute. A link holding a token of Blackboard, or the location of a file of the content collection such as `/xid-1234_1`, is resolved to the file with the same id in `csfiles`, or failing that to the file with the same name. Tokens which do not refer to a file, e.g. `@X@course.url@X@`, are left unresolved. External links, and links without tokens, are resolved as commoncartridge.LinkResolver does.
+func (lr LinkResolver) Resolve(attribute, raw string) commoncartridge.Link {
+	ref := html.UnescapeString(strings.TrimSpace(raw))
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+
+	if !strings.Contains(ref, tokenMarker) && !xidPattern.MatchString(ref) {
+		return lr.files.Resolve(attribute, raw)
+	}
+	if u, err := url.Parse(ref); err == nil && (u.Scheme != "" || u.Host != "") {
+		return lr.files.Resolve(attribute, raw)
+	}
+
+	link := commoncartridge.Link{Attribute: strings.ToLower(attribute), Raw: raw}
+	if p := lr.file(ref); p != "" {
+		link = lr.files.Resolve(attribute, "/"+p)
+		link.Raw = raw
+	}
+
+	return link
+}
+
+// file returns the path of the file of the content collection that a reference points to, or an empty string when there is none.
+func (lr LinkResolver) file(ref string) string {
+	if m := xidPattern.FindStringSubmatch(ref); m != nil {
+		return lr.xids[m[1]]
+	}
+
+	name := ref[strings.LastIndex(ref, tokenMarker)+len(tokenMarker):]
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" {
+		return ""
+	}
+
+	return lr.names[path.Base(name)]
+}
@@ -0,0 +1,217 @@
+package canvas
+
+import (
+	"encoding/xml"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// submissionFormats are the formats of the IMSCC assignment extension which correspond to the submission types of Canvas.
+var submissionFormats = map[string]string{
+	"online_text_entry": "html",
+	"online_url":        "url",
+	"online_upload":     "file",
+}
+
+// Assignment is an assignment of the cartridge, as described by the IMSCC assignment extension, along with the settings that Canvas adds to it.
+type Assignment struct {
+	types.Assignment
+	// Settings are the Canvas settings of the assignment, or nil when the cartridge has none.
+	Settings *AssignmentSettings `json:",omitempty"`
+}
+
+// AssignmentSettings are the settings of a Canvas assignment.
+type AssignmentSettings struct {
+	// Identifier is the identifier of the assignment in Canvas, which differs from the one of its resource.
+	Identifier string
+	// DueAt, LockAt and UnlockAt are the zero time when they are not set.
+	DueAt    time.Time
+	LockAt   time.Time
+	UnlockAt time.Time
+	AllDay   bool
+	// AllDayDate is the day on which an all-day assignment is due, in the time zone of the course, or the zero time.
+	AllDayDate time.Time
+	// SubmissionTypes are the ways learners can submit their work, e.g. `online_upload`, `online_text_entry`, `on_paper` or `none`.
+	SubmissionTypes []string
+	// AllowedExtensions are the file extensions accepted for an `online_upload`, all of them when empty.
+	AllowedExtensions []string
+	// GradingType is one of `points`, `percent`, `pass_fail`, `letter_grade`, `gpa_scale` or `not_graded`.
+	GradingType    string
+	PointsPossible float64
+	// Group is the identifier of the assignment group of the assignment.
+	Group         string
+	Position      int
+	WorkflowState string
+	// Rubric is the identifier of the rubric grading the assignment, if any.
+	Rubric string
+	// GroupAssignment is true when learners submit as a group.
+	GroupAssignment                bool
+	GradeGroupStudentsIndividually bool
+	PeerReviews                    PeerReviews
+	OmitFromFinalGrade             bool
+	Muted                          bool
+	ModeratedGrading               bool
+	AnonymousGrading               bool
+	Raw                            types.CanvasAssignment
+}
+
+// PeerReviews are the settings of the reviews that learners make of each other's submissions.
+type PeerReviews struct {
+	Enabled   bool
+	Automatic bool
+	Anonymous bool
+	// Count is the number of reviews assigned to each learner, when they are automatic.
+	Count int
+	DueAt time.Time
+}
+
+// Published returns false when the assignment is hidden from learners.
+func (s AssignmentSettings) Published() bool {
+	return published(s.WorkflowState)
+}
+
+// Assignments returns the assignments of the cartridge, with their Canvas settings. Older Canvas exports, as well as the other exporters, describe assignments with the IMSCC assignment extension, to which the settings found in its `<extensions>` are added. Newer Canvas exports only have the `assignment_settings.xml` and the HTML page of the assignment, from which the IMSCC assignment is built. The assignments of cartridges which are not exported by Canvas have no settings.
+func Assignments(cc commoncartridge.IMSCC) ([]Assignment, error) {
+	assignments := make([]Assignment, 0)
+
+	base, err := cc.Assignments()
+	if err != nil {
+		return assignments, err
+	}
+
+	items, err := GradedItems(cc)
+	if err != nil {
+		return assignments, err
+	}
+
+	settings := make(map[string]types.CanvasAssignment)
+	for _, item := range items {
+		if item.Type == AssignmentItem {
+			settings[item.Identifier] = item.Assignment
+		}
+	}
+
+	found := make(map[string]bool)
+	for _, a := range base {
+		assignment := Assignment{Assignment: a}
+		if s, ok := settings[a.Identifier]; ok {
+			assignment.Settings = NewAssignmentSettings(s)
+			found[a.Identifier] = true
+		}
+
+		assignments = append(assignments, assignment)
+	}
+
+	for _, item := range items {
+		if item.Type != AssignmentItem || found[item.Identifier] {
+			continue
+		}
+
+		assignment, err := newAssignment(cc, item)
+		if err != nil {
+			return assignments, err
+		}
+
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, nil
+}
+
+// NewAssignmentSettings returns the settings of a Canvas assignment from their decoded document.
+func NewAssignmentSettings(a types.CanvasAssignment) *AssignmentSettings {
+	settings := &AssignmentSettings{
+		Identifier:                     a.Identifier,
+		DueAt:                          parseTime(a.DueAt),
+		LockAt:                         parseTime(a.LockAt),
+		UnlockAt:                       parseTime(a.UnlockAt),
+		AllDay:                         parseBool(a.AllDay),
+		AllDayDate:                     parseTime(a.AllDayDate),
+		SubmissionTypes:                parseList(a.SubmissionTypes),
+		AllowedExtensions:              parseList(a.AllowedExtensions),
+		GradingType:                    strings.TrimSpace(a.GradingType),
+		Group:                          strings.TrimSpace(a.AssignmentGroupIdentifierref),
+		Position:                       parseInt(a.Position),
+		WorkflowState:                  strings.TrimSpace(a.WorkflowState),
+		Rubric:                         strings.TrimSpace(a.RubricIdentifierref),
+		GroupAssignment:                parseBool(a.HasGroupCategory),
+		GradeGroupStudentsIndividually: parseBool(a.GradeGroupStudentsIndividually),
+		PeerReviews: PeerReviews{
+			Enabled:   parseBool(a.PeerReviews),
+			Automatic: parseBool(a.AutomaticPeerReviews),
+			Anonymous: parseBool(a.AnonymousPeerReviews),
+			Count:     parseInt(a.PeerReviewCount),
+			DueAt:     parseTime(a.PeerReviewsDueAt),
+		},
+		OmitFromFinalGrade: parseBool(a.OmitFromFinalGrade),
+		Muted:              parseBool(a.Muted),
+		ModeratedGrading:   parseBool(a.ModeratedGrading),
+		AnonymousGrading:   parseBool(a.AnonymousGrading),
+		Raw:                a,
+	}
+	settings.PointsPossible, _ = strconv.ParseFloat(strings.TrimSpace(a.PointsPossible), 64)
+
+	return settings
+}
+
+// newAssignment builds the IMSCC assignment of a Canvas assignment from its settings and the body of the HTML page of its resource.
+func newAssignment(cc commoncartridge.IMSCC, item GradedItem) (Assignment, error) {
+	assignment := Assignment{Settings: NewAssignmentSettings(item.Assignment)}
+	assignment.XMLName = xml.Name{Local: "assignment"}
+	assignment.Identifier = item.Identifier
+	assignment.Title = item.Title
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return assignment, err
+	}
+
+	for _, r := range manifest.Resources.Resource {
+		if r.Identifier != item.Identifier || r.Href == "" || path.Base(r.Href) == "assignment_settings.xml" {
+			continue
+		}
+
+		bytesArray, err := cc.ReadFile(r.Href)
+		if err != nil {
+			return assignment, err
+		}
+
+		assignment.Text.Text = strings.TrimSpace(commoncartridge.HTMLBody(string(bytesArray)))
+		assignment.Text.Texttype = "text/html"
+	}
+
+	if assignment.Settings.GradingType != "not_graded" {
+		assignment.Gradable.Text = "true"
+		assignment.Gradable.PointsPossible = formatPoints(assignment.Settings.PointsPossible)
+	} else {
+		assignment.Gradable.Text = "false"
+	}
+
+	for _, t := range assignment.Settings.SubmissionTypes {
+		if format, ok := submissionFormats[t]; ok {
+			assignment.SubmissionFormats.Format = append(assignment.SubmissionFormats.Format, struct {
+				Text string `xml:",chardata"`
+				Type string `xml:"type,attr"`
+			}{Type: format})
+		}
+	}
+
+	return assignment, nil
+}
+
+// parseList returns the values of a comma-separated Canvas setting.
+func parseList(s string) []string {
+	list := make([]string, 0)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
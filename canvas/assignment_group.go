@@ -0,0 +1,279 @@
+package canvas
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// assignmentGroupsFile is the document holding the assignment groups of a Canvas course.
+var assignmentGroupsFile = settingsFile("assignment_groups.xml")
+
+// The types of GradedItem.
+const (
+	AssignmentItem = "assignment"
+	QuizItem       = "quiz"
+	DiscussionItem = "discussion_topic"
+)
+
+// AssignmentGroup is a category of the gradebook of a Canvas course, with the assignments, quizzes and graded discussions it holds.
+type AssignmentGroup struct {
+	Identifier string
+	Title      string
+	Position   int
+	// GroupWeight is the weight of the group, as set in the course.
+	GroupWeight float64
+	// Weight is the share of the final grade given by the group, in percent. When the course is weighted by group, it is the weight of the group relative to the total weight of the groups, otherwise it is the share of the points of the group.
+	Weight float64
+	// PointsPossible is the total of the points of the items which count towards the final grade.
+	PointsPossible float64
+	// DropLowest and DropHighest are the number of lowest and highest scores of the group which are ignored.
+	DropLowest  int
+	DropHighest int
+	Items       []GradedItem
+}
+
+// GradedItem is an assignment, a graded quiz or a graded discussion, as found in the gradebook.
+type GradedItem struct {
+	// Identifier is the identifier of the resource of the assignment, quiz or discussion topic.
+	Identifier string
+	Title      string
+	// Type is one of AssignmentItem, QuizItem or DiscussionItem.
+	Type string
+	// Group is the identifier of the assignment group of the item.
+	Group          string
+	PointsPossible float64
+	Position       int
+	Published      bool
+	// OmitFromFinalGrade is true when the item is graded, but does not count towards the final grade.
+	OmitFromFinalGrade bool
+	Assignment         types.CanvasAssignment
+}
+
+// AssignmentGroups returns the assignment groups of a Canvas course, from the `course_settings/assignment_groups.xml` file, in order. Each assignment, graded quiz and graded discussion of the cartridge is linked to its group through its Canvas settings; the items whose group cannot be found are returned in an additional group with no identifier. The weights of the groups are computed according to the `group_weighting_scheme` of the course settings.
+func AssignmentGroups(cc commoncartridge.IMSCC) ([]AssignmentGroup, error) {
+	groups := make([]AssignmentGroup, 0)
+
+	var meta types.CanvasAssignmentGroups
+	if err := readXML(cc, assignmentGroupsFile, &meta); err != nil {
+		return groups, fmt.Errorf("could not read the assignment groups: %w", err)
+	}
+
+	for _, g := range meta.AssignmentGroup {
+		groups = append(groups, NewAssignmentGroup(g))
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Position < groups[j].Position
+	})
+
+	items, err := GradedItems(cc)
+	if err != nil {
+		return groups, err
+	}
+
+	ungrouped := AssignmentGroup{Items: make([]GradedItem, 0)}
+	for _, item := range items {
+		found := false
+		for i := range groups {
+			if groups[i].Identifier == item.Group {
+				groups[i].Items = append(groups[i].Items, item)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			ungrouped.Items = append(ungrouped.Items, item)
+		}
+	}
+
+	if len(ungrouped.Items) > 0 {
+		groups = append(groups, ungrouped)
+	}
+
+	weighted := false
+	if cc.HasFile(courseSettingsFile) {
+		course, err := CourseSettings(cc)
+		if err != nil {
+			return groups, err
+		}
+		weighted = course.GroupWeightingScheme == "percent"
+	}
+
+	setWeights(groups, weighted)
+
+	return groups, nil
+}
+
+// NewAssignmentGroup builds an AssignmentGroup, with no items, from its decoded settings.
+func NewAssignmentGroup(g types.CanvasAssignmentGroup) AssignmentGroup {
+	group := AssignmentGroup{
+		Identifier: g.Identifier,
+		Title:      strings.TrimSpace(g.Title),
+		Position:   parseInt(g.Position),
+		Items:      make([]GradedItem, 0),
+	}
+	group.GroupWeight, _ = strconv.ParseFloat(strings.TrimSpace(g.GroupWeight), 64)
+
+	for _, r := range g.Rules.Rule {
+		switch strings.TrimSpace(r.DropType) {
+		case "drop_lowest":
+			group.DropLowest = parseInt(r.DropCount)
+		case "drop_highest":
+			group.DropHighest = parseInt(r.DropCount)
+		}
+	}
+
+	return group
+}
+
+// setWeights computes the points and the share of the final grade of each group. Canvas scales the weights of the groups when they do not add up to 100, and ignores the groups which have no points.
+func setWeights(groups []AssignmentGroup, weighted bool) {
+	totalPoints, totalWeight := 0.0, 0.0
+	for i := range groups {
+		sort.SliceStable(groups[i].Items, func(a, b int) bool {
+			return groups[i].Items[a].Position < groups[i].Items[b].Position
+		})
+
+		groups[i].PointsPossible = 0
+		for _, item := range groups[i].Items {
+			if !item.OmitFromFinalGrade {
+				groups[i].PointsPossible += item.PointsPossible
+			}
+		}
+
+		totalPoints += groups[i].PointsPossible
+		if groups[i].PointsPossible > 0 {
+			totalWeight += groups[i].GroupWeight
+		}
+	}
+
+	for i := range groups {
+		groups[i].Weight = 0
+		switch {
+		case weighted && totalWeight > 0 && groups[i].PointsPossible > 0:
+			groups[i].Weight = groups[i].GroupWeight / totalWeight * 100
+		case !weighted && totalPoints > 0:
+			groups[i].Weight = groups[i].PointsPossible / totalPoints * 100
+		}
+	}
+}
+
+// TotalWeight returns the total of the weights of the groups, as set in the course, which should be 100 for a course weighted by group.
+func TotalWeight(groups []AssignmentGroup) float64 {
+	total := 0.0
+	for _, g := range groups {
+		total += g.GroupWeight
+	}
+
+	return total
+}
+
+// GradedItems returns the assignments, graded quizzes and graded discussions of a Canvas course, from the settings of their resources: the `assignment_settings.xml` of assignments, or the extensions of their `assignment.xml` in older exports, the `assessment_meta.xml` of quizzes and the meta document of discussion topics. Practice quizzes and ungraded discussions are left out.
+func GradedItems(cc commoncartridge.IMSCC) ([]GradedItem, error) {
+	items := make([]GradedItem, 0)
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return items, err
+	}
+
+	for _, r := range manifest.Resources.Resource {
+		for _, f := range r.File {
+			switch {
+			case path.Base(f.Href) == "assignment_settings.xml":
+				var a types.CanvasAssignment
+				if err := readXML(cc, f.Href, &a); err != nil {
+					return items, err
+				}
+				items = append(items, newGradedItem(r.Identifier, AssignmentItem, a))
+
+			case strings.HasPrefix(r.Type, "assignment_xmlv1p") && f.Href == r.Href:
+				var a types.CanvasAssignmentExtension
+				if err := readXML(cc, f.Href, &a); err != nil {
+					return items, err
+				}
+				if a.Extensions.Assignment.Identifier != "" {
+					items = append(items, newGradedItem(r.Identifier, AssignmentItem, a.Extensions.Assignment))
+				}
+
+			case path.Base(f.Href) == "assessment_meta.xml":
+				var q types.CanvasQuiz
+				if err := readXML(cc, f.Href, &q); err != nil {
+					return items, err
+				}
+				if q.Assignment.Identifier == "" {
+					continue
+				}
+
+				id := q.Identifier
+				if id == "" {
+					id = path.Dir(f.Href)
+				}
+				if q.Assignment.AssignmentGroupIdentifierref == "" {
+					q.Assignment.AssignmentGroupIdentifierref = q.AssignmentGroupIdentifierref
+				}
+				items = append(items, newGradedItem(id, QuizItem, q.Assignment))
+
+			case strings.HasSuffix(r.Type, "learning-application-resource") && path.Dir(f.Href) == "." && path.Ext(f.Href) == ".xml":
+				//-- other documents at the root of the cartridge are not discussion topics
+				var topic types.CanvasTopicMeta
+				if err := readXML(cc, f.Href, &topic); err != nil || topic.Assignment.Identifier == "" {
+					continue
+				}
+				items = append(items, newGradedItem(strings.TrimSpace(topic.TopicID), DiscussionItem, topic.Assignment))
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// newGradedItem builds a GradedItem from the settings of its assignment.
+func newGradedItem(id string, itemType string, a types.CanvasAssignment) GradedItem {
+	item := GradedItem{
+		Identifier:         id,
+		Title:              strings.TrimSpace(a.Title),
+		Type:               itemType,
+		Group:              strings.TrimSpace(a.AssignmentGroupIdentifierref),
+		Position:           parseInt(a.Position),
+		Published:          published(a.WorkflowState),
+		OmitFromFinalGrade: parseBool(a.OmitFromFinalGrade),
+		Assignment:         a,
+	}
+	item.PointsPossible, _ = strconv.ParseFloat(strings.TrimSpace(a.PointsPossible), 64)
+
+	return item
+}
+
+// published returns false when the workflow state of a Canvas object hides it from learners.
+func published(state string) bool {
+	return commoncartridge.ItemSettings{WorkflowState: strings.TrimSpace(state)}.Published()
+}
+
+// GradebookCSV writes a gradebook template for the given groups, in the CSV format imported by Canvas: a column per item, named after its title and identifier, following the columns identifying the students, then a row with the points possible of each item.
+func GradebookCSV(w io.Writer, groups []AssignmentGroup) error {
+	header := []string{"Student", "ID", "SIS User ID", "SIS Login ID", "Section"}
+	points := []string{"Points Possible", "", "", "", ""}
+
+	for _, g := range groups {
+		for _, item := range g.Items {
+			header = append(header, fmt.Sprintf("%s (%s)", item.Title, item.Identifier))
+			points = append(points, formatPoints(item.PointsPossible))
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	cw.Write(points)
+	cw.Flush()
+
+	return cw.Error()
+}
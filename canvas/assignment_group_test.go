@@ -0,0 +1,112 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/csv"
+	"path/filepath"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignmentGroups(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	groups, err := AssignmentGroups(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(groups), 2)
+	assert.Equal(t, groups[0].Title, "New Assignment Group")
+	assert.Equal(t, groups[1].Title, "Assignments")
+	assert.Equal(t, TotalWeight(groups), 0.0)
+
+	//-- the course is not weighted by group, so each group weighs its share of the points
+	assert.Equal(t, groups[0].PointsPossible, 407.0)
+	assert.Equal(t, groups[1].PointsPossible, 205.0)
+	assert.InDelta(t, groups[0].Weight+groups[1].Weight, 100.0, 0.0001)
+	assert.InDelta(t, groups[1].Weight, 205.0/612.0*100, 0.0001)
+
+	require.Equal(t, len(groups[0].Items), 20)
+	quiz := groups[0].Items[1]
+	assert.Equal(t, quiz.Identifier, "i581ee685674f4a8c9231bd228a92976a")
+	assert.Equal(t, quiz.Type, QuizItem)
+	assert.Equal(t, quiz.Title, "Multiple Attempt Quiz")
+	assert.Equal(t, quiz.PointsPossible, 10.0)
+
+	kinds := make(map[string]int)
+	for _, g := range groups {
+		for _, item := range g.Items {
+			kinds[item.Type]++
+		}
+	}
+	assert.Equal(t, kinds[DiscussionItem], 5)
+	assert.Equal(t, kinds[QuizItem], 13)
+}
+
+func TestAssignmentGroupsWeighted(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	groups, err := AssignmentGroups(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(groups), 7)
+	assert.Equal(t, TotalWeight(groups), 100.0)
+	weights := make([]float64, 0)
+	for _, g := range groups {
+		weights = append(weights, g.GroupWeight)
+	}
+	assert.Equal(t, weights, []float64{20, 20, 20, 20, 10, 10, 0})
+
+	//-- the assignments of the template have no points, so only the groups with points share the final grade
+	assert.Equal(t, groups[0].Title, "Assignments")
+	assert.Equal(t, groups[0].PointsPossible, 0.0)
+	assert.Equal(t, groups[0].Weight, 0.0)
+	assert.Equal(t, len(groups[0].Items), 15)
+	assert.False(t, groups[0].Items[0].Published)
+
+	total := 0.0
+	for _, g := range groups {
+		total += g.Weight
+	}
+	assert.InDelta(t, total, 100.0, 0.0001)
+}
+
+func TestAssignmentGroupsMissing(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	_, err := AssignmentGroups(cc)
+	assert.NotNil(t, err)
+}
+
+func TestNewAssignmentGroup(t *testing.T) {
+	var g types.CanvasAssignmentGroup
+	g.Identifier, g.Title, g.Position, g.GroupWeight = "g1", " Quizzes ", "2", "12.5"
+	g.Rules.Rule = append(g.Rules.Rule, struct {
+		Text      string `xml:",chardata"`
+		DropType  string `xml:"drop_type"`
+		DropCount string `xml:"drop_count"`
+	}{DropType: "drop_lowest", DropCount: "2"})
+
+	group := NewAssignmentGroup(g)
+	assert.Equal(t, group.Title, "Quizzes")
+	assert.Equal(t, group.Position, 2)
+	assert.Equal(t, group.GroupWeight, 12.5)
+	assert.Equal(t, group.DropLowest, 2)
+	assert.Equal(t, group.DropHighest, 0)
+}
+
+func TestGradebookCSV(t *testing.T) {
+	groups := []AssignmentGroup{
+		{Items: []GradedItem{{Identifier: "a1", Title: "Essay, final", PointsPossible: 12.5}}},
+		{Items: []GradedItem{{Identifier: "q1", Title: "Quiz", PointsPossible: 10}}},
+	}
+
+	var buf bytes.Buffer
+	require.Nil(t, GradebookCSV(&buf, groups))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.Nil(t, err)
+	require.Equal(t, len(rows), 2)
+	assert.Equal(t, rows[0], []string{"Student", "ID", "SIS User ID", "SIS Login ID", "Section", "Essay, final (a1)", "Quiz (q1)"})
+	assert.Equal(t, rows[1], []string{"Points Possible", "", "", "", "", "12.5", "10"})
+}
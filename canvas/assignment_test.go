@@ -0,0 +1,111 @@
+package canvas
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignments(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	assignments, err := Assignments(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(assignments), 14)
+	for _, a := range assignments {
+		require.NotNil(t, a.Settings, a.Identifier)
+	}
+
+	a := find(t, assignments, "i7aff7e807cbf2c3be5ca6fc0733ff0a8")
+	assert.Equal(t, a.Title, "File Type Restriction Assignment (Doc and PPT)")
+	assert.Equal(t, a.Gradable.PointsPossible, "35")
+	assert.Equal(t, a.Settings.Identifier, "i7aff7e807cbf2c3be5ca6fc0733ff0a8_canvas")
+	assert.Equal(t, a.Settings.PointsPossible, 35.0)
+	assert.Equal(t, a.Settings.AllowedExtensions, []string{"doc", "ppt"})
+	assert.Equal(t, a.Settings.SubmissionTypes, []string{"online_upload"})
+	assert.Equal(t, a.Settings.Group, "ia2ab80ff48700ca63e961581c2c49c36")
+	assert.True(t, a.Settings.Published())
+
+	a = find(t, assignments, "iaa4b4fdadec793530c31c58a249e0879")
+	assert.Equal(t, a.Settings.DueAt, time.Date(2020, 12, 26, 6, 59, 59, 0, time.UTC))
+	assert.Equal(t, a.Settings.PeerReviews.DueAt, time.Date(2020, 12, 26, 6, 59, 59, 0, time.UTC))
+	assert.True(t, a.Settings.LockAt.IsZero())
+
+	a = find(t, assignments, "ia57385f92f17f47f20554839ea2c4a34")
+	assert.False(t, a.Settings.Published())
+}
+
+func TestAssignmentsSettingsOnly(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	assignments, err := Assignments(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(assignments), 16)
+	a := find(t, assignments, "gb183644d9241af82ae2b3c219990b59a")
+	assert.Equal(t, a.XMLName.Local, "assignment")
+	assert.Equal(t, a.Title, "Module 13 Assignment")
+	assert.Equal(t, a.Text.Text, "")
+	assert.Equal(t, a.Text.Texttype, "text/html")
+	assert.Equal(t, a.Gradable.Text, "true")
+	assert.Equal(t, a.Gradable.PointsPossible, "0")
+	assert.Empty(t, a.SubmissionFormats.Format)
+	assert.Equal(t, a.Settings.SubmissionTypes, []string{"none"})
+	assert.Equal(t, a.Settings.Position, 20)
+	assert.False(t, a.Settings.Published())
+
+	for _, a := range assignments {
+		if len(a.Settings.SubmissionTypes) > 0 && a.Settings.SubmissionTypes[0] == "online_upload" {
+			require.Equal(t, len(a.SubmissionFormats.Format), 1)
+			assert.Equal(t, a.SubmissionFormats.Format[0].Type, "file")
+		}
+	}
+}
+
+func TestAssignmentsFallback(t *testing.T) {
+	name := "i7aff7e807cbf2c3be5ca6fc0733ff0a8/assignment.xml"
+	original, err := load(t, testcc.SingleTestFile).ReadFile(name)
+	require.Nil(t, err)
+
+	//-- an assignment without the Canvas extension, as other exporters write them
+	content := regexp.MustCompile(`(?s)<extensions>.*</extensions>`).ReplaceAllString(string(original), "")
+	cc := load(t, testcc.Rewrite(t, testcc.SingleTestFile, map[string]string{name: content}))
+
+	assignments, err := Assignments(cc)
+	require.Nil(t, err)
+	require.Equal(t, len(assignments), 14)
+
+	a := find(t, assignments, "i7aff7e807cbf2c3be5ca6fc0733ff0a8")
+	assert.Nil(t, a.Settings)
+	assert.Equal(t, a.Gradable.PointsPossible, "35")
+	assert.Equal(t, a.SubmissionFormats.Format[0].Type, "file")
+
+	for _, name := range []string{"py4e_export.imscc", "single-page.imscc"} {
+		cc := load(t, filepath.Join(testcc.AllTestFilesDir, name))
+		assignments, err := Assignments(cc)
+		require.Nil(t, err)
+
+		base, err := cc.Assignments()
+		require.Nil(t, err)
+		require.Equal(t, len(assignments), len(base))
+		for i, a := range assignments {
+			assert.Nil(t, a.Settings)
+			assert.Equal(t, a.Assignment, base[i])
+		}
+	}
+}
+
+func find(t *testing.T, assignments []Assignment, id string) Assignment {
+	for _, a := range assignments {
+		if a.Identifier == id {
+			return a
+		}
+	}
+
+	require.FailNow(t, "could not find the assignment", id)
+	return Assignment{}
+}
@@ -0,0 +1,297 @@
+package canvas
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// eventsFile is the document holding the events of the calendar of a Canvas course.
+var eventsFile = settingsFile("events.xml")
+
+// The types of the entries of the calendar, besides the AssignmentItem, QuizItem and DiscussionItem of the gradebook.
+const (
+	EventEntry  = "calendar_event"
+	ModuleEntry = "module"
+	CourseEntry = "course"
+)
+
+// The dates of the entries of the calendar.
+const (
+	EventDate  = "event"
+	StartDate  = "start"
+	EndDate    = "end"
+	DueDate    = "due"
+	UnlockDate = "unlock"
+	LockDate   = "lock"
+)
+
+// summaryPrefixes are the prefixes of the summaries of the entries of the calendar, by date.
+var summaryPrefixes = map[string]string{
+	StartDate:  "Starts: ",
+	EndDate:    "Ends: ",
+	DueDate:    "Due: ",
+	UnlockDate: "Available: ",
+	LockDate:   "Closes: ",
+}
+
+// icsTime and icsDate are the formats of the dates and times of iCalendar, in UTC.
+const (
+	icsTime = "20060102T150405Z"
+	icsDate = "20060102"
+)
+
+// CalendarEntry is a dated entity of a course: an event of its calendar, or a date of the course, of a module, or of an assignment, quiz or graded discussion.
+type CalendarEntry struct {
+	// Type is one of EventEntry, CourseEntry, ModuleEntry, AssignmentItem, QuizItem or DiscussionItem.
+	Type string
+	// Date is what happens at Start, one of EventDate, StartDate, EndDate, DueDate, UnlockDate or LockDate.
+	Date string
+	// Identifier is the identifier of the event, course or module, or of the resource of the assignment, quiz or discussion topic.
+	Identifier string
+	// Path is the path of the main file of the resource in the archive, if any.
+	Path  string
+	Title string
+	// Description is HTML.
+	Description string
+	Location    string
+	Start       time.Time
+	// End is the zero time for the dates which are not events.
+	End    time.Time
+	AllDay bool
+	// Recurrence is the RFC 5545 recurrence rule of a repeating event, e.g. `FREQ=WEEKLY;COUNT=10`.
+	Recurrence string
+}
+
+// Summary returns the title of the entry, prefixed by what happens at its date, e.g. `Due: Essay`.
+func (e CalendarEntry) Summary() string {
+	return summaryPrefixes[e.Date] + e.Title
+}
+
+// Calendar returns all the dated entities of a Canvas course, sorted by date: the start and conclusion of the course, the events of `course_settings/events.xml`, the unlock dates of modules, and the due, unlock and lock dates of assignments, quizzes and graded discussions. Modules, assignments, quizzes and discussions which are hidden from learners are left out.
+func Calendar(cc commoncartridge.IMSCC) ([]CalendarEntry, error) {
+	entries := make([]CalendarEntry, 0)
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return entries, err
+	}
+
+	hrefs := make(map[string]string)
+	for _, r := range manifest.Resources.Resource {
+		hrefs[r.Identifier] = commoncartridge.ResourceHref(r)
+	}
+
+	add := func(entry CalendarEntry) {
+		if entry.Start.IsZero() {
+			return
+		}
+		if entry.Type != CourseEntry {
+			entry.Path = hrefs[entry.Identifier]
+		}
+		entries = append(entries, entry)
+	}
+
+	if cc.HasFile(courseSettingsFile) {
+		course, err := CourseSettings(cc)
+		if err != nil {
+			return entries, err
+		}
+		add(CalendarEntry{Type: CourseEntry, Date: StartDate, Identifier: course.Identifier, Title: course.Title, Start: course.StartAt})
+		add(CalendarEntry{Type: CourseEntry, Date: EndDate, Identifier: course.Identifier, Title: course.Title, Start: course.ConcludeAt})
+	}
+
+	if cc.HasFile(eventsFile) {
+		var meta types.CanvasEvents
+		if err := readXML(cc, eventsFile, &meta); err != nil {
+			return entries, fmt.Errorf("could not read the calendar events: %w", err)
+		}
+		for _, e := range meta.Event {
+			add(NewCalendarEvent(e))
+		}
+	}
+
+	if cc.HasFile(moduleMetaFile) {
+		modules, err := Modules(cc)
+		if err != nil {
+			return entries, err
+		}
+		for _, m := range modules {
+			if m.Settings.Published() {
+				add(CalendarEntry{Type: ModuleEntry, Date: UnlockDate, Identifier: m.Identifier, Title: m.Title, Start: m.Settings.UnlockAt})
+			}
+		}
+	}
+
+	items, err := GradedItems(cc)
+	if err != nil {
+		return entries, err
+	}
+	for _, item := range items {
+		if item.Type == QuizItem || !item.Published {
+			continue
+		}
+
+		s := NewAssignmentSettings(item.Assignment)
+		add(dueEntry(CalendarEntry{Type: item.Type, Identifier: item.Identifier, Title: item.Title, Start: s.DueAt}, s))
+		for date, t := range map[string]time.Time{UnlockDate: s.UnlockAt, LockDate: s.LockAt} {
+			add(CalendarEntry{Type: item.Type, Date: date, Identifier: item.Identifier, Title: item.Title, Start: t})
+		}
+	}
+
+	//-- practice quizzes and surveys have dates too, which are not part of the gradebook
+	quizzes, err := Quizzes(cc)
+	if err != nil {
+		return entries, err
+	}
+	for _, q := range quizzes {
+		if q.Settings == nil || !q.Settings.Published() {
+			continue
+		}
+
+		add(dueEntry(CalendarEntry{Type: QuizItem, Identifier: q.Identifier, Title: q.Settings.Title, Start: q.Settings.DueAt}, q.Settings.Assignment))
+		for date, t := range map[string]time.Time{UnlockDate: q.Settings.UnlockAt, LockDate: q.Settings.LockAt} {
+			add(CalendarEntry{Type: QuizItem, Date: date, Identifier: q.Identifier, Title: q.Settings.Title, Start: t})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Start.Equal(entries[j].Start) {
+			return entries[i].Start.Before(entries[j].Start)
+		}
+		if entries[i].Title != entries[j].Title {
+			return entries[i].Title < entries[j].Title
+		}
+		return entries[i].Date < entries[j].Date
+	})
+
+	return entries, nil
+}
+
+// dueEntry returns the entry of the due date of an assignment, which lasts the whole day on which it is due when the assignment is an all-day one.
+func dueEntry(entry CalendarEntry, s *AssignmentSettings) CalendarEntry {
+	entry.Date = DueDate
+	if s != nil && s.AllDay && !s.AllDayDate.IsZero() && !entry.Start.IsZero() {
+		entry.Start, entry.AllDay = s.AllDayDate, true
+	}
+
+	return entry
+}
+
+// NewCalendarEvent returns the entry of an event of the calendar from its decoded document. All-day events start at the beginning of their day.
+func NewCalendarEvent(e types.CanvasEvent) CalendarEntry {
+	entry := CalendarEntry{
+		Type:        EventEntry,
+		Date:        EventDate,
+		Identifier:  e.Identifier,
+		Title:       strings.TrimSpace(e.Title),
+		Description: strings.TrimSpace(e.Description),
+		Start:       parseTime(e.StartAt),
+		End:         parseTime(e.EndAt),
+		AllDay:      parseBool(e.AllDay),
+		Recurrence:  strings.TrimPrefix(strings.TrimSpace(e.Rrule), "RRULE:"),
+	}
+
+	location := make([]string, 0)
+	for _, l := range []string{e.LocationName, e.LocationAddress} {
+		if l = strings.TrimSpace(l); l != "" {
+			location = append(location, l)
+		}
+	}
+	entry.Location = strings.Join(location, ", ")
+
+	if day := parseTime(e.AllDayDate); entry.AllDay && !day.IsZero() {
+		entry.Start, entry.End = day, time.Time{}
+	}
+
+	return entry
+}
+
+// CalendarICS writes the entries as an RFC 5545 iCalendar file with the given name, to which a calendar application can subscribe. Entries which are not events are written as events without duration, and all-day entries as events of a whole day.
+func CalendarICS(w io.Writer, name string, entries []CalendarEntry) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format(icsTime)
+
+	writeLine := func(line string) {
+		bw.WriteString(foldLine(line) + "\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//commonsyllabi//commoncartridge//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("METHOD:PUBLISH")
+	if name != "" {
+		writeLine("X-WR-CALNAME:" + escapeText(name))
+	}
+
+	for _, e := range entries {
+		writeLine("BEGIN:VEVENT")
+		writeLine(fmt.Sprintf("UID:%s-%s@commoncartridge", e.Identifier, e.Date))
+		writeLine("DTSTAMP:" + stamp)
+
+		if e.AllDay {
+			start := e.Start.UTC()
+			end := start.AddDate(0, 0, 1)
+			if !e.End.IsZero() && e.End.After(start) {
+				end = e.End.UTC().AddDate(0, 0, 1)
+			}
+			writeLine("DTSTART;VALUE=DATE:" + start.Format(icsDate))
+			writeLine("DTEND;VALUE=DATE:" + end.Format(icsDate))
+		} else {
+			writeLine("DTSTART:" + e.Start.UTC().Format(icsTime))
+			if !e.End.IsZero() && e.End.After(e.Start) {
+				writeLine("DTEND:" + e.End.UTC().Format(icsTime))
+			}
+		}
+
+		if e.Recurrence != "" {
+			writeLine("RRULE:" + e.Recurrence)
+		}
+		writeLine("SUMMARY:" + escapeText(e.Summary()))
+		if description := strings.TrimSpace(commoncartridge.PlainText(e.Description)); description != "" {
+			writeLine("DESCRIPTION:" + escapeText(description))
+		}
+		if e.Location != "" {
+			writeLine("LOCATION:" + escapeText(e.Location))
+		}
+		writeLine("CATEGORIES:" + escapeText(e.Type))
+		writeLine("END:VEVENT")
+	}
+
+	writeLine("END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// escapeText escapes the backslashes, semicolons, commas and newlines of an iCalendar text value.
+func escapeText(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+	return strings.ReplaceAll(s, "\r", "")
+}
+
+// foldLine splits a content line longer than 75 octets into lines starting with a space, without splitting its UTF-8 characters.
+func foldLine(line string) string {
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+		b.WriteString(line[:i] + "\r\n ")
+		line = line[i:]
+		//-- the leading space counts towards the length of the following lines
+		limit = 74
+	}
+	b.WriteString(line)
+
+	return b.String()
+}
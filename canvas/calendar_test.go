@@ -0,0 +1,145 @@
+package canvas
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	entries, err := Calendar(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(entries), 3)
+	assert.Equal(t, entries[0], CalendarEntry{
+		Type:       EventEntry,
+		Date:       EventDate,
+		Identifier: "ie4a6774e3372ecfb4d6dfb7f71ff9520",
+		Title:      "Example Event",
+		Start:      time.Date(2014, 4, 22, 18, 0, 0, 0, time.UTC),
+		End:        time.Date(2014, 4, 22, 19, 0, 0, 0, time.UTC),
+	})
+
+	assert.Equal(t, entries[1].Type, CourseEntry)
+	assert.Equal(t, entries[1].Date, StartDate)
+	assert.Equal(t, entries[1].Summary(), "Starts: Loaded Course")
+	assert.Equal(t, entries[1].Path, "")
+
+	assert.Equal(t, entries[2].Type, AssignmentItem)
+	assert.Equal(t, entries[2].Identifier, "iaa4b4fdadec793530c31c58a249e0879")
+	assert.Equal(t, entries[2].Path, "iaa4b4fdadec793530c31c58a249e0879/assignment.xml")
+	assert.Equal(t, entries[2].Summary(), "Due: Due Date Assignment")
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	entries, err = Calendar(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(entries), 6)
+	assert.Equal(t, entries[0].Type, ModuleEntry)
+	assert.Equal(t, entries[0].Date, UnlockDate)
+	assert.Equal(t, entries[0].Title, "Module 2")
+	assert.Equal(t, entries[5].Type, DiscussionItem)
+	assert.Equal(t, entries[5].Path, "g90474b4e3752c93f75db81812c533c40.xml")
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	entries, err = Calendar(cc)
+	require.Nil(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestCalendarQuizzes(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "sample-public-sandbox-course-export.imscc"))
+	entries, err := Calendar(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(entries), 4)
+	for _, e := range entries {
+		assert.Equal(t, e.Type, QuizItem)
+		assert.Equal(t, e.Date, DueDate)
+	}
+
+	//-- a practice quiz, which is not an all-day one
+	assert.Equal(t, entries[0].Start, time.Date(2015, 5, 1, 5, 59, 0, 0, time.UTC))
+	assert.False(t, entries[0].AllDay)
+
+	//-- all-day quizzes are due on the day of the course, rather than the one of UTC
+	assert.Equal(t, entries[1].Start, time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC))
+	assert.True(t, entries[1].AllDay)
+}
+
+func TestNewCalendarEvent(t *testing.T) {
+	entry := NewCalendarEvent(types.CanvasEvent{
+		Identifier:      "e1",
+		Title:           " Office hours ",
+		StartAt:         "2022-09-01T00:00:00",
+		AllDay:          "true",
+		AllDayDate:      "2022-08-31",
+		LocationName:    "Room 101",
+		LocationAddress: "1 Main St",
+		Rrule:           "RRULE:FREQ=WEEKLY;COUNT=10",
+	})
+
+	assert.Equal(t, entry.Title, "Office hours")
+	assert.Equal(t, entry.Start, time.Date(2022, 8, 31, 0, 0, 0, 0, time.UTC))
+	assert.True(t, entry.AllDay)
+	assert.Equal(t, entry.Location, "Room 101, 1 Main St")
+	assert.Equal(t, entry.Recurrence, "FREQ=WEEKLY;COUNT=10")
+}
+
+func TestCalendarICS(t *testing.T) {
+	entries := []CalendarEntry{
+		{
+			Type:        EventEntry,
+			Date:        EventDate,
+			Identifier:  "e1",
+			Title:       "Lecture; part 1, part 2",
+			Description: "<p>Bring a laptop</p><p>" + strings.Repeat("é", 60) + "</p>",
+			Location:    "Room 101",
+			Start:       time.Date(2022, 9, 1, 14, 0, 0, 0, time.UTC),
+			End:         time.Date(2022, 9, 1, 15, 30, 0, 0, time.UTC),
+			Recurrence:  "FREQ=WEEKLY;COUNT=10",
+		},
+		{Type: AssignmentItem, Date: DueDate, Identifier: "a1", Title: "Essay", Start: time.Date(2022, 9, 9, 0, 0, 0, 0, time.UTC), AllDay: true},
+	}
+
+	var b bytes.Buffer
+	require.Nil(t, CalendarICS(&b, "Course, 2022", entries))
+	ics := b.String()
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "X-WR-CALNAME:Course\\, 2022\r\n")
+	assert.Contains(t, ics, "UID:e1-event@commoncartridge\r\n")
+	assert.Contains(t, ics, "DTSTART:20220901T140000Z\r\nDTEND:20220901T153000Z\r\nRRULE:FREQ=WEEKLY;COUNT=10\r\n")
+	assert.Contains(t, ics, "SUMMARY:Lecture\\; part 1\\, part 2\r\n")
+	assert.Contains(t, ics, "DESCRIPTION:Bring a laptop\\n")
+	assert.Contains(t, ics, "LOCATION:Room 101\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20220909\r\nDTEND;VALUE=DATE:20220910\r\nSUMMARY:Due: Essay\r\n")
+	assert.Contains(t, ics, "CATEGORIES:assignment\r\n")
+
+	for _, line := range strings.Split(ics, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	assert.Equal(t, foldLine("SUMMARY:short"), "SUMMARY:short")
+
+	line := "DESCRIPTION:" + strings.Repeat("a", 200)
+	folded := foldLine(line)
+	assert.Equal(t, strings.ReplaceAll(folded, "\r\n ", ""), line)
+
+	//-- multi-byte characters are not split
+	line = "DESCRIPTION:" + strings.Repeat("é", 100)
+	for _, l := range strings.Split(foldLine(line), "\r\n ") {
+		assert.True(t, strings.HasSuffix(l, "é"), l)
+	}
+}
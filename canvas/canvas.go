@@ -0,0 +1,127 @@
+// The canvas package decodes the files that Canvas adds to the cartridges it exports, in the `course_settings` folder and next to the resources, which hold the course data that the IMSCC standard cannot carry. Importing the package registers its Extension, which completes the metadata of Canvas cartridges.
+package canvas
+
+import (
+	"encoding/xml"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge"
+)
+
+const (
+	// SettingsDir is the folder in which Canvas exports the settings of the course.
+	SettingsDir = "course_settings"
+	// exportFlag is the file which Canvas adds to all of its exports.
+	exportFlag = "course_settings/canvas_export.txt"
+)
+
+func init() {
+	commoncartridge.RegisterExtension(Extension{})
+}
+
+// Extension is the commoncartridge.Extension of Canvas exports.
+type Extension struct{}
+
+// Name returns `canvas`.
+func (Extension) Name() string {
+	return "canvas"
+}
+
+// Detect returns true when the cartridge has the `course_settings/canvas_export.txt` file, or the course settings, of a Canvas export.
+func (Extension) Detect(cc commoncartridge.IMSCC) bool {
+	return cc.HasFile(exportFlag) || cc.HasFile(courseSettingsFile)
+}
+
+// Supports returns true for the vendor extensions of Canvas which this package decodes: its namespace and link tokens, the course settings it reads, and the settings of assignments and quizzes exported next to their resources.
+func (Extension) Supports(v commoncartridge.VendorExtension) bool {
+	switch v.Kind {
+	case commoncartridge.NamespaceExtension:
+		return v.Name == canvasNamespace
+	case commoncartridge.TokenExtension:
+		return v.Name == WikiReference || v.Name == ObjectReference || v.Name == CourseReference
+	case commoncartridge.FileExtension:
+		for _, name := range []string{
+			exportFlag, courseSettingsFile, moduleMetaFile, assignmentGroupsFile, rubricsFile, filesMetaFile, mediaTracksFile, learningOutcomesFile, eventsFile,
+			"*/" + quizMetaFile, "*/assignment_settings.xml", wikiDir + "/",
+		} {
+			if v.Name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ExtendMetadata adds the course code, start and conclusion dates of the course settings to the metadata, when they are set.
+func (Extension) ExtendMetadata(cc commoncartridge.IMSCC, meta *commoncartridge.Metadata) error {
+	if !cc.HasFile(courseSettingsFile) {
+		return nil
+	}
+
+	settings, err := CourseSettings(cc)
+	if err != nil {
+		return err
+	}
+
+	meta.CourseCode = settings.CourseCode
+	meta.StartAt = strings.TrimSpace(settings.Raw.StartAt)
+	meta.ConcludeAt = strings.TrimSpace(settings.Raw.ConcludeAt)
+
+	return nil
+}
+
+// readXML decodes the file of the cartridge at the given path.
+func readXML(cc commoncartridge.IMSCC, name string, v interface{}) error {
+	bytesArray, err := cc.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	return xml.Unmarshal(bytesArray, v)
+}
+
+// settingsFile returns the path of a file of the `course_settings` folder.
+func settingsFile(name string) string {
+	return path.Join(SettingsDir, name)
+}
+
+// timeLayouts are the formats of the dates found in Canvas exports, which are in UTC when they have no time zone.
+var timeLayouts = []string{
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02",
+}
+
+// parseTime returns the time of a Canvas date, or the zero time when it is empty or invalid.
+func parseTime(s string) time.Time {
+	t, _, _ := parseLayout(s)
+	return t
+}
+
+// parseLayout returns the time of a date, along with its layout, or false when it is not a date.
+func parseLayout(s string) (time.Time, string, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, layout, true
+		}
+	}
+
+	return time.Time{}, "", false
+}
+
+// parseBool returns true when a Canvas setting is `true`.
+func parseBool(s string) bool {
+	return strings.TrimSpace(s) == "true"
+}
+
+// parseInt returns the integer of a Canvas setting, or 0 when it is empty or invalid.
+func parseInt(s string) int {
+	i, _ := strconv.Atoi(strings.TrimSpace(s))
+	return i
+}
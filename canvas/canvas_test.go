@@ -0,0 +1,77 @@
+package canvas
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	require.Equal(t, len(cc.Extensions()), 1)
+	assert.Equal(t, cc.Extensions()[0].Name(), "canvas")
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	assert.Empty(t, cc.Extensions())
+}
+
+func TestExtendMetadata(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	serialized, err := cc.Metadata()
+	require.Nil(t, err)
+
+	var meta commoncartridge.Metadata
+	require.Nil(t, json.Unmarshal([]byte(serialized), &meta))
+	assert.Equal(t, meta.Title, "Loaded Course")
+	assert.Equal(t, meta.CourseCode, "Loaded")
+	assert.Equal(t, meta.StartAt, "2014-08-13T19:55:00")
+	assert.Equal(t, meta.ConcludeAt, "")
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	serialized, err = cc.Metadata()
+	require.Nil(t, err)
+	assert.NotContains(t, serialized, "CourseCode")
+}
+
+func TestSupports(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	producer, err := cc.Producer()
+	require.Nil(t, err)
+	assert.Equal(t, producer.Name, commoncartridge.CanvasProducer)
+
+	unsupported := make([]string, 0)
+	for _, v := range producer.Extensions {
+		if !v.Supported {
+			unsupported = append(unsupported, v.Name)
+		}
+	}
+	assert.Equal(t, unsupported, []string{"course_settings/grading_standards.xml"})
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	producer, err = cc.Producer()
+	require.Nil(t, err)
+	for _, v := range producer.Extensions {
+		assert.True(t, v.Supported, v.Name)
+	}
+
+	assert.False(t, Extension{}.Supports(commoncartridge.VendorExtension{Producer: commoncartridge.CanvasProducer, Kind: commoncartridge.TokenExtension, Name: "$CANVAS_COLLABORATION_REFERENCE$"}))
+}
+
+func TestParseTime(t *testing.T) {
+	assert.Equal(t, parseTime("2014-08-13T19:55:00"), time.Date(2014, 8, 13, 19, 55, 0, 0, time.UTC))
+	assert.Equal(t, parseTime(" 2021-01-25T04:59:59Z "), time.Date(2021, 1, 25, 4, 59, 59, 0, time.UTC))
+	assert.True(t, parseTime("").IsZero())
+	assert.True(t, parseTime("next week").IsZero())
+}
+
+func load(t *testing.T, p string) commoncartridge.IMSCC {
+	cc, err := commoncartridge.Load(p)
+	require.Nil(t, err)
+	return cc
+}
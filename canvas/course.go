@@ -0,0 +1,172 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// courseSettingsFile is the document holding the settings of a Canvas course.
+var courseSettingsFile = settingsFile("course_settings.xml")
+
+// Course is a user-friendly representation of the `course_settings.xml` of a Canvas export.
+type Course struct {
+	Identifier string
+	Title      string
+	CourseCode string
+	// StartAt and ConcludeAt are the dates of the course, which are zero when they are not set.
+	StartAt    time.Time
+	ConcludeAt time.Time
+	// DefaultView is the home page of the course, e.g. `wiki`, `modules` or `syllabus`.
+	DefaultView string
+	License     string
+	Locale      string
+	// GroupWeightingScheme is `percent` when the final grade is computed from the weights of the assignment groups, and `equal` otherwise.
+	GroupWeightingScheme string
+	// GradingStandard is the identifier of the grading scheme of the course, if it is enabled.
+	GradingStandard string
+	StorageQuota    int64
+	// Tabs are the items of the course navigation, in order.
+	Tabs []Tab
+	// Flags are all the other settings which are either `true` or `false`, by name, e.g. `is_public`. Nested settings are named after their parent, e.g. `default_post_policy.post_manually`.
+	Flags map[string]bool
+	// Settings are all the other settings, by name.
+	Settings map[string]string
+	// Raw is the decoded document.
+	Raw types.CanvasCourse
+}
+
+// Tab is an item of the navigation of a Canvas course.
+type Tab struct {
+	// ID is the number of a Canvas tool, e.g. `0` for the home page, or `context_external_tool_<id>` for an LTI tool.
+	ID string
+	// Name is the name of the Canvas tool, e.g. `home`, or `external_tool`.
+	Name string
+	// ExternalTool is the identifier of the LTI tool of the tab, if any.
+	ExternalTool string
+	Hidden       bool
+}
+
+// tabNames are the names of the tools of Canvas, by tab number.
+var tabNames = map[string]string{
+	"0":  "home",
+	"1":  "syllabus",
+	"2":  "pages",
+	"3":  "assignments",
+	"4":  "quizzes",
+	"5":  "grades",
+	"6":  "people",
+	"7":  "groups",
+	"8":  "discussions",
+	"10": "modules",
+	"11": "files",
+	"12": "conferences",
+	"13": "settings",
+	"14": "announcements",
+	"15": "outcomes",
+	"16": "collaborations",
+	"17": "collaborations",
+	"18": "rubrics",
+	"19": "schedule",
+	"20": "course_pacing",
+}
+
+// externalToolPrefix starts the identifier of the tabs of LTI tools.
+const externalToolPrefix = "context_external_tool_"
+
+// CourseSettings returns the settings of a Canvas course, from the `course_settings/course_settings.xml` file of the cartridge.
+func CourseSettings(cc commoncartridge.IMSCC) (Course, error) {
+	var course types.CanvasCourse
+	if err := readXML(cc, courseSettingsFile, &course); err != nil {
+		return Course{}, fmt.Errorf("could not read the course settings: %w", err)
+	}
+
+	return NewCourse(course)
+}
+
+// NewCourse builds the Course of a decoded `course_settings.xml` document. It returns an error when the tab configuration is not valid JSON.
+func NewCourse(course types.CanvasCourse) (Course, error) {
+	c := Course{
+		Identifier:           course.Identifier,
+		Title:                strings.TrimSpace(course.Title),
+		CourseCode:           strings.TrimSpace(course.CourseCode),
+		StartAt:              parseTime(course.StartAt),
+		ConcludeAt:           parseTime(course.ConcludeAt),
+		DefaultView:          strings.TrimSpace(course.DefaultView),
+		License:              strings.TrimSpace(course.License),
+		Locale:               strings.TrimSpace(course.Locale),
+		GroupWeightingScheme: strings.TrimSpace(course.GroupWeightingScheme),
+		Tabs:                 make([]Tab, 0),
+		Flags:                make(map[string]bool),
+		Settings:             make(map[string]string),
+		Raw:                  course,
+	}
+
+	if parseBool(course.GradingStandardEnabled) {
+		c.GradingStandard = strings.TrimSpace(course.GradingStandardIdentifierRef)
+	}
+	c.StorageQuota, _ = strconv.ParseInt(strings.TrimSpace(course.StorageQuota), 10, 64)
+
+	addSettings(c, "", course.Settings)
+
+	tabs, err := parseTabs(course.TabConfiguration)
+	if err != nil {
+		return c, err
+	}
+	c.Tabs = tabs
+
+	return c, nil
+}
+
+// addSettings adds the given settings, and the ones they contain, to the flags or the settings of the course, prefixing their names.
+func addSettings(c Course, prefix string, elements []types.CanvasSetting) {
+	for _, s := range elements {
+		name := prefix + s.XMLName.Local
+		if len(s.Settings) > 0 {
+			addSettings(c, name+".", s.Settings)
+			continue
+		}
+
+		value := strings.TrimSpace(s.Value)
+		switch value {
+		case "true", "false":
+			c.Flags[name] = value == "true"
+		default:
+			c.Settings[name] = value
+		}
+	}
+}
+
+// parseTabs decodes the JSON tab configuration of a course, in which the ids are either numbers or strings.
+func parseTabs(configuration string) ([]Tab, error) {
+	tabs := make([]Tab, 0)
+	if strings.TrimSpace(configuration) == "" {
+		return tabs, nil
+	}
+
+	var raw []struct {
+		ID     json.RawMessage `json:"id"`
+		Hidden bool            `json:"hidden"`
+	}
+	if err := json.Unmarshal([]byte(configuration), &raw); err != nil {
+		return tabs, fmt.Errorf("could not decode the tab configuration: %w", err)
+	}
+
+	for _, r := range raw {
+		tab := Tab{ID: strings.Trim(string(r.ID), `"`), Hidden: r.Hidden}
+		tab.Name = tabNames[tab.ID]
+		if strings.HasPrefix(tab.ID, externalToolPrefix) {
+			tab.Name = "external_tool"
+			tab.ExternalTool = strings.TrimPrefix(tab.ID, externalToolPrefix)
+		}
+
+		tabs = append(tabs, tab)
+	}
+
+	return tabs, nil
+}
@@ -0,0 +1,61 @@
+package canvas
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCourseSettings(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	course, err := CourseSettings(cc)
+	require.Nil(t, err)
+
+	assert.Equal(t, course.Identifier, "i4874e552ee3da37b383af53dd9429827")
+	assert.Equal(t, course.Title, "Loaded Course")
+	assert.Equal(t, course.CourseCode, "Loaded")
+	assert.Equal(t, course.StartAt, time.Date(2014, 8, 13, 19, 55, 0, 0, time.UTC))
+	assert.True(t, course.ConcludeAt.IsZero())
+	assert.Equal(t, course.DefaultView, "wiki")
+	assert.Equal(t, course.License, "private")
+	assert.Equal(t, course.GroupWeightingScheme, "equal")
+	assert.Equal(t, course.GradingStandard, "i5ab834e212a4c041da8e38505381d8d0")
+	assert.Equal(t, course.StorageQuota, int64(524288000))
+	assert.Empty(t, course.Tabs)
+	assert.Equal(t, course.Flags["allow_student_organized_groups"], true)
+	assert.Equal(t, course.Flags["is_public"], false)
+	assert.Equal(t, course.Settings["default_wiki_editing_roles"], "teachers")
+}
+
+func TestCourseSettingsTabs(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	course, err := CourseSettings(cc)
+	require.Nil(t, err)
+
+	assert.Equal(t, course.CourseCode, "ZZ-TPLT-TUH24413-C509-060B-202114")
+	assert.True(t, course.StartAt.IsZero())
+	assert.Equal(t, course.GroupWeightingScheme, "percent")
+	assert.Equal(t, course.GradingStandard, "")
+	assert.Equal(t, course.Flags["default_post_policy.post_manually"], false)
+	assert.Equal(t, course.Settings["home_page_announcement_limit"], "2")
+
+	require.Equal(t, len(course.Tabs), 39)
+	assert.Equal(t, course.Tabs[0], Tab{ID: "0", Name: "home"})
+	assert.Equal(t, course.Tabs[1], Tab{ID: "14", Name: "announcements"})
+	assert.Equal(t, course.Tabs[3], Tab{ID: "context_external_tool_g5e8cec8d24182ed707831e627f13e2cf", Name: "external_tool", ExternalTool: "g5e8cec8d24182ed707831e627f13e2cf"})
+	assert.True(t, course.Tabs[8].Hidden)
+}
+
+func TestCourseSettingsMissing(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	_, err := CourseSettings(cc)
+	assert.NotNil(t, err)
+
+	_, err = NewCourse(types.CanvasCourse{TabConfiguration: "[{"})
+	assert.NotNil(t, err)
+}
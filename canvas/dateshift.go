@@ -0,0 +1,227 @@
+package canvas
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge"
+)
+
+// canvasNamespace is the namespace of the documents in which Canvas exports the settings of a course.
+const canvasNamespace = "http://canvas.instructure.com/xsd/cccv1p0"
+
+// dateElements are the elements of the Canvas documents which hold the dates of a course, of its modules, events, files, assignments, quizzes and discussion topics.
+var dateElements = []string{
+	"start_at", "end_at", "conclude_at",
+	"due_at", "unlock_at", "lock_at", "all_day_date",
+	"peer_reviews_due_at", "show_correct_answers_at", "hide_correct_answers_at",
+	"delayed_post_at", "todo_date",
+}
+
+var (
+	datePattern         = regexp.MustCompile(`<(` + strings.Join(dateElements, "|") + `)>([^<]+)</(\w+)>`)
+	lomDatePattern      = regexp.MustCompile(`<((?:\w+:)?dateTime)>([^<]+)</((?:\w+:)?dateTime)>`)
+	identifierAttribute = regexp.MustCompile(`\bidentifier\s*=\s*"([^"]*)"`)
+	// untilPattern matches the end date of the recurrence rule of an event, e.g. `<rrule>FREQ=WEEKLY;UNTIL=20140601T000000Z</rrule>`.
+	untilPattern = regexp.MustCompile(`<(rrule)>[^<]*?\bUNTIL=([0-9TZ]+)[^<]*</(\w+)>`)
+)
+
+// untilLayouts are the formats of the end date of an RFC 5545 recurrence rule: a UTC or floating date and time, or a date.
+var untilLayouts = []string{"20060102T150405Z", "20060102T150405", "20060102"}
+
+// DateShift describes how to move the dates of a course to a new term.
+type DateShift struct {
+	// Offset is added to every date, unless NewStart is set.
+	Offset time.Duration
+	// OldStart and NewStart are the starts of the old and new terms: every date is moved by the number of days between them, keeping its time of day. OldStart defaults to the start of the course in its settings.
+	OldStart time.Time
+	NewStart time.Time
+	// SkipWeekends moves the dates which land on a Saturday or a Sunday to the following Monday, keeping their time of day.
+	SkipWeekends bool
+	// Location is the time zone in which weekends are determined, UTC when nil.
+	Location *time.Location
+}
+
+// Shift returns the given date moved by the offset of the shift, skipping weekends if needed.
+func (s DateShift) Shift(t time.Time) time.Time {
+	if !s.NewStart.IsZero() {
+		days := day(s.NewStart).Sub(day(s.OldStart)).Hours() / 24
+		t = t.AddDate(0, 0, int(math.Round(days)))
+	} else {
+		t = t.Add(s.Offset)
+	}
+
+	if s.SkipWeekends {
+		location := s.Location
+		if location == nil {
+			location = time.UTC
+		}
+
+		switch t.In(location).Weekday() {
+		case time.Saturday:
+			t = t.AddDate(0, 0, 2)
+		case time.Sunday:
+			t = t.AddDate(0, 0, 1)
+		}
+	}
+
+	return t
+}
+
+// day returns the midnight of the date of t, in UTC.
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DateChange is a date of the cartridge which has been shifted.
+type DateChange struct {
+	// Path is the path of the file holding the date in the archive.
+	Path string
+	// Element is the name of the element holding the date, e.g. `due_at`, or `rrule` for the end of the recurrence rule of an event.
+	Element string
+	// Identifier is the identifier of the closest element before the date which has one, such as the assignment, quiz, module or event the date belongs to.
+	Identifier string
+	Old        time.Time
+	New        time.Time
+}
+
+// ShiftDates writes to w a copy of the cartridge in which the dates of the course, its modules, events, files, assignments, quizzes and discussion topics, the end of the recurrence rules of events, as well as the LOM dates of the manifest, are moved according to the shift. The dates are rewritten in their original format, and the other files are copied as is. It returns the changes made, in the order of the archive.
+func ShiftDates(cc commoncartridge.IMSCC, w io.Writer, shift DateShift) ([]DateChange, error) {
+	changes := make([]DateChange, 0)
+
+	if !shift.NewStart.IsZero() && shift.OldStart.IsZero() {
+		var course Course
+		if cc.HasFile(courseSettingsFile) {
+			var err error
+			if course, err = CourseSettings(cc); err != nil {
+				return changes, fmt.Errorf("could not read the start date of the course: %w", err)
+			}
+		}
+		if course.StartAt.IsZero() {
+			return changes, fmt.Errorf("the start of the old term is not set, and the course has no start date")
+		}
+		shift.OldStart = course.StartAt
+	}
+
+	manifest := cc.ManifestPath()
+	zw := zip.NewWriter(w)
+	for _, f := range cc.Reader.File {
+		pattern := datePattern
+		switch {
+		case f.Name == manifest:
+			pattern = lomDatePattern
+		case path.Ext(f.Name) != ".xml":
+			if err := zw.Copy(f); err != nil {
+				return changes, err
+			}
+			continue
+		}
+
+		bytesArray, err := cc.ReadFile(f.Name)
+		if err != nil {
+			return changes, err
+		}
+
+		content := string(bytesArray)
+		if pattern == datePattern && !strings.Contains(content, canvasNamespace) {
+			if err := zw.Copy(f); err != nil {
+				return changes, err
+			}
+			continue
+		}
+
+		shifted, fileChanges := shiftContent(content, pattern, shift)
+		if pattern == datePattern {
+			var untilChanges []DateChange
+			shifted, untilChanges = shiftContent(shifted, untilPattern, shift)
+			fileChanges = append(fileChanges, untilChanges...)
+		}
+		for i := range fileChanges {
+			fileChanges[i].Path = f.Name
+		}
+		changes = append(changes, fileChanges...)
+
+		if len(fileChanges) == 0 {
+			if err := zw.Copy(f); err != nil {
+				return changes, err
+			}
+			continue
+		}
+
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: zip.Deflate, Modified: f.Modified})
+		if err != nil {
+			return changes, err
+		}
+		if _, err := io.WriteString(fw, shifted); err != nil {
+			return changes, err
+		}
+	}
+
+	return changes, zw.Close()
+}
+
+// shiftContent returns the content in which the dates matched by the pattern are shifted, along with the changes made.
+func shiftContent(content string, pattern *regexp.Regexp, shift DateShift) (string, []DateChange) {
+	changes := make([]DateChange, 0)
+
+	var b strings.Builder
+	last := 0
+	for _, m := range pattern.FindAllStringSubmatchIndex(content, -1) {
+		element, value, closing := content[m[2]:m[3]], content[m[4]:m[5]], content[m[6]:m[7]]
+		if element != closing {
+			continue
+		}
+
+		old, layout, ok := parseLayout(value)
+		if pattern == untilPattern {
+			old, layout, ok = parseUntil(value)
+		}
+		if !ok {
+			continue
+		}
+
+		change := DateChange{Element: element, Old: old, New: shift.Shift(old)}
+		if ids := identifierAttribute.FindAllStringSubmatch(content[:m[0]], -1); len(ids) > 0 {
+			change.Identifier = ids[len(ids)-1][1]
+		}
+		changes = append(changes, change)
+
+		b.WriteString(content[last:m[4]])
+		b.WriteString(change.New.In(old.Location()).Format(layout))
+		last = m[5]
+	}
+	b.WriteString(content[last:])
+
+	return b.String(), changes
+}
+
+// parseUntil returns the end date of a recurrence rule and its layout, and false when it cannot be parsed.
+func parseUntil(s string) (time.Time, string, bool) {
+	for _, layout := range untilLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, layout, true
+		}
+	}
+
+	return time.Time{}, "", false
+}
+
+// DateChangesCSV writes the changes as CSV, with a row per date, following a header row.
+func DateChangesCSV(w io.Writer, changes []DateChange) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Path", "Element", "Identifier", "Old", "New"})
+
+	for _, c := range changes {
+		cw.Write([]string{c.Path, c.Element, c.Identifier, c.Old.Format(time.RFC3339), c.New.Format(time.RFC3339)})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,164 @@
+package canvas
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShiftDates(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+
+	var b bytes.Buffer
+	changes, err := ShiftDates(cc, &b, DateShift{NewStart: time.Date(2024, 8, 14, 0, 0, 0, 0, time.UTC)})
+	require.Nil(t, err)
+
+	p := filepath.Join(t.TempDir(), "shifted.imscc")
+	require.Nil(t, os.WriteFile(p, b.Bytes(), 0644))
+	shifted, err := commoncartridge.Load(p)
+	require.Nil(t, err)
+
+	//-- 3654 days from 2014-08-13 to 2024-08-14
+	course, err := CourseSettings(shifted)
+	require.Nil(t, err)
+	assert.Equal(t, course.StartAt, time.Date(2024, 8, 14, 19, 55, 0, 0, time.UTC))
+
+	entries, err := Calendar(shifted)
+	require.Nil(t, err)
+	require.Equal(t, len(entries), 3)
+	assert.Equal(t, entries[0].Start, time.Date(2024, 4, 23, 18, 0, 0, 0, time.UTC))
+	assert.Equal(t, entries[2].Start, time.Date(2030, 12, 28, 6, 59, 59, 0, time.UTC))
+
+	meta, err := shifted.Metadata()
+	require.Nil(t, err)
+	assert.Contains(t, meta, `"Date":"2024-09-09"`)
+
+	found := false
+	for _, c := range changes {
+		if c.Path == "iaa4b4fdadec793530c31c58a249e0879/assignment.xml" && c.Element == "all_day_date" {
+			found = true
+			assert.Equal(t, c.Identifier, "iaa4b4fdadec793530c31c58a249e0879_canvas")
+			assert.Equal(t, c.Old, time.Date(2020, 12, 26, 0, 0, 0, 0, time.UTC))
+			assert.Equal(t, c.New, time.Date(2030, 12, 28, 0, 0, 0, 0, time.UTC))
+		}
+	}
+	assert.True(t, found)
+
+	//-- the files without dates are left as is
+	original, err := cc.ReadFile("wiki_content/front-page.html")
+	require.Nil(t, err)
+	copied, err := shifted.ReadFile("wiki_content/front-page.html")
+	require.Nil(t, err)
+	assert.Equal(t, copied, original)
+
+	var csv bytes.Buffer
+	require.Nil(t, DateChangesCSV(&csv, changes))
+	assert.True(t, strings.HasPrefix(csv.String(), "Path,Element,Identifier,Old,New\n"))
+	assert.Equal(t, strings.Count(csv.String(), "\n"), len(changes)+1)
+}
+
+func TestShiftDatesWithoutStart(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+
+	var b bytes.Buffer
+	_, err := ShiftDates(cc, &b, DateShift{NewStart: time.Date(2024, 8, 14, 0, 0, 0, 0, time.UTC)})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "the course has no start date")
+
+	//-- the course settings cannot be read
+	broken := load(t, testcc.Rewrite(t, testcc.SingleTestFile, map[string]string{courseSettingsFile: `<course`}))
+	_, err = ShiftDates(broken, &b, DateShift{NewStart: time.Date(2024, 8, 14, 0, 0, 0, 0, time.UTC)})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "could not read the start date of the course")
+
+	changes, err := ShiftDates(cc, &b, DateShift{Offset: 7 * 24 * time.Hour})
+	require.Nil(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestShiftNestedDates(t *testing.T) {
+	manifest := `<manifest><metadata><lom><lifeCycle><contribute><date><dateTime>2022-09-01</dateTime></date></contribute></lifeCycle></lom></metadata></manifest>`
+	cc := load(t, testcc.Archive(t, map[string]string{"course/imsmanifest.xml": manifest}))
+
+	var b bytes.Buffer
+	changes, err := ShiftDates(cc, &b, DateShift{Offset: 7 * 24 * time.Hour})
+	require.Nil(t, err)
+	require.Equal(t, len(changes), 1)
+	assert.Equal(t, changes[0].Path, "course/imsmanifest.xml")
+}
+
+func TestShiftRecurringEvent(t *testing.T) {
+	events := `<?xml version="1.0" encoding="UTF-8"?>
+<events xmlns="http://canvas.instructure.com/xsd/cccv1p0">
+  <event identifier="iweekly">
+    <title>Lecture</title>
+    <start_at>2014-04-22T18:00:00</start_at>
+    <end_at>2014-04-22T19:00:00</end_at>
+    <rrule>RRULE:FREQ=WEEKLY;UNTIL=20140601T180000Z;BYDAY=TU</rrule>
+  </event>
+  <event identifier="idaily">
+    <title>Office hours</title>
+    <start_at>2014-04-22T18:00:00</start_at>
+    <rrule>FREQ=DAILY;UNTIL=20140430</rrule>
+  </event>
+</events>`
+	cc := load(t, testcc.Rewrite(t, testcc.SingleTestFile, map[string]string{eventsFile: events}))
+
+	var b bytes.Buffer
+	changes, err := ShiftDates(cc, &b, DateShift{Offset: 7 * 24 * time.Hour})
+	require.Nil(t, err)
+
+	until := make([]DateChange, 0)
+	for _, c := range changes {
+		if c.Element == "rrule" {
+			until = append(until, c)
+		}
+	}
+	require.Equal(t, len(until), 2)
+	assert.Equal(t, until[0].Path, eventsFile)
+	assert.Equal(t, until[0].Identifier, "iweekly")
+	assert.Equal(t, until[0].New, time.Date(2014, 6, 8, 18, 0, 0, 0, time.UTC))
+	assert.Equal(t, until[1].Identifier, "idaily")
+
+	p := filepath.Join(t.TempDir(), "shifted.imscc")
+	require.Nil(t, os.WriteFile(p, b.Bytes(), 0644))
+	shifted, err := commoncartridge.Load(p)
+	require.Nil(t, err)
+	content, err := shifted.ReadFile(eventsFile)
+	require.Nil(t, err)
+	assert.Contains(t, string(content), "<rrule>RRULE:FREQ=WEEKLY;UNTIL=20140608T180000Z;BYDAY=TU</rrule>")
+	assert.Contains(t, string(content), "<rrule>FREQ=DAILY;UNTIL=20140507</rrule>")
+	assert.Contains(t, string(content), "<start_at>2014-04-29T18:00:00</start_at>")
+}
+
+func TestDateShift(t *testing.T) {
+	friday := time.Date(2024, 1, 5, 23, 59, 0, 0, time.UTC)
+
+	shift := DateShift{Offset: 24 * time.Hour}
+	assert.Equal(t, shift.Shift(friday), time.Date(2024, 1, 6, 23, 59, 0, 0, time.UTC))
+
+	shift.SkipWeekends = true
+	assert.Equal(t, shift.Shift(friday), time.Date(2024, 1, 8, 23, 59, 0, 0, time.UTC))
+
+	//-- Saturday 03:00 in UTC is still Friday in New York
+	saturday := time.Date(2024, 1, 6, 3, 0, 0, 0, time.UTC)
+	shift = DateShift{SkipWeekends: true}
+	assert.Equal(t, shift.Shift(saturday), saturday.AddDate(0, 0, 2))
+
+	shift.Location = time.FixedZone("EST", -5*60*60)
+	assert.Equal(t, shift.Shift(saturday), saturday)
+
+	shift = DateShift{
+		OldStart: time.Date(2023, 9, 4, 13, 0, 0, 0, time.UTC),
+		NewStart: time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, shift.Shift(time.Date(2023, 9, 8, 23, 59, 0, 0, time.UTC)), time.Date(2024, 9, 6, 23, 59, 0, 0, time.UTC))
+}
@@ -0,0 +1,264 @@
+package canvas
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+var (
+	// filesMetaFile is the document holding the settings of the folders and files of a Canvas course.
+	filesMetaFile = settingsFile("files_meta.xml")
+	// mediaTracksFile is the document listing the caption and subtitle tracks of the media files of a Canvas course.
+	mediaTracksFile = settingsFile("media_tracks.xml")
+)
+
+const (
+	// filesDir is the folder in which Canvas exports the files of a course.
+	filesDir = "web_resources"
+	// courseFilesFolder is the name that Canvas gives to the root folder of the files of a course, in the paths of files_meta.xml.
+	courseFilesFolder = "course files"
+)
+
+// Folder is a folder of the files of a Canvas course whose settings differ from the defaults.
+type Folder struct {
+	// Path is the path of the folder in the archive, e.g. `web_resources/Handouts`.
+	Path   string
+	Hidden bool
+	Locked bool
+	// LockAt and UnlockAt are the zero time when they are not set.
+	LockAt   time.Time
+	UnlockAt time.Time
+}
+
+// File is a file of a Canvas course, with its settings and, for audio and video files, its caption tracks.
+type File struct {
+	// Identifier is the identifier of the resource of the file.
+	Identifier string
+	// Path is the path of the file in the archive.
+	Path string
+	// DisplayName is the name shown to learners, which defaults to the name of the file.
+	DisplayName string
+	// Hidden and Locked are true when the file, or one of its folders, is hidden or locked.
+	Hidden bool
+	Locked bool
+	// LockAt and UnlockAt are the zero time when they are set neither on the file nor on its folders. A file without dates takes the ones of its deepest folder which has them.
+	LockAt      time.Time
+	UnlockAt    time.Time
+	Category    string
+	UsageRights UsageRights
+	Tracks      []Track
+}
+
+// UsageRights are the copyright information of a file.
+type UsageRights struct {
+	// UseJustification is one of `own_copyright`, `used_by_permission`, `fair_use`, `public_domain` or `creative_commons`.
+	UseJustification string
+	Copyright        string
+	// License is e.g. `private` or a Creative Commons license such as `cc_by_sa`.
+	License string
+}
+
+// Track is a caption, subtitle or description track of an audio or video file.
+type Track struct {
+	// Identifier is the identifier of the resource of the file of the track.
+	Identifier string
+	// Path is the path of the file of the track in the archive.
+	Path string
+	// Kind is one of `subtitles`, `captions`, `descriptions`, `chapters` or `metadata`.
+	Kind string
+	// Language is the locale of the track, e.g. `en`.
+	Language string
+}
+
+// Visible returns false when the file is hidden from learners, or locked.
+func (f File) Visible() bool {
+	return !f.Hidden && !f.Locked
+}
+
+// Folders returns the folders of the `course_settings/files_meta.xml` of a Canvas export, which are hidden, locked or locked between dates.
+func Folders(cc commoncartridge.IMSCC) ([]Folder, error) {
+	folders := make([]Folder, 0)
+
+	meta, err := filesMeta(cc)
+	if err != nil {
+		return folders, err
+	}
+
+	for _, f := range meta.Folders.Folder {
+		folders = append(folders, NewFolder(f))
+	}
+
+	return folders, nil
+}
+
+// NewFolder returns the settings of a folder from their decoded document, with the path of the folder in the archive.
+func NewFolder(f types.CanvasFolderMeta) Folder {
+	p := strings.Trim(strings.TrimSpace(f.Path), "/")
+	if p == courseFilesFolder || strings.HasPrefix(p, courseFilesFolder+"/") {
+		p = strings.TrimPrefix(p, courseFilesFolder)
+	}
+
+	return Folder{
+		Path:     path.Join(filesDir, p),
+		Hidden:   parseBool(f.Hidden),
+		Locked:   parseBool(f.Locked),
+		LockAt:   parseTime(f.LockAt),
+		UnlockAt: parseTime(f.UnlockAt),
+	}
+}
+
+// Files returns the files of the `web_resources` folder of a Canvas export, in the order of the manifest, with the settings of `course_settings/files_meta.xml` and the tracks of `course_settings/media_tracks.xml`. A file is hidden or locked when one of its folders is.
+func Files(cc commoncartridge.IMSCC) ([]File, error) {
+	files := make([]File, 0)
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return files, err
+	}
+
+	meta, err := filesMeta(cc)
+	if err != nil {
+		return files, err
+	}
+
+	folders := make([]Folder, 0)
+	for _, f := range meta.Folders.Folder {
+		folders = append(folders, NewFolder(f))
+	}
+
+	settings := make(map[string]types.CanvasFileInfo)
+	for _, f := range meta.Files.File {
+		settings[f.Identifier] = f
+	}
+
+	tracks, err := mediaTracks(cc)
+	if err != nil {
+		return files, err
+	}
+
+	for _, r := range manifest.Resources.Resource {
+		href := commoncartridge.ResourceHref(r)
+		if r.Type != "webcontent" || !strings.HasPrefix(href, filesDir+"/") {
+			continue
+		}
+
+		file := File{
+			Identifier:  r.Identifier,
+			Path:        href,
+			DisplayName: path.Base(href),
+			Tracks:      make([]Track, 0),
+		}
+		if t, ok := tracks[r.Identifier]; ok {
+			file.Tracks = t
+		}
+
+		if s, ok := settings[r.Identifier]; ok {
+			if name := strings.TrimSpace(s.DisplayName); name != "" {
+				file.DisplayName = name
+			}
+			file.Hidden = parseBool(s.Hidden)
+			file.Locked = parseBool(s.Locked)
+			file.LockAt = parseTime(s.LockAt)
+			file.UnlockAt = parseTime(s.UnlockAt)
+			file.Category = strings.TrimSpace(s.Category)
+			file.UsageRights = UsageRights{
+				UseJustification: strings.TrimSpace(s.UsageRights.UseJustification),
+				Copyright:        strings.TrimSpace(s.UsageRights.LegalCopyright),
+				License:          strings.TrimSpace(s.UsageRights.License),
+			}
+		}
+
+		inherit := file.LockAt.IsZero() && file.UnlockAt.IsZero()
+		dated := ""
+		for _, f := range folders {
+			if !strings.HasPrefix(href, f.Path+"/") {
+				continue
+			}
+			file.Hidden = file.Hidden || f.Hidden
+			file.Locked = file.Locked || f.Locked
+
+			if inherit && (!f.LockAt.IsZero() || !f.UnlockAt.IsZero()) && len(f.Path) > len(dated) {
+				file.LockAt, file.UnlockAt = f.LockAt, f.UnlockAt
+				dated = f.Path
+			}
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// VisibleFiles returns the files of a Canvas export which learners can see, leaving out those which are hidden or locked, or whose folder is.
+func VisibleFiles(cc commoncartridge.IMSCC) ([]File, error) {
+	visible := make([]File, 0)
+
+	files, err := Files(cc)
+	if err != nil {
+		return visible, err
+	}
+
+	for _, f := range files {
+		if f.Visible() {
+			visible = append(visible, f)
+		}
+	}
+
+	return visible, nil
+}
+
+// filesMeta returns the decoded `course_settings/files_meta.xml`, which is empty when the cartridge has none.
+func filesMeta(cc commoncartridge.IMSCC) (types.CanvasFileMeta, error) {
+	var meta types.CanvasFileMeta
+	if !cc.HasFile(filesMetaFile) {
+		return meta, nil
+	}
+
+	if err := readXML(cc, filesMetaFile, &meta); err != nil {
+		return meta, fmt.Errorf("could not read the settings of the files: %w", err)
+	}
+
+	return meta, nil
+}
+
+// mediaTracks returns the tracks of `course_settings/media_tracks.xml`, by identifier of the resource of their media file.
+func mediaTracks(cc commoncartridge.IMSCC) (map[string][]Track, error) {
+	tracks := make(map[string][]Track)
+
+	if !cc.HasFile(mediaTracksFile) {
+		return tracks, nil
+	}
+
+	var meta types.CanvasMediaTracks
+	if err := readXML(cc, mediaTracksFile, &meta); err != nil {
+		return tracks, fmt.Errorf("could not read the media tracks: %w", err)
+	}
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return tracks, err
+	}
+
+	hrefs := make(map[string]string)
+	for _, r := range manifest.Resources.Resource {
+		hrefs[r.Identifier] = commoncartridge.ResourceHref(r)
+	}
+
+	for _, m := range meta.Media {
+		for _, t := range m.Track {
+			tracks[m.Identifierref] = append(tracks[m.Identifierref], Track{
+				Identifier: t.Identifierref,
+				Path:       hrefs[t.Identifierref],
+				Kind:       strings.TrimSpace(t.Kind),
+				Language:   strings.TrimSpace(t.Locale),
+			})
+		}
+	}
+
+	return tracks, nil
+}
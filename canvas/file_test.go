@@ -0,0 +1,116 @@
+package canvas
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFilesMeta = `<?xml version="1.0" encoding="UTF-8"?>
+<fileMeta xmlns="http://canvas.instructure.com/xsd/cccv1p0">
+  <folders>
+    <folder path="course files">
+      <lock_at>2022-12-01T04:00:00</lock_at>
+    </folder>
+    <folder path="course files/Example Folder">
+      <hidden>true</hidden>
+      <unlock_at>2022-10-01T04:00:00</unlock_at>
+    </folder>
+  </folders>
+  <files>
+    <file identifier="i5cb9a100311595ea2ffc33d3f2f48b36">
+      <locked>true</locked>
+      <unlock_at>2022-09-01T04:00:00</unlock_at>
+      <display_name>Example.jpg</display_name>
+      <usage_rights use_justification="creative_commons">
+        <license>cc_by_sa</license>
+      </usage_rights>
+    </file>
+  </files>
+</fileMeta>`
+
+const testMediaTracks = `<?xml version="1.0" encoding="UTF-8"?>
+<media_tracks xmlns="http://canvas.instructure.com/xsd/cccv1p0">
+  <media identifierref="i5cb9a100311595ea2ffc33d3f2f48b36">
+    <track kind="subtitles" locale="en" identifierref="i3755487a331b36c76cec8bbbcdb7cc66"/>
+    <track kind="captions" locale="fr" identifierref="missing"/>
+  </media>
+</media_tracks>`
+
+func TestFiles(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	files, err := Files(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(files), 2)
+	assert.Equal(t, files[0].Identifier, "i5cb9a100311595ea2ffc33d3f2f48b36")
+	assert.Equal(t, files[0].Path, "web_resources/Example File.jpg")
+	assert.Equal(t, files[0].DisplayName, "Example File.jpg")
+	assert.True(t, files[0].Visible())
+	assert.Empty(t, files[0].Tracks)
+	assert.Equal(t, files[1].Path, "web_resources/Example Folder/Example.doc")
+
+	folders, err := Folders(cc)
+	require.Nil(t, err)
+	assert.Empty(t, folders)
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "allyworkshop.imscc"))
+	files, err = Files(cc)
+	require.Nil(t, err)
+	for _, f := range files {
+		if f.Identifier == "i20d6a85d83b273b437345e95e67af49f" {
+			assert.Equal(t, f.DisplayName, "Accessible Technology Policy Final copy (2).pdf")
+		}
+	}
+}
+
+func TestHiddenFiles(t *testing.T) {
+	p := testcc.Rewrite(t, testcc.SingleTestFile, map[string]string{
+		filesMetaFile:   testFilesMeta,
+		mediaTracksFile: testMediaTracks,
+	})
+	cc := load(t, p)
+
+	folders, err := Folders(cc)
+	require.Nil(t, err)
+	require.Equal(t, len(folders), 2)
+	assert.Equal(t, folders[1].Path, "web_resources/Example Folder")
+	assert.True(t, folders[1].Hidden)
+
+	files, err := Files(cc)
+	require.Nil(t, err)
+	require.Equal(t, len(files), 2)
+
+	assert.Equal(t, files[0].DisplayName, "Example.jpg")
+	assert.True(t, files[0].Locked)
+	assert.False(t, files[0].Hidden)
+	assert.Equal(t, files[0].UnlockAt, time.Date(2022, 9, 1, 4, 0, 0, 0, time.UTC))
+	assert.True(t, files[0].LockAt.IsZero(), "the file has dates of its own")
+	assert.Equal(t, files[0].UsageRights, UsageRights{UseJustification: "creative_commons", License: "cc_by_sa"})
+	assert.Equal(t, files[0].Tracks, []Track{
+		{Identifier: "i3755487a331b36c76cec8bbbcdb7cc66", Path: "web_resources/Example Folder/Example.doc", Kind: "subtitles", Language: "en"},
+		{Identifier: "missing", Kind: "captions", Language: "fr"},
+	})
+
+	//-- hidden through its folder
+	assert.True(t, files[1].Hidden)
+	assert.False(t, files[1].Locked)
+	//-- dated through its deepest folder
+	assert.Equal(t, files[1].UnlockAt, time.Date(2022, 10, 1, 4, 0, 0, 0, time.UTC))
+	assert.True(t, files[1].LockAt.IsZero())
+
+	visible, err := VisibleFiles(cc)
+	require.Nil(t, err)
+	assert.Empty(t, visible)
+}
+
+func TestNewFolder(t *testing.T) {
+	assert.Equal(t, NewFolder(types.CanvasFolderMeta{Path: "course files"}).Path, "web_resources")
+	assert.Equal(t, NewFolder(types.CanvasFolderMeta{Path: "course files/A/B"}).Path, "web_resources/A/B")
+	assert.Equal(t, NewFolder(types.CanvasFolderMeta{Path: "course filesystem"}).Path, "web_resources/course filesystem")
+}
@@ -0,0 +1,215 @@
+package canvas
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// moduleMetaFile is the document holding the settings of the modules of a Canvas course.
+var moduleMetaFile = settingsFile("module_meta.xml")
+
+// Module is a module of a Canvas course, with its settings and the ones of its items, which are authoritative over the organization of the manifest.
+type Module struct {
+	Identifier string
+	Title      string
+	Settings   commoncartridge.ItemSettings
+	Items      []ModuleItem
+}
+
+// ModuleItem is an item of a Module.
+type ModuleItem struct {
+	Identifier string
+	Title      string
+	// Identifierref is the identifier of the resource of the item, if any. Text headers (`ContextModuleSubHeader`) have none.
+	Identifierref string
+	Settings      commoncartridge.ItemSettings
+}
+
+// Modules returns the modules of a Canvas course, from the `course_settings/module_meta.xml` file of the cartridge.
+func Modules(cc commoncartridge.IMSCC) ([]Module, error) {
+	modules := make([]Module, 0)
+
+	var meta types.CanvasModules
+	if err := readXML(cc, moduleMetaFile, &meta); err != nil {
+		return modules, fmt.Errorf("could not read the module settings: %w", err)
+	}
+
+	for _, m := range meta.Module {
+		modules = append(modules, NewModule(m))
+	}
+
+	return modules, nil
+}
+
+// NewModule builds a Module from its decoded settings. The completion requirement of each item is also set on the item.
+func NewModule(m types.CanvasModule) Module {
+	module := Module{
+		Identifier: m.Identifier,
+		Title:      strings.TrimSpace(m.Title),
+		Settings: commoncartridge.ItemSettings{
+			WorkflowState:             strings.TrimSpace(m.WorkflowState),
+			Position:                  parseInt(m.Position),
+			RequireSequentialProgress: parseBool(m.RequireSequentialProgress),
+			Locked:                    parseBool(m.Locked),
+			UnlockAt:                  parseTime(m.UnlockAt),
+			Prerequisites:             make([]string, 0),
+			CompletionRequirements:    make([]commoncartridge.CompletionRequirement, 0),
+		},
+		Items: make([]ModuleItem, 0),
+	}
+
+	for _, p := range m.Prerequisites.Prerequisite {
+		module.Settings.Prerequisites = append(module.Settings.Prerequisites, strings.TrimSpace(p.Identifierref))
+	}
+
+	for _, r := range m.CompletionRequirements.CompletionRequirement {
+		requirement := commoncartridge.CompletionRequirement{
+			Item: strings.TrimSpace(r.Identifierref),
+			Type: strings.TrimSpace(r.Type),
+		}
+		requirement.MinScore, _ = strconv.ParseFloat(strings.TrimSpace(r.MinScore), 64)
+
+		module.Settings.CompletionRequirements = append(module.Settings.CompletionRequirements, requirement)
+	}
+
+	for _, i := range m.Items.Item {
+		item := ModuleItem{
+			Identifier:    i.Identifier,
+			Title:         strings.TrimSpace(i.Title),
+			Identifierref: strings.TrimSpace(i.Identifierref),
+			Settings: commoncartridge.ItemSettings{
+				WorkflowState:          strings.TrimSpace(i.WorkflowState),
+				Position:               parseInt(i.Position),
+				ContentType:            strings.TrimSpace(i.ContentType),
+				Indent:                 parseInt(i.Indent),
+				URL:                    strings.TrimSpace(i.URL),
+				NewTab:                 parseBool(i.NewTab),
+				Prerequisites:          make([]string, 0),
+				CompletionRequirements: make([]commoncartridge.CompletionRequirement, 0),
+			},
+		}
+
+		for _, r := range module.Settings.CompletionRequirements {
+			if r.Item == item.Identifier {
+				item.Settings.CompletionRequirements = append(item.Settings.CompletionRequirements, r)
+			}
+		}
+
+		module.Items = append(module.Items, item)
+	}
+
+	return module
+}
+
+// ExtendItems sets the settings of the modules found in `module_meta.xml` on the top-level items, and the ones of their items on the children, which are then sorted by position. Since these settings are authoritative, the modules are sorted by position as well, and the modules and items that the manifest leaves out, such as empty or unpublished ones, are added, the items with their resource. The items of the manifest which are not part of a module come last. Canvas sometimes exports the item of an LTI tool with a different identifier in the manifest, in which case it is matched on the identifier of its resource.
+func (Extension) ExtendItems(cc commoncartridge.IMSCC, items []commoncartridge.FullItem) ([]commoncartridge.FullItem, error) {
+	if !cc.HasFile(moduleMetaFile) {
+		return items, nil
+	}
+
+	modules, err := Modules(cc)
+	if err != nil {
+		return items, err
+	}
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return items, err
+	}
+
+	extended := make([]commoncartridge.FullItem, 0, len(items))
+	used := make(map[int]bool)
+
+	for _, m := range modules {
+		settings := m.Settings
+		module := commoncartridge.FullItem{
+			Item:     types.Item{Identifier: m.Identifier, Title: m.Title},
+			Settings: &settings,
+		}
+
+		var children []commoncartridge.FullItem
+		for i := range items {
+			if !used[i] && items[i].Item.Identifier == m.Identifier {
+				used[i] = true
+				module.Item, module.Resources, children = items[i].Item, items[i].Resources, items[i].Children
+				break
+			}
+		}
+		module.Children = m.children(children, manifest.Resources.Resource)
+
+		extended = append(extended, module)
+	}
+
+	sort.SliceStable(extended, func(a, b int) bool {
+		return extended[a].Settings.Position < extended[b].Settings.Position
+	})
+
+	for i, item := range items {
+		if !used[i] {
+			extended = append(extended, item)
+		}
+	}
+
+	return extended, nil
+}
+
+// children returns the items of the module, in order, with their settings. Each of them is either taken from the given items of the manifest, or built from the module item. The items of the manifest which are not part of the module come last.
+func (m Module) children(items []commoncartridge.FullItem, resources []types.Resource) []commoncartridge.FullItem {
+	children := make([]commoncartridge.FullItem, 0, len(items))
+	used := make(map[int]bool)
+
+	for _, mi := range m.Items {
+		settings := mi.Settings
+		child := commoncartridge.FullItem{
+			Item:     types.Item{Identifier: mi.Identifier, Identifierref: mi.Identifierref, Title: mi.Title},
+			Settings: &settings,
+		}
+
+		if j, ok := mi.match(items, used); ok {
+			used[j] = true
+			child.Item, child.Resources, child.Children = items[j].Item, items[j].Resources, items[j].Children
+		} else if mi.Identifierref != "" {
+			for _, r := range resources {
+				if r.Identifier == mi.Identifierref {
+					child.Resources = append(child.Resources, r)
+				}
+			}
+		}
+
+		children = append(children, child)
+	}
+
+	sort.SliceStable(children, func(a, b int) bool {
+		return children[a].Settings.Position < children[b].Settings.Position
+	})
+
+	for j, item := range items {
+		if !used[j] {
+			children = append(children, item)
+		}
+	}
+
+	return children
+}
+
+// match returns the index of the item of the manifest corresponding to the module item, among the ones which are not used yet.
+func (mi ModuleItem) match(items []commoncartridge.FullItem, used map[int]bool) (int, bool) {
+	for j, item := range items {
+		if !used[j] && item.Item.Identifier == mi.Identifier {
+			return j, true
+		}
+	}
+
+	for j, item := range items {
+		if !used[j] && item.Item.Identifierref != "" && item.Item.Identifierref == mi.Identifier {
+			return j, true
+		}
+	}
+
+	return -1, false
+}
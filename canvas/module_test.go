@@ -0,0 +1,114 @@
+package canvas
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModules(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	modules, err := Modules(cc)
+	require.Nil(t, err)
+	require.Equal(t, len(modules), 2)
+
+	m := modules[0]
+	assert.Equal(t, m.Title, "Example Course Module")
+	assert.True(t, m.Settings.RequireSequentialProgress)
+	assert.Equal(t, len(m.Items), 15)
+	assert.Equal(t, len(m.Settings.CompletionRequirements), 6)
+	assert.Equal(t, m.Settings.CompletionRequirements[1], commoncartridge.CompletionRequirement{Item: "ic8a6338897d38f64caf28ec7d6e2a856", Type: "min_score", MinScore: 8})
+
+	quiz := m.Items[1]
+	assert.Equal(t, quiz.Settings.ContentType, "Quizzes::Quiz")
+	assert.Equal(t, quiz.Settings.Position, 2)
+	assert.Equal(t, quiz.Settings.CompletionRequirements[0].Type, "min_score")
+	assert.Equal(t, m.Items[5].Settings.Indent, 2)
+	assert.Equal(t, m.Items[6].Settings.URL, "http://www.google.com")
+	assert.False(t, m.Items[10].Settings.Published())
+
+	locked := modules[1]
+	assert.False(t, locked.Settings.Published())
+	assert.Equal(t, locked.Settings.Prerequisites, []string{"i224aa0e52b019dbf9aeece014df883c7"})
+}
+
+func TestModulesUnlockAt(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	modules, err := Modules(cc)
+	require.Nil(t, err)
+	assert.Equal(t, len(modules), 17)
+	assert.Equal(t, modules[2].Settings.UnlockAt, time.Date(2019, 9, 1, 4, 0, 0, 0, time.UTC))
+	assert.True(t, modules[0].Settings.UnlockAt.IsZero())
+}
+
+func TestExtendItems(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	items, err := cc.Items()
+	require.Nil(t, err)
+	require.Equal(t, len(items), 2)
+
+	require.NotNil(t, items[0].Settings)
+	assert.Equal(t, items[0].Settings.Position, 1)
+	assert.Nil(t, items[0].Children[0].Item.Item)
+
+	for _, child := range items[0].Children {
+		require.NotNil(t, child.Settings, child.Item.Title)
+	}
+	assert.Equal(t, items[0].Children[0].Settings.ContentType, "Assignment")
+	assert.Equal(t, items[0].Children[7].Settings.ContentType, "ContextExternalTool")
+
+	//-- unpublished items are left out of the manifest
+	require.Equal(t, len(items[0].Children), 15)
+	unpublished := items[0].Children[10]
+	assert.Equal(t, unpublished.Item.Title, "unpublished assignment")
+	assert.False(t, unpublished.Settings.Published())
+	require.Equal(t, len(unpublished.Resources), 1)
+	assert.Equal(t, unpublished.Resources[0].Identifier, "ia57385f92f17f47f20554839ea2c4a34")
+	assert.Equal(t, items[0].Children[14].Item.Title, "Due Date Assignment")
+
+	require.NotNil(t, items[1].Settings)
+	assert.Equal(t, items[1].Settings.WorkflowState, "unpublished")
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	items, err = cc.Items()
+	require.Nil(t, err)
+	for _, item := range items {
+		assert.Nil(t, item.Settings)
+	}
+}
+
+func TestExtendItemsModuleOrder(t *testing.T) {
+	content, err := load(t, testcc.SingleTestFile).ReadFile(moduleMetaFile)
+	require.Nil(t, err)
+
+	//-- the first module moves last, and an empty unpublished module which the manifest leaves out comes first
+	meta := strings.Replace(string(content), "<position>1</position>", "<position>3</position>", 1)
+	meta = strings.Replace(meta, "</modules>", `  <module identifier="gempty">
+    <title>Empty Module</title>
+    <workflow_state>unpublished</workflow_state>
+    <position>1</position>
+    <items/>
+  </module>
+</modules>`, 1)
+
+	cc := load(t, testcc.Rewrite(t, testcc.SingleTestFile, map[string]string{moduleMetaFile: meta}))
+	items, err := cc.Items()
+	require.Nil(t, err)
+	require.Equal(t, len(items), 3)
+
+	assert.Equal(t, items[0].Item.Identifier, "gempty")
+	assert.Equal(t, items[0].Item.Title, "Empty Module")
+	assert.False(t, items[0].Settings.Published())
+	assert.Empty(t, items[0].Children)
+
+	assert.Equal(t, items[1].Item.Title, "Example, Locked Module")
+	assert.Equal(t, items[2].Item.Title, "Example Course Module")
+	assert.Equal(t, items[2].Settings.Position, 3)
+	assert.Equal(t, len(items[2].Children), 15)
+}
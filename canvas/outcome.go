@@ -0,0 +1,241 @@
+package canvas
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// learningOutcomesFile is the document holding the learning outcomes of a Canvas course.
+var learningOutcomesFile = settingsFile("learning_outcomes.xml")
+
+// The types of content a learning outcome can be aligned with, besides the AssignmentItem, QuizItem and DiscussionItem of the gradebook.
+const (
+	RubricAlignment       = "rubric"
+	QuestionBankAlignment = "question_bank"
+)
+
+// alignmentTypes are the types of the alignments of Canvas, by their `content_type`.
+var alignmentTypes = map[string]string{
+	"Assignment":             AssignmentItem,
+	"Quizzes::Quiz":          QuizItem,
+	"Quiz":                   QuizItem,
+	"DiscussionTopic":        DiscussionItem,
+	"Rubric":                 RubricAlignment,
+	"AssessmentQuestionBank": QuestionBankAlignment,
+}
+
+// OutcomeGroup is a group of learning outcomes, which may hold other groups.
+type OutcomeGroup struct {
+	Identifier string
+	Title      string
+	// Description is HTML.
+	Description string
+	Groups      []OutcomeGroup
+	Outcomes    []Outcome
+}
+
+// Outcome is a learning outcome of a Canvas course, with its rating scale and the content aligned with it.
+type Outcome struct {
+	Identifier string
+	Title      string
+	// Description is HTML.
+	Description string
+	// CalculationMethod is how the mastery of the outcome is computed from the scores of a learner, one of `decaying_average`, `n_mastery`, `latest`, `highest` or `average`, with the CalculationInt parameter of the first two.
+	CalculationMethod string
+	CalculationInt    int
+	PointsPossible    float64
+	// MasteryPoints is the score above which the outcome is mastered.
+	MasteryPoints float64
+	Ratings       []Rating
+	// Global is true for the outcomes shared by all the courses of the institution, which ExternalIdentifier identifies.
+	Global             bool
+	ExternalIdentifier string
+	Alignments         []Alignment
+}
+
+// Alignment is a piece of content which assesses a learning outcome.
+type Alignment struct {
+	// Type is one of AssignmentItem, QuizItem, DiscussionItem, RubricAlignment or QuestionBankAlignment.
+	Type string
+	// Identifier is the identifier of the resource of an assignment, quiz or discussion topic, or the one of a rubric or question bank.
+	Identifier string
+	Title      string
+	// Criterion is the ID of the criterion of a rubric which rates the outcome, if any.
+	Criterion string
+}
+
+// AllOutcomes returns the outcomes of the group, then the ones of all its subgroups.
+func (g OutcomeGroup) AllOutcomes() []Outcome {
+	outcomes := make([]Outcome, 0)
+	outcomes = append(outcomes, g.Outcomes...)
+	for _, sub := range g.Groups {
+		outcomes = append(outcomes, sub.AllOutcomes()...)
+	}
+
+	return outcomes
+}
+
+// LearningOutcomes returns the tree of the learning outcomes of a Canvas course, from the `course_settings/learning_outcomes.xml` file, as a root group with no identifier, which is empty when the course has no learning outcomes. Each outcome lists the content aligned with it: the alignments recorded by Canvas, the rubric criteria which rate the outcome, and the assignments, quizzes and discussion topics graded with those rubrics.
+func LearningOutcomes(cc commoncartridge.IMSCC) (OutcomeGroup, error) {
+	root := OutcomeGroup{Groups: make([]OutcomeGroup, 0), Outcomes: make([]Outcome, 0)}
+	if !cc.HasFile(learningOutcomesFile) {
+		return root, nil
+	}
+
+	var meta types.CanvasLearningOutcomes
+	if err := readXML(cc, learningOutcomesFile, &meta); err != nil {
+		return root, fmt.Errorf("could not read the learning outcomes: %w", err)
+	}
+
+	items, err := GradedItems(cc)
+	if err != nil {
+		return root, err
+	}
+
+	rubrics, err := Rubrics(cc)
+	if err != nil {
+		return root, err
+	}
+
+	aligner := newAligner(items, rubrics)
+	root.Groups, root.Outcomes = aligner.list(meta.CanvasOutcomeList)
+
+	return root, nil
+}
+
+// NewOutcome builds an Outcome, with the alignments recorded by Canvas left unresolved, from its decoded settings.
+func NewOutcome(o types.CanvasOutcome) Outcome {
+	outcome := Outcome{
+		Identifier:         o.Identifier,
+		Title:              strings.TrimSpace(o.Title),
+		Description:        strings.TrimSpace(o.Description),
+		CalculationMethod:  strings.TrimSpace(o.CalculationMethod),
+		CalculationInt:     parseInt(o.CalculationInt),
+		Ratings:            make([]Rating, 0),
+		Global:             parseBool(o.IsGlobalOutcome),
+		ExternalIdentifier: strings.TrimSpace(o.ExternalIdentifier),
+		Alignments:         make([]Alignment, 0),
+	}
+	outcome.PointsPossible, _ = strconv.ParseFloat(strings.TrimSpace(o.PointsPossible), 64)
+	outcome.MasteryPoints, _ = strconv.ParseFloat(strings.TrimSpace(o.MasteryPoints), 64)
+
+	for _, r := range o.Ratings.Rating {
+		rating := Rating{Description: strings.TrimSpace(r.Description)}
+		rating.Points, _ = strconv.ParseFloat(strings.TrimSpace(r.Points), 64)
+		outcome.Ratings = append(outcome.Ratings, rating)
+	}
+
+	for _, a := range o.Alignments.Alignment {
+		alignment := Alignment{Type: alignmentTypes[strings.TrimSpace(a.ContentType)], Identifier: strings.TrimSpace(a.ContentID)}
+		if alignment.Type == "" {
+			alignment.Type = strings.TrimSpace(a.ContentType)
+		}
+		outcome.Alignments = append(outcome.Alignments, alignment)
+	}
+
+	return outcome
+}
+
+// aligner resolves the alignments of the outcomes to the graded items and rubrics of the course.
+type aligner struct {
+	// items are the graded items, by identifier of their resource and of their assignment.
+	items   map[string]GradedItem
+	rubrics []Rubric
+}
+
+// newAligner returns an aligner for the given graded items and rubrics.
+func newAligner(items []GradedItem, rubrics []Rubric) aligner {
+	a := aligner{items: make(map[string]GradedItem), rubrics: rubrics}
+	for _, item := range items {
+		a.items[item.Identifier] = item
+		if id := strings.TrimSpace(item.Assignment.Identifier); id != "" {
+			a.items[id] = item
+		}
+	}
+
+	return a
+}
+
+// list returns the groups and outcomes of a level of the tree, with their alignments.
+func (a aligner) list(l types.CanvasOutcomeList) ([]OutcomeGroup, []Outcome) {
+	groups := make([]OutcomeGroup, 0)
+	for _, g := range l.LearningOutcomeGroup {
+		group := OutcomeGroup{
+			Identifier:  g.Identifier,
+			Title:       strings.TrimSpace(g.Title),
+			Description: strings.TrimSpace(g.Description),
+		}
+		group.Groups, group.Outcomes = a.list(g.LearningOutcomes)
+		groups = append(groups, group)
+	}
+
+	outcomes := make([]Outcome, 0)
+	for _, o := range l.LearningOutcome {
+		outcomes = append(outcomes, a.outcome(NewOutcome(o)))
+	}
+
+	return groups, outcomes
+}
+
+// outcome resolves the alignments recorded by Canvas to the identifiers of the resources of graded items, and adds the rubric criteria rating the outcome and the items graded with their rubric.
+func (a aligner) outcome(outcome Outcome) Outcome {
+	alignments := make([]Alignment, 0)
+	add := func(alignment Alignment) {
+		for _, existing := range alignments {
+			if existing.Type == alignment.Type && existing.Identifier == alignment.Identifier && existing.Criterion == alignment.Criterion {
+				return
+			}
+		}
+		alignments = append(alignments, alignment)
+	}
+
+	for _, alignment := range outcome.Alignments {
+		if item, ok := a.items[alignment.Identifier]; ok && alignment.Type != RubricAlignment && alignment.Type != QuestionBankAlignment {
+			alignment = Alignment{Type: item.Type, Identifier: item.Identifier, Title: item.Title}
+		}
+		for _, r := range a.rubrics {
+			if alignment.Type == RubricAlignment && r.Identifier == alignment.Identifier {
+				alignment.Title = r.Title
+			}
+		}
+		add(alignment)
+	}
+
+	for _, r := range a.rubrics {
+		aligned := false
+		for _, c := range r.Criteria {
+			if c.Outcome != outcome.Identifier {
+				continue
+			}
+			aligned = true
+
+			//-- the rubric recorded by Canvas is completed with the criterion
+			found := false
+			for i, existing := range alignments {
+				if existing.Type == RubricAlignment && existing.Identifier == r.Identifier && existing.Criterion == "" {
+					alignments[i].Criterion, found = c.ID, true
+					break
+				}
+			}
+			if !found {
+				add(Alignment{Type: RubricAlignment, Identifier: r.Identifier, Title: r.Title, Criterion: c.ID})
+			}
+		}
+
+		if !aligned {
+			continue
+		}
+		for _, id := range r.Assignments {
+			if item, ok := a.items[id]; ok {
+				add(Alignment{Type: item.Type, Identifier: item.Identifier, Title: item.Title})
+			}
+		}
+	}
+
+	outcome.Alignments = alignments
+	return outcome
+}
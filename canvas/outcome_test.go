@@ -0,0 +1,94 @@
+package canvas
+
+import (
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLearningOutcomes(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	root, err := LearningOutcomes(cc)
+	require.Nil(t, err)
+
+	assert.Equal(t, root.Identifier, "")
+	assert.Empty(t, root.Outcomes)
+	require.Equal(t, len(root.Groups), 1)
+	assert.Equal(t, root.Groups[0].Identifier, "i93a8c3ca78d8e6ce92de448d233506ca")
+	assert.Equal(t, root.Groups[0].Title, "Example Outcome Group")
+	assert.True(t, strings.HasPrefix(root.Groups[0].Description, "<p>Lorem ipsum"))
+
+	outcomes := root.AllOutcomes()
+	require.Equal(t, len(outcomes), 1)
+	outcome := outcomes[0]
+	assert.Equal(t, outcome.Identifier, "ia9db0c1f10f12a85e15326ca00562db3")
+	assert.Equal(t, outcome.Title, "Example Outcome")
+	assert.Equal(t, outcome.PointsPossible, 5.0)
+	assert.Equal(t, outcome.MasteryPoints, 4.0)
+	require.Equal(t, len(outcome.Ratings), 4)
+	assert.Equal(t, outcome.Ratings[0], Rating{Description: "Perfection!", Points: 5})
+
+	//-- the rubric recorded by Canvas, with the criterion rating the outcome
+	assert.Equal(t, outcome.Alignments, []Alignment{
+		{Type: RubricAlignment, Identifier: "i7b3d56321ef7c255da82c508cab9bc7b", Title: "Example Course Rubric", Criterion: "9_268"},
+	})
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	root, err = LearningOutcomes(cc)
+	require.Nil(t, err)
+	assert.Empty(t, root.Groups)
+	assert.Empty(t, root.Outcomes)
+
+	cc = load(t, testcc.Rewrite(t, testcc.SingleTestFile, map[string]string{learningOutcomesFile: "<learningOutcomes"}))
+	_, err = LearningOutcomes(cc)
+	assert.NotNil(t, err)
+}
+
+func TestOutcomeAlignments(t *testing.T) {
+	name := "iaa4b4fdadec793530c31c58a249e0879/assignment.xml"
+	r, err := load(t, testcc.SingleTestFile).Reader.Open(name)
+	require.Nil(t, err)
+	original, err := io.ReadAll(r)
+	require.Nil(t, err)
+
+	//-- the assignment is graded with the rubric of the outcome
+	content := strings.Replace(string(original), "<muted>", "<rubric_identifierref>i7b3d56321ef7c255da82c508cab9bc7b</rubric_identifierref>\n      <muted>", 1)
+	cc := load(t, testcc.Rewrite(t, testcc.SingleTestFile, map[string]string{name: content}))
+
+	root, err := LearningOutcomes(cc)
+	require.Nil(t, err)
+
+	alignments := root.AllOutcomes()[0].Alignments
+	require.Equal(t, len(alignments), 2)
+	assert.Equal(t, alignments[1].Type, AssignmentItem)
+	assert.Equal(t, alignments[1].Identifier, "iaa4b4fdadec793530c31c58a249e0879")
+	assert.Equal(t, alignments[1].Criterion, "")
+}
+
+func TestNewOutcome(t *testing.T) {
+	var o types.CanvasOutcome
+	err := xml.Unmarshal([]byte(`<learningOutcome identifier="o1">
+  <calculation_method>decaying_average</calculation_method>
+  <calculation_int>65</calculation_int>
+  <is_global_outcome>true</is_global_outcome>
+  <alignments>
+    <alignment><content_type>Quizzes::Quiz</content_type><content_id>q1</content_id></alignment>
+    <alignment><content_type>WikiPage</content_type><content_id>p1</content_id></alignment>
+  </alignments>
+</learningOutcome>`), &o)
+	require.Nil(t, err)
+
+	outcome := NewOutcome(o)
+	assert.Equal(t, outcome.Identifier, "o1")
+	assert.Equal(t, outcome.CalculationMethod, "decaying_average")
+	assert.Equal(t, outcome.CalculationInt, 65)
+	assert.True(t, outcome.Global)
+	assert.Equal(t, outcome.Alignments, []Alignment{{Type: QuizItem, Identifier: "q1"}, {Type: "WikiPage", Identifier: "p1"}})
+}
@@ -0,0 +1,205 @@
+package canvas
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// The types of Canvas quizzes.
+const (
+	GradedQuiz   = "assignment"
+	PracticeQuiz = "practice_quiz"
+	GradedSurvey = "graded_survey"
+	Survey       = "survey"
+)
+
+// quizMetaFile is the document holding the Canvas settings of a quiz, in the folder of its resource.
+const quizMetaFile = "assessment_meta.xml"
+
+// assessmentType matches the resource types of QTI assessments, leaving out question banks.
+var assessmentType = regexp.MustCompile(`^imsqti_xmlv1p\d(/imscc_xmlv1p\d)?/assessment$`)
+
+// Quiz is a QTI assessment of the cartridge, along with the settings that Canvas adds to it.
+type Quiz struct {
+	types.Questestinterop
+	// Identifier is the identifier of the resource of the quiz in the manifest.
+	Identifier string
+	// Settings are the Canvas settings of the quiz, or nil when the cartridge has none.
+	Settings *QuizSettings `json:",omitempty"`
+}
+
+// QuizSettings are the settings of a Canvas quiz.
+type QuizSettings struct {
+	Title string
+	// Description is the HTML shown to learners before they take the quiz.
+	Description string
+	// QuizType is one of GradedQuiz, PracticeQuiz, GradedSurvey or Survey.
+	QuizType       string
+	PointsPossible float64
+	// TimeLimit is the time allowed, in minutes, 0 meaning no limit.
+	TimeLimit int
+	// AllowedAttempts is the number of attempts allowed, -1 meaning unlimited.
+	AllowedAttempts int
+	// ScoringPolicy is the score kept when learners take the quiz several times, `keep_highest`, `keep_latest` or `keep_average`.
+	ScoringPolicy  string
+	ShuffleAnswers bool
+	// ShowCorrectAnswers is true when learners see the correct answers after submitting, between ShowCorrectAnswersAt and HideCorrectAnswersAt when they are set.
+	ShowCorrectAnswers            bool
+	ShowCorrectAnswersLastAttempt bool
+	ShowCorrectAnswersAt          time.Time
+	HideCorrectAnswersAt          time.Time
+	// HideResults is `always`, `until_after_last_attempt`, or empty when learners see their responses.
+	HideResults        string
+	OneTimeResults     bool
+	OneQuestionAtATime bool
+	CantGoBack         bool
+	// AccessCode is the password learners must enter to take the quiz, if any.
+	AccessCode             string
+	IPFilter               string
+	RequireLockdownBrowser bool
+	// Available is false when the quiz is hidden from learners.
+	Available bool
+	// DueAt, LockAt and UnlockAt are the dates of the quiz or, when it has none, of its assignment. They are the zero time when they are not set.
+	DueAt    time.Time
+	LockAt   time.Time
+	UnlockAt time.Time
+	// Group is the identifier of the assignment group of the quiz.
+	Group string
+	// Assignment holds the settings of the assignment of a graded quiz, and is nil for practice quizzes and surveys.
+	Assignment *AssignmentSettings `json:",omitempty"`
+	Raw        types.CanvasQuiz
+}
+
+// Graded returns true for the quizzes and surveys which count towards the grade.
+func (s QuizSettings) Graded() bool {
+	return s.QuizType == GradedQuiz || s.QuizType == GradedSurvey
+}
+
+// Published returns false when the quiz is hidden from learners.
+func (s QuizSettings) Published() bool {
+	return s.Available
+}
+
+// Quizzes returns the QTI assessments of the cartridge, in the order of the manifest, with the Canvas settings of their `assessment_meta.xml`. The quizzes of cartridges which are not exported by Canvas have no settings. It returns an error when the document of an assessment is not a QTI document.
+func Quizzes(cc commoncartridge.IMSCC) ([]Quiz, error) {
+	quizzes := make([]Quiz, 0)
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return quizzes, err
+	}
+
+	settings := make(map[string]types.CanvasQuiz)
+	for _, r := range manifest.Resources.Resource {
+		for _, f := range r.File {
+			if path.Base(f.Href) != quizMetaFile {
+				continue
+			}
+
+			var q types.CanvasQuiz
+			if err := readXML(cc, f.Href, &q); err != nil {
+				return quizzes, err
+			}
+
+			id := q.Identifier
+			if id == "" {
+				id = path.Dir(f.Href)
+			}
+			settings[id] = q
+		}
+	}
+
+	for _, r := range manifest.Resources.Resource {
+		if !assessmentType.MatchString(r.Type) {
+			continue
+		}
+
+		href := commoncartridge.ResourceHref(r)
+		quiz := Quiz{Identifier: r.Identifier}
+		if err := readXML(cc, href, &quiz.Questestinterop); err != nil {
+			return quizzes, fmt.Errorf("could not read the quiz %s: %w", r.Identifier, err)
+		}
+
+		if q, ok := settings[r.Identifier]; ok {
+			quiz.Settings = NewQuizSettings(q)
+		}
+
+		quizzes = append(quizzes, quiz)
+	}
+
+	return quizzes, nil
+}
+
+// FindQuiz returns the quiz whose resource has the given identifier, or an error if there is none.
+func FindQuiz(cc commoncartridge.IMSCC, id string) (Quiz, error) {
+	quizzes, err := Quizzes(cc)
+	if err != nil {
+		return Quiz{}, err
+	}
+
+	for _, q := range quizzes {
+		if q.Identifier == id {
+			return q, nil
+		}
+	}
+
+	return Quiz{}, fmt.Errorf("could not find a quiz with the identifier %s", id)
+}
+
+// NewQuizSettings returns the settings of a Canvas quiz from their decoded document.
+func NewQuizSettings(q types.CanvasQuiz) *QuizSettings {
+	settings := &QuizSettings{
+		Title:                         strings.TrimSpace(q.Title),
+		Description:                   strings.TrimSpace(q.Description),
+		QuizType:                      strings.TrimSpace(q.QuizType),
+		TimeLimit:                     parseInt(q.TimeLimit),
+		AllowedAttempts:               parseInt(q.AllowedAttempts),
+		ScoringPolicy:                 strings.TrimSpace(q.ScoringPolicy),
+		ShuffleAnswers:                parseBool(q.ShuffleAnswers),
+		ShowCorrectAnswers:            parseBool(q.ShowCorrectAnswers),
+		ShowCorrectAnswersLastAttempt: parseBool(q.ShowCorrectAnswersLastAttempt),
+		ShowCorrectAnswersAt:          parseTime(q.ShowCorrectAnswersAt),
+		HideCorrectAnswersAt:          parseTime(q.HideCorrectAnswersAt),
+		HideResults:                   strings.TrimSpace(q.HideResults),
+		OneTimeResults:                parseBool(q.OneTimeResults),
+		OneQuestionAtATime:            parseBool(q.OneQuestionAtATime),
+		CantGoBack:                    parseBool(q.CantGoBack),
+		AccessCode:                    strings.TrimSpace(q.AccessCode),
+		IPFilter:                      strings.TrimSpace(q.IPFilter),
+		RequireLockdownBrowser:        parseBool(q.RequireLockdownBrowser),
+		Available:                     parseBool(q.Available),
+		DueAt:                         parseTime(q.DueAt),
+		LockAt:                        parseTime(q.LockAt),
+		UnlockAt:                      parseTime(q.UnlockAt),
+		Group:                         strings.TrimSpace(q.AssignmentGroupIdentifierref),
+		Raw:                           q,
+	}
+	settings.PointsPossible, _ = strconv.ParseFloat(strings.TrimSpace(q.PointsPossible), 64)
+
+	if q.Assignment.Identifier == "" {
+		return settings
+	}
+
+	settings.Assignment = NewAssignmentSettings(q.Assignment)
+	if settings.DueAt.IsZero() {
+		settings.DueAt = settings.Assignment.DueAt
+	}
+	if settings.LockAt.IsZero() {
+		settings.LockAt = settings.Assignment.LockAt
+	}
+	if settings.UnlockAt.IsZero() {
+		settings.UnlockAt = settings.Assignment.UnlockAt
+	}
+	if settings.Group == "" {
+		settings.Group = settings.Assignment.Group
+	}
+
+	return settings
+}
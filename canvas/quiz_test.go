@@ -0,0 +1,125 @@
+package canvas
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuizzes(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	quizzes, err := Quizzes(cc)
+	require.Nil(t, err)
+
+	qtis, err := cc.QTIs()
+	require.Nil(t, err)
+	require.Equal(t, len(quizzes), len(qtis))
+	require.Equal(t, len(quizzes), 17)
+
+	for _, q := range quizzes {
+		require.NotNil(t, q.Settings, q.Identifier)
+		assert.Equal(t, q.Assessment.Ident, q.Identifier)
+	}
+
+	quiz, err := FindQuiz(cc, "i7d40ddafe1510b13e094faf1d8aede61")
+	require.Nil(t, err)
+	assert.Equal(t, quiz.Assessment.Title, "50 Q's straight up tf and mc")
+	assert.Equal(t, len(quiz.Items()), 50)
+	assert.Equal(t, quiz.Settings.QuizType, GradedQuiz)
+	assert.Equal(t, quiz.Settings.TimeLimit, 75)
+	assert.Equal(t, quiz.Settings.AllowedAttempts, 1)
+	assert.Equal(t, quiz.Settings.PointsPossible, 100.0)
+	assert.Equal(t, quiz.Settings.ScoringPolicy, "keep_highest")
+	assert.True(t, quiz.Settings.ShowCorrectAnswers)
+	assert.False(t, quiz.Settings.ShuffleAnswers)
+	assert.True(t, quiz.Settings.Published())
+	assert.True(t, quiz.Settings.Graded())
+	assert.Equal(t, quiz.Settings.Group, "i20a7c6080e264d0605be98bbf73e6440")
+	require.NotNil(t, quiz.Settings.Assignment)
+	assert.Equal(t, quiz.Settings.Assignment.Identifier, "i5b724379b69780346b183ef6388f4603")
+
+	quiz, err = FindQuiz(cc, "iae800568e436f2368f690a2a87aa92e8")
+	require.Nil(t, err)
+	assert.Equal(t, quiz.Settings.AccessCode, "Canvas")
+
+	quiz, err = FindQuiz(cc, "i581ee685674f4a8c9231bd228a92976a")
+	require.Nil(t, err)
+	assert.Equal(t, quiz.Settings.AllowedAttempts, -1)
+
+	quiz, err = FindQuiz(cc, "i18ee2ad283ef16acf172480ad0e3e514")
+	require.Nil(t, err)
+	assert.Equal(t, quiz.Settings.QuizType, PracticeQuiz)
+	assert.False(t, quiz.Settings.Graded())
+	assert.Nil(t, quiz.Settings.Assignment)
+
+	_, err = FindQuiz(cc, "i1b875ff668210d0b823e91e54ec674d2")
+	assert.NotNil(t, err)
+}
+
+func TestQuizDates(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "sample-public-sandbox-course-export.imscc"))
+	quiz, err := FindQuiz(cc, "i2f6f808ba812d7ef688e87f951f589cf")
+	require.Nil(t, err)
+
+	assert.Equal(t, quiz.Settings.DueAt, time.Date(2015, 6, 2, 5, 59, 0, 0, time.UTC))
+	assert.Equal(t, quiz.Settings.ShowCorrectAnswersAt, time.Date(2015, 1, 5, 7, 0, 0, 0, time.UTC))
+	assert.Equal(t, quiz.Settings.HideCorrectAnswersAt, time.Date(2015, 3, 31, 6, 0, 0, 0, time.UTC))
+	assert.Equal(t, quiz.Settings.Group, "i9da795afe37f9304ac8a4c416fc3e380")
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	quiz, err = FindQuiz(cc, "g762a20b186d2f0cef42285e93726aa65")
+	require.Nil(t, err)
+	assert.Equal(t, quiz.Settings.UnlockAt, time.Date(2020, 4, 20, 4, 0, 0, 0, time.UTC))
+	assert.Equal(t, quiz.Settings.Group, "gd66b2da43ad1c2cf3526005241a945ef")
+}
+
+func TestQuizzesWithoutSettings(t *testing.T) {
+	//-- the settings of the quiz refer to another one
+	p := testcc.Rewrite(t, testcc.SingleTestFile, map[string]string{
+		"i7d40ddafe1510b13e094faf1d8aede61/assessment_meta.xml": `<quiz identifier="other"/>`,
+	})
+	cc := load(t, p)
+
+	quiz, err := FindQuiz(cc, "i7d40ddafe1510b13e094faf1d8aede61")
+	require.Nil(t, err)
+	assert.Nil(t, quiz.Settings)
+	assert.Equal(t, quiz.Assessment.Title, "50 Q's straight up tf and mc")
+}
+
+func TestMalformedQuiz(t *testing.T) {
+	for _, content := range []string{`<questestinterop><assessment`, `<quiz identifier="i7d40ddafe1510b13e094faf1d8aede61"/>`} {
+		p := testcc.Rewrite(t, testcc.SingleTestFile, map[string]string{
+			"i7d40ddafe1510b13e094faf1d8aede61/assessment_qti.xml": content,
+		})
+
+		_, err := Quizzes(load(t, p))
+		assert.NotNil(t, err, content)
+	}
+}
+
+func TestNewQuizSettings(t *testing.T) {
+	q := types.CanvasQuiz{
+		QuizType:        GradedSurvey,
+		AllowedAttempts: "3",
+		TimeLimit:       "",
+		Available:       "false",
+		Assignment: types.CanvasAssignment{
+			Identifier:                   "a1",
+			DueAt:                        "2022-09-01T23:59:00",
+			AssignmentGroupIdentifierref: "g1",
+		},
+	}
+
+	settings := NewQuizSettings(q)
+	assert.Equal(t, settings.AllowedAttempts, 3)
+	assert.Equal(t, settings.TimeLimit, 0)
+	assert.True(t, settings.Graded())
+	assert.False(t, settings.Published())
+	assert.Equal(t, settings.DueAt, time.Date(2022, 9, 1, 23, 59, 0, 0, time.UTC))
+	assert.Equal(t, settings.Group, "g1")
+}
@@ -0,0 +1,230 @@
+package canvas
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// rubricsFile is the document holding the rubrics of a Canvas course.
+var rubricsFile = settingsFile("rubrics.xml")
+
+// Rubric is a rubric of a Canvas course, with the items it grades.
+type Rubric struct {
+	Identifier     string
+	Title          string
+	PointsPossible float64
+	ReadOnly       bool
+	Reusable       bool
+	Public         bool
+	HideScoreTotal bool
+	// FreeFormComments is true when graders write a comment for each criterion instead of picking a rating.
+	FreeFormComments bool
+	Criteria         []Criterion
+	// Assignments are the identifiers of the resources of the assignments, quizzes and discussion topics graded with the rubric.
+	Assignments []string
+}
+
+// Criterion is a row of a rubric, scored with one of its ratings.
+type Criterion struct {
+	ID              string
+	Description     string
+	LongDescription string
+	Points          float64
+	// MasteryPoints is the score above which the learning outcome of the criterion is mastered, if any.
+	MasteryPoints float64
+	// Outcome is the identifier of the learning outcome the criterion is taken from, if any.
+	Outcome string
+	// IgnoreForScoring is true when the criterion does not count towards the score of the rubric.
+	IgnoreForScoring bool
+	Ratings          []Rating
+}
+
+// Rating is a level of achievement of a criterion, with its points.
+type Rating struct {
+	ID              string
+	Description     string
+	LongDescription string
+	Points          float64
+}
+
+// Rubrics returns the rubrics of a Canvas course, from the `course_settings/rubrics.xml` file, each linked to the assignments whose Canvas settings refer to it. It returns an empty slice when the course has no rubrics.
+func Rubrics(cc commoncartridge.IMSCC) ([]Rubric, error) {
+	rubrics := make([]Rubric, 0)
+	if !cc.HasFile(rubricsFile) {
+		return rubrics, nil
+	}
+
+	var meta types.CanvasRubrics
+	if err := readXML(cc, rubricsFile, &meta); err != nil {
+		return rubrics, fmt.Errorf("could not read the rubrics: %w", err)
+	}
+
+	items, err := GradedItems(cc)
+	if err != nil {
+		return rubrics, err
+	}
+
+	for _, r := range meta.Rubric {
+		rubric := NewRubric(r)
+		for _, item := range items {
+			if strings.TrimSpace(item.Assignment.RubricIdentifierref) == rubric.Identifier {
+				rubric.Assignments = append(rubric.Assignments, item.Identifier)
+			}
+		}
+
+		rubrics = append(rubrics, rubric)
+	}
+
+	return rubrics, nil
+}
+
+// AssignmentRubric returns the rubric grading the given assignment, or an error if it has none.
+func AssignmentRubric(cc commoncartridge.IMSCC, a types.Assignment) (Rubric, error) {
+	rubrics, err := Rubrics(cc)
+	if err != nil {
+		return Rubric{}, err
+	}
+
+	for _, r := range rubrics {
+		for _, id := range r.Assignments {
+			if id == a.Identifier {
+				return r, nil
+			}
+		}
+	}
+
+	return Rubric{}, fmt.Errorf("no rubric grades the assignment %s", a.Identifier)
+}
+
+// NewRubric builds a Rubric, with no assignments, from its decoded settings.
+func NewRubric(r types.CanvasRubric) Rubric {
+	rubric := Rubric{
+		Identifier:       r.Identifier,
+		Title:            strings.TrimSpace(r.Title),
+		ReadOnly:         parseBool(r.ReadOnly),
+		Reusable:         parseBool(r.Reusable),
+		Public:           parseBool(r.Public),
+		HideScoreTotal:   parseBool(r.HideScoreTotal),
+		FreeFormComments: parseBool(r.FreeFormCriterionComments),
+		Criteria:         make([]Criterion, 0),
+		Assignments:      make([]string, 0),
+	}
+	rubric.PointsPossible, _ = strconv.ParseFloat(strings.TrimSpace(r.PointsPossible), 64)
+
+	for _, c := range r.Criteria.Criterion {
+		criterion := Criterion{
+			ID:               strings.TrimSpace(c.CriterionID),
+			Description:      strings.TrimSpace(c.Description),
+			LongDescription:  strings.TrimSpace(c.LongDescription),
+			Outcome:          strings.TrimSpace(c.LearningOutcomeIdentifierref),
+			IgnoreForScoring: parseBool(c.IgnoreForScoring),
+			Ratings:          make([]Rating, 0),
+		}
+		criterion.Points, _ = strconv.ParseFloat(strings.TrimSpace(c.Points), 64)
+		criterion.MasteryPoints, _ = strconv.ParseFloat(strings.TrimSpace(c.MasteryPoints), 64)
+
+		for _, r := range c.Ratings.Rating {
+			rating := Rating{
+				ID:              strings.TrimSpace(r.ID),
+				Description:     strings.TrimSpace(r.Description),
+				LongDescription: strings.TrimSpace(r.LongDescription),
+			}
+			rating.Points, _ = strconv.ParseFloat(strings.TrimSpace(r.Points), 64)
+
+			criterion.Ratings = append(criterion.Ratings, rating)
+		}
+
+		rubric.Criteria = append(rubric.Criteria, criterion)
+	}
+
+	return rubric
+}
+
+// RubricMarkdown returns the rubric as a Markdown table, laid out as Canvas shows it: a row per criterion, with its ratings from the highest to the lowest, then its points. The long descriptions, which are HTML, are rendered as plain text.
+func RubricMarkdown(r Rubric) string {
+	columns := 1
+	for _, c := range r.Criteria {
+		if len(c.Ratings) > columns {
+			columns = len(c.Ratings)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", r.Title)
+
+	b.WriteString("| Criteria | Ratings |" + strings.Repeat("  |", columns-1) + " Pts |\n")
+	b.WriteString("| --- |" + strings.Repeat(" --- |", columns) + " --- |\n")
+
+	for _, c := range r.Criteria {
+		cells := []string{tableCell(c.Description, c.LongDescription)}
+		if c.Outcome != "" {
+			cells[0] += fmt.Sprintf("<br>threshold: %s pts", formatPoints(c.MasteryPoints))
+		}
+
+		ratings := append([]Rating{}, c.Ratings...)
+		sort.SliceStable(ratings, func(i, j int) bool { return ratings[i].Points > ratings[j].Points })
+		for _, rating := range ratings {
+			cells = append(cells, tableCell(fmt.Sprintf("**%s** (%s pts)", rating.Description, formatPoints(rating.Points)), rating.LongDescription))
+		}
+		for i := len(c.Ratings); i < columns; i++ {
+			cells = append(cells, "")
+		}
+
+		points := formatPoints(c.Points) + " pts"
+		if c.IgnoreForScoring {
+			points = "--"
+		}
+		cells = append(cells, points)
+
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	if !r.HideScoreTotal {
+		fmt.Fprintf(&b, "\n**Total points: %s**\n", formatPoints(r.PointsPossible))
+	}
+
+	return b.String()
+}
+
+// tableCell returns the text and the plain text of the HTML description on a single Markdown table cell.
+func tableCell(text string, description string) string {
+	if description = commoncartridge.PlainText(description); description != "" {
+		text += "\n" + description
+	}
+
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(strings.TrimSpace(text), "\n", "<br>")
+}
+
+// RubricCSV writes the rubric as CSV, with a row per rating of each criterion, following a header row.
+func RubricCSV(w io.Writer, r Rubric) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Rubric", "Criterion ID", "Criterion", "Criterion Description", "Criterion Points", "Rating ID", "Rating", "Rating Description", "Rating Points"})
+
+	for _, c := range r.Criteria {
+		criterion := []string{r.Title, c.ID, c.Description, commoncartridge.PlainText(c.LongDescription), formatPoints(c.Points)}
+		if len(c.Ratings) == 0 {
+			cw.Write(append(criterion, "", "", "", ""))
+		}
+
+		for _, rating := range c.Ratings {
+			row := append(append([]string{}, criterion...), rating.ID, rating.Description, commoncartridge.PlainText(rating.LongDescription), formatPoints(rating.Points))
+			cw.Write(row)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatPoints returns the points with as few decimals as needed.
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
@@ -0,0 +1,115 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRubrics(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	rubrics, err := Rubrics(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(rubrics), 1)
+	rubric := rubrics[0]
+	assert.Equal(t, rubric.Identifier, "i7b3d56321ef7c255da82c508cab9bc7b")
+	assert.Equal(t, rubric.Title, "Example Course Rubric")
+	assert.Equal(t, rubric.PointsPossible, 10.0)
+	assert.Empty(t, rubric.Assignments)
+
+	require.Equal(t, len(rubric.Criteria), 2)
+	assert.Equal(t, rubric.Criteria[0].ID, "9_8244")
+	assert.Equal(t, rubric.Criteria[0].Ratings[1], Rating{ID: "blank_2", Description: "Example New Section added", Points: 3})
+	assert.Equal(t, rubric.Criteria[1].Outcome, "ia9db0c1f10f12a85e15326ca00562db3")
+	assert.Equal(t, rubric.Criteria[1].MasteryPoints, 4.0)
+	assert.Equal(t, len(rubric.Criteria[1].Ratings), 4)
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	rubrics, err = Rubrics(cc)
+	require.Nil(t, err)
+	assert.Equal(t, len(rubrics), 13)
+	assert.Equal(t, rubrics[0].PointsPossible, 20.0)
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	rubrics, err = Rubrics(cc)
+	require.Nil(t, err)
+	assert.Empty(t, rubrics)
+}
+
+func TestAssignmentRubric(t *testing.T) {
+	name := "iaa4b4fdadec793530c31c58a249e0879/assignment.xml"
+	r, err := load(t, testcc.SingleTestFile).Reader.Open(name)
+	require.Nil(t, err)
+	original, err := io.ReadAll(r)
+	require.Nil(t, err)
+
+	//-- none of the test files has a graded rubric, so one is added to an assignment
+	content := strings.Replace(string(original), "<muted>", "<rubric_identifierref>i7b3d56321ef7c255da82c508cab9bc7b</rubric_identifierref>\n      <muted>", 1)
+	cc := load(t, testcc.Rewrite(t, testcc.SingleTestFile, map[string]string{name: content}))
+
+	rubrics, err := Rubrics(cc)
+	require.Nil(t, err)
+	assert.Equal(t, rubrics[0].Assignments, []string{"iaa4b4fdadec793530c31c58a249e0879"})
+
+	assignments, err := cc.Assignments()
+	require.Nil(t, err)
+
+	found := false
+	for _, a := range assignments {
+		rubric, err := AssignmentRubric(cc, a)
+		if a.Identifier == "iaa4b4fdadec793530c31c58a249e0879" {
+			require.Nil(t, err)
+			assert.Equal(t, rubric.Title, "Example Course Rubric")
+			found = true
+		} else {
+			assert.NotNil(t, err)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestRubricMarkdown(t *testing.T) {
+	rubrics, err := Rubrics(load(t, testcc.SingleTestFile))
+	require.Nil(t, err)
+
+	md := RubricMarkdown(rubrics[0])
+	lines := strings.Split(md, "\n")
+	assert.Equal(t, lines[0], "## Example Course Rubric")
+	assert.Equal(t, lines[2], "| Criteria | Ratings |  |  |  | Pts |")
+	assert.Equal(t, lines[3], "| --- | --- | --- | --- | --- | --- |")
+	assert.Equal(t, lines[4], "| Example Rubric Criteria | **Full Marks** (5 pts) | **Example New Section added** (3 pts) | **No Marks** (0 pts) |  | 5 pts |")
+	assert.True(t, strings.HasPrefix(lines[5], "| Example Outcome<br>Lorem ipsum dolor sit amet"))
+	assert.Contains(t, lines[5], "<br>threshold: 4 pts | **Perfection!** (5 pts) |")
+	assert.Contains(t, md, "**Total points: 10**")
+
+	//-- the ratings are shown from the highest to the lowest, whatever their order in the export
+	md = RubricMarkdown(Rubric{Title: "Order", Criteria: []Criterion{{Description: "c", Points: 5, Ratings: []Rating{{Description: "Low", Points: 0}, {Description: "High", Points: 5}}}}})
+	assert.Contains(t, md, "| c | **High** (5 pts) | **Low** (0 pts) | 5 pts |")
+
+	md = RubricMarkdown(Rubric{Title: "Pipes", HideScoreTotal: true, Criteria: []Criterion{{Description: "a | b", IgnoreForScoring: true}}})
+	assert.Contains(t, md, "| a \\| b |  | -- |")
+	assert.NotContains(t, md, "Total points")
+}
+
+func TestRubricCSV(t *testing.T) {
+	rubrics, err := Rubrics(load(t, testcc.SingleTestFile))
+	require.Nil(t, err)
+
+	var buf bytes.Buffer
+	require.Nil(t, RubricCSV(&buf, rubrics[0]))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.Nil(t, err)
+	require.Equal(t, len(rows), 8)
+	assert.Equal(t, rows[0][0], "Rubric")
+	assert.Equal(t, rows[1], []string{"Example Course Rubric", "9_8244", "Example Rubric Criteria", "", "5", "blank", "Full Marks", "", "5"})
+	assert.Equal(t, rows[7][8], "0")
+}
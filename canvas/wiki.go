@@ -0,0 +1,228 @@
+package canvas
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+)
+
+// The substitution tokens with which Canvas replaces the links between the objects of a course in HTML content.
+const (
+	WikiReference   = "$WIKI_REFERENCE$"
+	ObjectReference = "$CANVAS_OBJECT_REFERENCE$"
+	CourseReference = "$CANVAS_COURSE_REFERENCE$"
+)
+
+// wikiDir is the folder in which Canvas exports the pages of a course.
+const wikiDir = "wiki_content"
+
+// metaPattern matches the `<meta>` headers in which Canvas exports the settings of a page.
+var metaPattern = regexp.MustCompile(`(?is)<meta\s+name\s*=\s*"([^"]*)"\s+content\s*=\s*"([^"]*)"\s*/?>`)
+
+// WikiPage is a page of a Canvas course, with the settings found in the `<meta>` headers of its HTML file.
+type WikiPage struct {
+	// Identifier is the identifier of the resource of the page.
+	Identifier string
+	// Path is the path of the HTML file of the page in the archive.
+	Path  string
+	Title string
+	// Body is the content of the `<body>` of the page.
+	Body          string
+	WorkflowState string
+	// EditingRoles are the roles allowed to edit the page, e.g. `teachers` or `students`.
+	EditingRoles []string
+	// FrontPage is true for the page shown on the home of the course.
+	FrontPage bool
+	// Meta are all the `<meta>` headers of the page, by name.
+	Meta map[string]string
+	// Links are the links of the body, in which the references to other objects of the course are resolved to their identifiers.
+	Links []commoncartridge.Link
+}
+
+// Published returns false when the page is hidden from learners.
+func (p WikiPage) Published() bool {
+	return published(p.WorkflowState)
+}
+
+// WikiPages returns the pages of a Canvas course, from the `wiki_content` folder, in the order of the manifest.
+func WikiPages(cc commoncartridge.IMSCC) ([]WikiPage, error) {
+	pages := make([]WikiPage, 0)
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return pages, err
+	}
+
+	lr, err := NewLinkResolver(cc, wikiDir)
+	if err != nil {
+		return pages, err
+	}
+
+	for _, r := range manifest.Resources.Resource {
+		if r.Type != "webcontent" || path.Dir(r.Href) != wikiDir || !strings.EqualFold(path.Ext(r.Href), ".html") {
+			continue
+		}
+
+		bytesArray, err := cc.ReadFile(r.Href)
+		if err != nil {
+			return pages, err
+		}
+
+		page := NewWikiPage(string(bytesArray))
+		page.Identifier, page.Path = r.Identifier, r.Href
+		page.Links = lr.Links(page.Body)
+
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+// FrontPage returns the page shown on the home of a Canvas course, or an error if it has none.
+func FrontPage(cc commoncartridge.IMSCC) (WikiPage, error) {
+	pages, err := WikiPages(cc)
+	if err != nil {
+		return WikiPage{}, err
+	}
+
+	for _, p := range pages {
+		if p.FrontPage {
+			return p, nil
+		}
+	}
+
+	return WikiPage{}, fmt.Errorf("the course has no front page")
+}
+
+// NewWikiPage returns the title, body and settings of the HTML of a page, without its links.
+func NewWikiPage(content string) WikiPage {
+	page := WikiPage{
+		Body:         content,
+		EditingRoles: make([]string, 0),
+		Meta:         make(map[string]string),
+		Links:        make([]commoncartridge.Link, 0),
+	}
+
+	page.Title = commoncartridge.HTMLTitle(content)
+	page.Body = strings.TrimSpace(commoncartridge.HTMLBody(content))
+
+	for _, m := range metaPattern.FindAllStringSubmatch(content, -1) {
+		page.Meta[m[1]] = html.UnescapeString(m[2])
+	}
+
+	page.WorkflowState = strings.TrimSpace(page.Meta["workflow_state"])
+	page.EditingRoles = parseList(page.Meta["editing_roles"])
+	page.FrontPage = parseBool(page.Meta["front_page"])
+
+	return page
+}
+
+// LinkResolver resolves the links found in the HTML content of a Canvas course, including the references that Canvas substitutes to the links between pages, assignments, quizzes, discussion topics and modules.
+type LinkResolver struct {
+	files commoncartridge.LinkResolver
+	// resources are the paths of the main file of the resources, by identifier.
+	resources map[string]string
+	// assignments are the identifiers of the resources of graded quizzes and discussion topics, by identifier of their assignment.
+	assignments map[string]string
+	modules     map[string]bool
+	syllabus    string
+}
+
+// NewLinkResolver returns a LinkResolver for the HTML of a Canvas course, which indexes the resources, assignments and modules that the tokens of Canvas refer to. The other links are resolved from the base directory, as commoncartridge.IMSCC.NewLinkResolver does.
+func NewLinkResolver(cc commoncartridge.IMSCC, base string) (LinkResolver, error) {
+	lr := LinkResolver{
+		files:       cc.NewLinkResolver(base),
+		resources:   make(map[string]string),
+		assignments: make(map[string]string),
+		modules:     make(map[string]bool),
+	}
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return lr, err
+	}
+
+	for _, r := range manifest.Resources.Resource {
+		lr.resources[r.Identifier] = commoncartridge.ResourceHref(r)
+
+		if strings.EqualFold(r.Intendeduse, "syllabus") {
+			lr.syllabus = r.Identifier
+		}
+	}
+
+	items, err := GradedItems(cc)
+	if err != nil {
+		return lr, err
+	}
+	for _, item := range items {
+		lr.assignments[strings.TrimSpace(item.Assignment.Identifier)] = item.Identifier
+	}
+
+	if cc.HasFile(moduleMetaFile) {
+		modules, err := Modules(cc)
+		if err != nil {
+			return lr, err
+		}
+		for _, m := range modules {
+			lr.modules[m.Identifier] = true
+		}
+	}
+
+	return lr, nil
+}
+
+// Links returns all the `src` and `href` references found in the given HTML, resolved to the files, resources and modules of the course.
+func (lr LinkResolver) Links(content string) []commoncartridge.Link {
+	links := lr.files.Links(content)
+	for i, l := range links {
+		links[i] = lr.Resolve(l.Attribute, l.Raw)
+	}
+
+	return links
+}
+
+// Resolve returns the Link corresponding to the raw value of the given attribute. A `$WIKI_REFERENCE$/pages/<slug>` refers to a page, a `$CANVAS_OBJECT_REFERENCE$/<type>/<id>` to an assignment, quiz, discussion topic or module, and a `$CANVAS_COURSE_REFERENCE$/assignments/syllabus` to the syllabus. The Identifier of the link is the one of the resource, or of the module, and is empty when the object is not part of the cartridge. Other links are resolved as commoncartridge.LinkResolver does.
+func (lr LinkResolver) Resolve(attribute, raw string) commoncartridge.Link {
+	ref := html.UnescapeString(strings.TrimSpace(raw))
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		ref = ref[:i]
+	}
+
+	link := commoncartridge.Link{Attribute: strings.ToLower(attribute), Raw: raw}
+	switch {
+	case strings.HasPrefix(ref, WikiReference):
+		slug := path.Base(strings.TrimPrefix(ref, WikiReference))
+		link = lr.files.Resolve(attribute, "/"+path.Join(wikiDir, slug+".html"))
+		link.Raw = raw
+
+	case strings.HasPrefix(ref, ObjectReference):
+		id := path.Base(strings.TrimPrefix(ref, ObjectReference))
+		if resource, ok := lr.assignments[id]; ok {
+			id = resource
+		}
+
+		if href, ok := lr.resources[id]; ok {
+			link.Identifier, link.Path = id, href
+		} else if lr.modules[id] {
+			link.Identifier = id
+		}
+
+	case strings.HasPrefix(ref, CourseReference):
+		if strings.TrimPrefix(ref, CourseReference) == "/assignments/syllabus" && lr.syllabus != "" {
+			link.Identifier, link.Path = lr.syllabus, lr.resources[lr.syllabus]
+		}
+
+	default:
+		link = lr.files.Resolve(attribute, raw)
+	}
+
+	return link
+}
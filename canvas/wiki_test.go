@@ -0,0 +1,100 @@
+package canvas
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWikiPages(t *testing.T) {
+	cc := load(t, testcc.SingleTestFile)
+	pages, err := WikiPages(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(pages), 2)
+	assert.Equal(t, pages[0].Identifier, "i4f8a2f796e0466d931a65228358e5124")
+	assert.Equal(t, pages[0].Path, "wiki_content/front-page.html")
+	assert.Equal(t, pages[0].Title, "Front Page")
+	assert.True(t, strings.HasPrefix(pages[0].Body, `<p style="text-align: center;">`))
+	assert.Equal(t, pages[0].EditingRoles, []string{"teachers"})
+	assert.Equal(t, pages[0].Meta["identifier"], "i4f8a2f796e0466d931a65228358e5124")
+	assert.True(t, pages[0].FrontPage)
+	assert.True(t, pages[0].Published())
+
+	assert.Equal(t, pages[1].Title, "Page hidden from Students")
+	assert.False(t, pages[1].FrontPage)
+	assert.False(t, pages[1].Published())
+
+	page, err := FrontPage(cc)
+	require.Nil(t, err)
+	assert.Equal(t, page.Identifier, pages[0].Identifier)
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "sample-public-sandbox-course-export.imscc"))
+	_, err = FrontPage(cc)
+	assert.NotNil(t, err)
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	pages, err = WikiPages(cc)
+	require.Nil(t, err)
+	assert.Empty(t, pages)
+}
+
+func TestWikiPageLinks(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	pages, err := WikiPages(cc)
+	require.Nil(t, err)
+
+	var page WikiPage
+	for _, p := range pages {
+		if p.Path == "wiki_content/start-here.html" {
+			page = p
+		}
+	}
+	require.Equal(t, page.Title, "Start Here")
+
+	links := make(map[string]commoncartridge.Link)
+	for _, l := range page.Links {
+		links[l.Raw] = l
+	}
+
+	assert.Equal(t, links["$CANVAS_COURSE_REFERENCE$/assignments/syllabus"].Identifier, "gb0046ad350ac24999f3b7cc2ed8690a2_syllabus")
+	assert.Equal(t, links["$CANVAS_OBJECT_REFERENCE$/discussion_topics/g90474b4e3752c93f75db81812c533c40"].Path, "g90474b4e3752c93f75db81812c533c40.xml")
+	assert.Equal(t, links["$CANVAS_OBJECT_REFERENCE$/quizzes/g8fba04fba5b6185e3c7c1c0c18586866"].Identifier, "g8fba04fba5b6185e3c7c1c0c18586866")
+
+	module := links["$CANVAS_OBJECT_REFERENCE$/modules/g4551625499da6aea821088935f922d0f"]
+	assert.Equal(t, module.Identifier, "g4551625499da6aea821088935f922d0f")
+	assert.Equal(t, module.Path, "")
+}
+
+func TestResolveReference(t *testing.T) {
+	cc := load(t, filepath.Join(testcc.AllTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	lr, err := NewLinkResolver(cc, wikiDir)
+	require.Nil(t, err)
+
+	link := lr.Resolve("href", "%24WIKI_REFERENCE%24/pages/virtual-meetings?module_item_id=1")
+	assert.Equal(t, link.Identifier, "g1fa6deda94f27e12545ea01bca2ef4ce")
+	assert.Equal(t, link.Path, "wiki_content/virtual-meetings.html")
+	assert.Equal(t, link.Raw, "%24WIKI_REFERENCE%24/pages/virtual-meetings?module_item_id=1")
+
+	//-- the assignment of a graded quiz refers to the resource of the quiz
+	link = lr.Resolve("href", "$CANVAS_OBJECT_REFERENCE$/assignments/gad43b26dff23167e1afbc8ada68b2e5f")
+	assert.Equal(t, link.Identifier, "g006d9854e56c5e86c4f9d0dd8a9a1ae6")
+
+	link = lr.Resolve("href", "$CANVAS_OBJECT_REFERENCE$/assignments/g935712a73da7c3a20a3b847b17c8bcb6")
+	assert.Equal(t, link.Identifier, "")
+	assert.False(t, link.External)
+
+	link = lr.Resolve("href", "$CANVAS_COURSE_REFERENCE$/modules")
+	assert.Equal(t, link.Identifier, "")
+
+	link = lr.Resolve("src", "$IMS-CC-FILEBASE$/Images/Icons/start.png?canvas_download=1")
+	assert.Equal(t, link.Path, "web_resources/Images/Icons/start.png")
+
+	link = lr.Resolve("href", "https://www.speedtest.net")
+	assert.True(t, link.External)
+}
@@ -36,3 +36,23 @@ type Cartridge interface {
 	// FindFile takes an identifier and returns the fs.File that the corresponding node refers to.
 	FindFile(string) (fs.File, error)
 }
+
+// CourseCartridge is a Cartridge which also reads the course it holds beyond the resources of the standard: its quizzes and question banks, the links of its HTML content, the system which exported it and its syllabus. It is kept apart from Cartridge, so that the types which implement Cartridge outside of this package still do.
+type CourseCartridge interface {
+	Cartridge
+
+	// Quizzes returns all the QTI assessments of the cartridge, as Quizzes with typed questions, choices and correct answers.
+	Quizzes() ([]Quiz, error)
+
+	// QuestionBanks returns the question banks and quiz definitions of a Canvas export, which can hold question types that the CC profile cannot carry.
+	QuestionBanks() ([]QuestionBank, error)
+
+	// Links takes an identifier and returns the links found in the HTML content of the corresponding resource, resolved to the files of the cartridge.
+	Links(string) ([]Link, error)
+
+	// Producer returns the learning management system or generator which most likely exported the cartridge, and the vendor extensions it holds.
+	Producer() (Producer, error)
+
+	// Syllabus returns the syllabus of the course, with its plain text, its resolved links and its outline.
+	Syllabus() (Syllabus, error)
+}
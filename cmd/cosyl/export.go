@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/formats"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// exportQuiz converts the quiz with the given id to another question format, printing the result on stdout and the report on stderr. The qti21 format is written as a zip package.
+//
+//	cosyl export-quiz --format gift <cartridge> <quiz-id>
+func exportQuiz(args []string) {
+	fs := flag.NewFlagSet("export-quiz", flag.ExitOnError)
+	format := fs.String("format", "gift", "output format, one of gift, aiken, moodle or qti21 (a zip package)")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		log.Fatal("usage: cosyl export-quiz --format gift <cartridge> <quiz-id>")
+	}
+
+	cc, err := commoncartridge.Load(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	qti, err := findQTI(cc, fs.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var report formats.Report
+	switch *format {
+	case "gift":
+		var out string
+		out, report = formats.GIFT(qti)
+		fmt.Print(out)
+	case "aiken":
+		var out string
+		out, report = formats.Aiken(qti)
+		fmt.Print(out)
+	case "moodle":
+		var out []byte
+		out, report, err = formats.MoodleXML(qti)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(out))
+	case "qti21":
+		var pkg formats.QTI21Package
+		pkg, report = formats.QTI21(qti)
+		if err := pkg.WriteZip(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatalf("unknown format: %s", *format)
+	}
+
+	fmt.Fprint(os.Stderr, report.String())
+}
+
+// findQTI returns the assessment whose resource identifier, or assessment ident, is the given id.
+func findQTI(cc commoncartridge.IMSCC, id string) (types.Questestinterop, error) {
+	if found, err := cc.Find(id); err == nil {
+		if qti, ok := found.(types.Questestinterop); ok {
+			return qti, nil
+		}
+	}
+
+	qtis, err := cc.QTIs()
+	if err != nil {
+		return types.Questestinterop{}, err
+	}
+
+	for _, qti := range qtis {
+		if qti.Assessment.Ident == id {
+			return qti, nil
+		}
+	}
+
+	return types.Questestinterop{}, fmt.Errorf("could not find quiz with id: %s", id)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/canvas"
+)
+
+// gradebook prints the gradebook template of a Canvas course as CSV, with a column per assignment, graded quiz and graded discussion.
+//
+//	cosyl gradebook <cartridge> > gradebook.csv
+func gradebook(args []string) {
+	if len(args) < 1 {
+		log.Fatal("usage: cosyl gradebook <cartridge>")
+	}
+
+	cc, err := commoncartridge.Load(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	groups, err := canvas.AssignmentGroups(cc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := canvas.GradebookCSV(os.Stdout, groups); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/canvas"
+)
+
+// ical prints the calendar of a Canvas course as an iCalendar file, with its events and the dates of its course, modules, assignments, quizzes and graded discussions.
+//
+//	cosyl ical <cartridge> > course.ics
+func ical(args []string) {
+	if len(args) < 1 {
+		log.Fatal("usage: cosyl ical <cartridge>")
+	}
+
+	cc, err := commoncartridge.Load(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	entries, err := canvas.Calendar(cc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := canvas.CalendarICS(os.Stdout, cc.Title(), entries); err != nil {
+		log.Fatal(err)
+	}
+}
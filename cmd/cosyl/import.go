@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/formats"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// importQuiz parses a GIFT or Aiken quiz and writes a copy of the cartridge in which it is added as a QTI assessment, printing the report on stderr.
+//
+//	cosyl import-quiz --format gift <cartridge> <quiz-file> <output>
+func importQuiz(args []string) {
+	fs := flag.NewFlagSet("import-quiz", flag.ExitOnError)
+	format := fs.String("format", "gift", "input format, one of gift or aiken")
+	title := fs.String("title", "", "title of the quiz, defaulting to the name of the quiz file")
+	fs.Parse(args)
+
+	if fs.NArg() < 3 {
+		log.Fatal("usage: cosyl import-quiz --format gift <cartridge> <quiz-file> <output>")
+	}
+
+	cc, err := commoncartridge.Load(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	text, err := os.ReadFile(fs.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *title == "" {
+		*title = strings.TrimSuffix(filepath.Base(fs.Arg(1)), filepath.Ext(fs.Arg(1)))
+	}
+
+	var qti types.Questestinterop
+	var report formats.Report
+	switch *format {
+	case "gift":
+		qti, report = formats.ParseGIFT(string(text), *title)
+	case "aiken":
+		qti, report = formats.ParseAiken(string(text), *title)
+	default:
+		log.Fatalf("unknown format: %s", *format)
+	}
+
+	var id string
+	err = writeCartridge(fs.Arg(2), func(w io.Writer) error {
+		id, err = cc.AddAssessment(w, qti)
+		return err
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Fprint(os.Stderr, report.String())
+	fmt.Printf("added assessment %s to %s\n", id, fs.Arg(2))
+}
+
+// writeCartridge writes a cartridge to a temporary file next to the output, which then replaces it, so that the output can be the cartridge being read, whose file is only replaced once it is fully read.
+func writeCartridge(output string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(output), "."+filepath.Base(output)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	//-- the temporary file is only readable by its owner, unlike the ones created by os.Create
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), output)
+}
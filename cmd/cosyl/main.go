@@ -7,8 +7,13 @@ import (
 	"io"
 	"log"
 	"os"
+	"time"
 
 	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/blackboard"
+	"github.com/commonsyllabi/commoncartridge/canvas"
+	"github.com/commonsyllabi/commoncartridge/d2l"
+	"github.com/commonsyllabi/commoncartridge/moodle"
 )
 
 var (
@@ -22,11 +27,44 @@ var (
 	topics      = flag.Bool("topics", false, "lists all topics in the cartridge")
 	qtis        = flag.Bool("qtis", false, "lists all quizzes in the cartridge")
 	ltis        = flag.Bool("ltis", false, "lists all basic LTI links in the cartridge")
+	quizzes     = flag.Bool("quizzes", false, "lists all quizzes in the cartridge, with their questions and answers, as serialized json")
+	banks       = flag.Bool("banks", false, "lists all Canvas question banks and quiz definitions in the cartridge, as serialized json")
+	course      = flag.Bool("course", false, "shows the settings of a Canvas course as serialized json")
+	syllabus    = flag.Bool("syllabus", false, "shows the syllabus of the course as plain text")
+	rubrics     = flag.Bool("rubrics", false, "lists the rubrics of a Canvas course as markdown tables")
+	pages       = flag.Bool("pages", false, "lists the pages of a Canvas course, with their links, as serialized json")
+	outcomes    = flag.Bool("outcomes", false, "shows the learning outcomes of a Canvas course, with the content aligned with them, as serialized json")
+	files       = flag.Bool("files", false, "lists the files of a Canvas course which learners can see, with their caption tracks, as serialized json")
+	sections    = flag.Bool("sections", false, "lists the sections of a Moodle course, with their summary, labels and activities, as serialized json")
+	producer    = flag.Bool("producer", false, "shows the learning management system which exported the cartridge, with its vendor extensions, as serialized json")
+	unresolved  = flag.Bool("unresolved", false, "lists the links of a Blackboard or D2L course which could not be resolved to a file of the cartridge")
 	find        = flag.String("f", "", "finds the resource with the related id")
 	file        = flag.String("F", "", "finds the file (i.e. webcontent) with the related id and returns the file as a fs.File")
+	links       = flag.String("L", "", "lists the links found in the HTML content of the resource with the related id")
 )
 
+// commands are the subcommands of cosyl, each taking the arguments following its name.
+var commands = map[string]func(args []string){
+	"export-quiz": exportQuiz,
+	"import-quiz": importQuiz,
+	"render-quiz": renderQuiz,
+	"gradebook":   gradebook,
+	"ical":        ical,
+	"shift-dates": shiftDates,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			command(os.Args[2:])
+			return
+		}
+	}
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: cosyl [flags] <cartridge>\n       cosyl export-quiz --format gift|aiken|moodle|qti21 <cartridge> <quiz-id>\n       cosyl import-quiz --format gift|aiken <cartridge> <quiz-file> <output>\n       cosyl render-quiz --format html|markdown [--answers] <cartridge> <quiz-id>\n       cosyl gradebook <cartridge>\n       cosyl ical <cartridge>\n       cosyl shift-dates [--offset days | --new-start date [--old-start date]] [--skip-weekends] <cartridge> <output>\n\nflags:\n")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	if *debug {
@@ -57,6 +95,13 @@ func main() {
 		fmt.Println(meta)
 	}
 
+	if *metadata || *items {
+		//-- the metadata and items are still printed without what the extensions which failed would have added
+		for _, err := range cc.ExtensionErrors() {
+			log.Printf("warning: %v", err)
+		}
+	}
+
 	if *items {
 		items, err := cc.Items()
 		if err != nil {
@@ -94,13 +139,17 @@ func main() {
 	}
 
 	if *assignments {
-		assignments, err := cc.Assignments()
+		assignments, err := canvas.Assignments(cc)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		for _, a := range assignments {
-			fmt.Printf("xml: %s title: %s\n", a.XMLName.Local, a.Title)
+			if a.Settings != nil && !a.Settings.DueAt.IsZero() {
+				fmt.Printf("xml: %s title: %s due: %s\n", a.XMLName.Local, a.Title, a.Settings.DueAt.Format(time.RFC3339))
+			} else {
+				fmt.Printf("xml: %s title: %s\n", a.XMLName.Local, a.Title)
+			}
 		}
 	}
 
@@ -122,7 +171,131 @@ func main() {
 		}
 
 		for _, qti := range qtis {
-			fmt.Printf("xml: %s title: %s items: %d\n", qti.XMLName.Local, qti.Assessment.Title, len(qti.Assessment.Section.Item))
+			fmt.Printf("xml: %s title: %s items: %d\n", qti.XMLName.Local, qti.Assessment.Title, len(qti.Items()))
+		}
+	}
+
+	if *quizzes {
+		quizzes, err := cc.Quizzes()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		data, _ := json.Marshal(quizzes)
+		fmt.Println(string(data))
+	}
+
+	if *banks {
+		banks, err := cc.QuestionBanks()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		data, _ := json.Marshal(banks)
+		fmt.Println(string(data))
+	}
+
+	if *course {
+		settings, err := canvas.CourseSettings(cc)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		data, _ := json.Marshal(settings)
+		fmt.Println(string(data))
+	}
+
+	if *syllabus {
+		syllabus, err := cc.Syllabus()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(syllabus.Text)
+	}
+
+	if *rubrics {
+		rubrics, err := canvas.Rubrics(cc)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, r := range rubrics {
+			fmt.Println(canvas.RubricMarkdown(r))
+		}
+	}
+
+	if *pages {
+		pages, err := canvas.WikiPages(cc)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		data, _ := json.Marshal(pages)
+		fmt.Println(string(data))
+	}
+
+	if *outcomes {
+		outcomes, err := canvas.LearningOutcomes(cc)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		data, _ := json.Marshal(outcomes)
+		fmt.Println(string(data))
+	}
+
+	if *files {
+		files, err := canvas.VisibleFiles(cc)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		data, _ := json.Marshal(files)
+		fmt.Println(string(data))
+	}
+
+	if *sections {
+		sections, err := moodle.Sections(cc)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		data, _ := json.Marshal(sections)
+		fmt.Println(string(data))
+	}
+
+	if *producer {
+		producer, err := cc.Producer()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		data, _ := json.Marshal(producer)
+		fmt.Println(string(data))
+	}
+
+	if *unresolved {
+		producer, err := cc.Producer()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var links []commoncartridge.UnresolvedLink
+		switch producer.Name {
+		case commoncartridge.BlackboardProducer:
+			links, err = blackboard.Unresolved(cc)
+		case commoncartridge.D2LProducer:
+			links, err = d2l.Unresolved(cc)
+		default:
+			log.Fatalf("unresolved links are only listed for Blackboard and D2L cartridges, not %q", producer.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, l := range links {
+			fmt.Printf("%s: %s=%s\n", l.Source, l.Attribute, l.Raw)
 		}
 	}
 
@@ -155,6 +328,17 @@ func main() {
 		fmt.Printf("%+v\n", res)
 	}
 
+	if *links != "" {
+		links, err := cc.Links(*links)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, l := range links {
+			fmt.Printf("%s: %s path: %s id: %s external: %t\n", l.Attribute, l.Raw, l.Path, l.Identifier, l.External)
+		}
+	}
+
 	if *file != "" {
 		file, err := cc.FindFile(*file)
 		if err != nil {
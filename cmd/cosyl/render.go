@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/formats"
+)
+
+// renderQuiz prints the quiz with the given id as a standalone document, with the images of the cartridge embedded and, optionally, an answer key.
+//
+//	cosyl render-quiz --format html --answers <cartridge> <quiz-id>
+func renderQuiz(args []string) {
+	fs := flag.NewFlagSet("render-quiz", flag.ExitOnError)
+	format := fs.String("format", "html", "output format, one of html or markdown")
+	answers := fs.Bool("answers", false, "appends the correct answers and the feedback of each question")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		log.Fatal("usage: cosyl render-quiz --format html [--answers] <cartridge> <quiz-id>")
+	}
+
+	cc, err := commoncartridge.Load(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	qti, err := findQTI(cc, fs.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	//-- the quiz can be found by its assessment ident, in which case images are resolved from the root of the cartridge
+	lr, err := cc.ResourceLinkResolver(fs.Arg(1))
+	if err != nil {
+		lr = cc.NewLinkResolver(".")
+	}
+
+	opts := formats.RenderOptions{AnswerKey: *answers, Links: &lr}
+	switch *format {
+	case "html":
+		fmt.Print(formats.HTML(qti, opts))
+	case "markdown", "md":
+		fmt.Print(formats.Markdown(qti, opts))
+	default:
+		log.Fatalf("unknown format: %s", *format)
+	}
+}
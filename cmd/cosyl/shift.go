@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/canvas"
+)
+
+// shiftDates writes a copy of the cartridge in which the dates of the course are moved to a new term, and prints the changes as CSV.
+//
+//	cosyl shift-dates --new-start 2024-09-02 --skip-weekends <cartridge> <output> > changes.csv
+func shiftDates(args []string) {
+	fs := flag.NewFlagSet("shift-dates", flag.ExitOnError)
+	offset := fs.Int("offset", 0, "number of days by which to move the dates, when the new start is not set")
+	oldStart := fs.String("old-start", "", "start of the old term, as YYYY-MM-DD, defaulting to the start of the course")
+	newStart := fs.String("new-start", "", "start of the new term, as YYYY-MM-DD")
+	skipWeekends := fs.Bool("skip-weekends", false, "moves the dates which land on a weekend to the following Monday")
+	timezone := fs.String("timezone", "UTC", "time zone in which weekends are determined, e.g. America/New_York")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		log.Fatal("usage: cosyl shift-dates [--offset days | --new-start date [--old-start date]] [--skip-weekends] <cartridge> <output>")
+	}
+
+	shift := canvas.DateShift{Offset: time.Duration(*offset) * 24 * time.Hour, SkipWeekends: *skipWeekends}
+
+	var err error
+	if *oldStart != "" {
+		if shift.OldStart, err = time.Parse("2006-01-02", *oldStart); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *newStart != "" {
+		if shift.NewStart, err = time.Parse("2006-01-02", *newStart); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if shift.Location, err = time.LoadLocation(*timezone); err != nil {
+		log.Fatal(err)
+	}
+
+	cc, err := commoncartridge.Load(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var changes []canvas.DateChange
+	err = writeCartridge(fs.Arg(1), func(w io.Writer) error {
+		changes, err = canvas.ShiftDates(cc, w, shift)
+		return err
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := canvas.DateChangesCSV(os.Stdout, changes); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintf(os.Stderr, "shifted %d dates in %s\n", len(changes), fs.Arg(1))
+}
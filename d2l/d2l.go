@@ -0,0 +1,71 @@
+// The d2l package decodes the conventions of the cartridges that D2L Brightspace exports: the `d2l_2p0` attributes which give the tool of each resource, and the `{orgUnitId}` placeholders that the links of its HTML content use to refer to the files of the course. Importing the package registers its Extension, which sets the type of the items of D2L cartridges.
+package d2l
+
+import (
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+)
+
+func init() {
+	commoncartridge.RegisterExtension(Extension{})
+}
+
+// Extension is the commoncartridge.Extension of D2L Brightspace exports.
+type Extension struct{}
+
+// Name returns `d2l`.
+func (Extension) Name() string {
+	return commoncartridge.D2LProducer
+}
+
+// Detect returns true when the manifest of the cartridge points to D2L Brightspace, e.g. because it uses the `d2l_2p0` namespace. Only the manifest is read, see commoncartridge.IMSCC.ManifestProducer.
+func (Extension) Detect(cc commoncartridge.IMSCC) bool {
+	producer, err := cc.ManifestProducer()
+	return err == nil && producer.Name == commoncartridge.D2LProducer
+}
+
+// Supports returns true for the vendor extensions of D2L Brightspace which this package decodes: its namespace, its resource types and its `{orgUnitId}` placeholders.
+func (Extension) Supports(v commoncartridge.VendorExtension) bool {
+	switch v.Kind {
+	case commoncartridge.NamespaceExtension:
+		return strings.Contains(v.Name, "desire2learn.com")
+	case commoncartridge.ResourceTypeExtension:
+		return strings.Contains(strings.ToLower(v.Name), "d2l")
+	case commoncartridge.TokenExtension:
+		return strings.EqualFold(v.Name, OrgUnitID)
+	}
+
+	return false
+}
+
+// ExtendMetadata leaves the metadata as is.
+func (Extension) ExtendMetadata(cc commoncartridge.IMSCC, meta *commoncartridge.Metadata) error {
+	return nil
+}
+
+// ExtendItems sets the type of the items whose resource belongs to a tool of D2L, e.g. `quiz`, `assignment` or `discussion`, and whether links open in a new window.
+func (Extension) ExtendItems(cc commoncartridge.IMSCC, items []commoncartridge.FullItem) ([]commoncartridge.FullItem, error) {
+	materials, err := Materials(cc)
+	if err != nil {
+		return items, err
+	}
+
+	byResource := make(map[string]Material)
+	for _, m := range materials {
+		byResource[m.Identifier] = m
+	}
+
+	var extend func(items []commoncartridge.FullItem)
+	extend = func(items []commoncartridge.FullItem) {
+		for i := range items {
+			if m, ok := byResource[items[i].Item.Identifierref]; ok {
+				items[i].Settings = &commoncartridge.ItemSettings{ContentType: m.Type, NewTab: m.LinkTarget == "_blank"}
+			}
+			extend(items[i].Children)
+		}
+	}
+	extend(items)
+
+	return items, nil
+}
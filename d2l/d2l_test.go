@@ -0,0 +1,85 @@
+package d2l
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// d2lFiles are the files of a cartridge following the conventions of D2L Brightspace exports.
+var d2lFiles = map[string]string{
+	"imsmanifest.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<manifest identifier="D2L_1" xmlns="http://www.imsglobal.org/xsd/imscp_v1p1" xmlns:d2l_2p0="http://desire2learn.com/xsd/d2lcp_v2p0">
+  <organizations>
+    <organization identifier="d2l_org">
+      <item identifier="ITEM_0">
+        <item identifier="ITEM_1" identifierref="RES_1"><title>Welcome</title></item>
+        <item identifier="ITEM_2" identifierref="RES_2"><title>Quiz 1</title></item>
+        <item identifier="ITEM_3" identifierref="RES_3"><title>Library</title></item>
+      </item>
+    </organization>
+  </organizations>
+  <resources>
+    <resource identifier="RES_1" type="webcontent" d2l_2p0:material_type="content" href="Content/Week 1/welcome.html" d2l_2p0:title="Welcome"><file href="Content/Week 1/welcome.html"/></resource>
+    <resource identifier="RES_2" type="webcontent" d2l_2p0:material_type="d2lquiz" href="quiz_d2l_1.xml" d2l_2p0:title="Quiz 1"/>
+    <resource identifier="RES_3" type="webcontent" d2l_2p0:material_type="contentlink" d2l_2p0:link_target="_blank" href="https://library.example.edu" d2l_2p0:title="Library"/>
+    <resource identifier="RES_4" type="webcontent" d2l_2p0:material_type="d2lbadges" href="badges_d2l.xml" d2l_2p0:title="Badges"/>
+    <resource identifier="RES_5" type="webcontent" href="Content/Week 1/notes.pdf"><file href="Content/Week 1/notes.pdf"/></resource>
+  </resources>
+</manifest>`,
+	"Content/Week 1/welcome.html": `<p>Read the <a href="/content/enforced/{orgUnitId}-ENG101/Week%201/notes.pdf">notes</a>,
+<a href="/d2l/common/dialogs/quickLink/quickLink.d2l?ou={orgUnitId}&amp;type=coursefile&amp;fileId=Week%201%2Fnotes.pdf">again</a>,
+take <a href="/d2l/common/dialogs/quickLink/quickLink.d2l?ou={orgUnitId}&amp;type=quiz&amp;rcode=RES_2">the quiz</a>,
+<a href="/d2l/common/dialogs/quickLink/quickLink.d2l?ou={orgUnitId}&amp;type=quiz&amp;rcode=RES_9">the other quiz</a>
+and <img src="/content/enforced/{orgUnitId}-ENG101/images/banner.png"/></p>`,
+	"Content/Week 1/notes.pdf": `%PDF`,
+	"quiz_d2l_1.xml":           `<questestinterop/>`,
+	"badges_d2l.xml":           `<badges/>`,
+}
+
+func TestDetect(t *testing.T) {
+	cc := load(t, testcc.Archive(t, d2lFiles))
+	require.Equal(t, len(cc.Extensions()), 1)
+	assert.Equal(t, cc.Extensions()[0].Name(), "d2l")
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	assert.Empty(t, cc.Extensions())
+}
+
+func TestExtendItems(t *testing.T) {
+	cc := load(t, testcc.Archive(t, d2lFiles))
+	items, err := cc.Items()
+	require.Nil(t, err)
+
+	types := make([]string, 0)
+	for _, item := range items {
+		require.NotNil(t, item.Settings)
+		types = append(types, item.Settings.ContentType)
+	}
+	assert.Equal(t, types, []string{"content", "quiz", "link"})
+	assert.True(t, items[2].Settings.NewTab)
+}
+
+func TestMaterials(t *testing.T) {
+	cc := load(t, testcc.Archive(t, d2lFiles))
+	materials, err := Materials(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(materials), 4)
+	assert.Equal(t, materials[0].Title, "Welcome")
+	assert.Equal(t, materials[0].Path, "Content/Week 1/welcome.html")
+	assert.Equal(t, materials[1].MaterialType, "d2lquiz")
+	assert.Equal(t, materials[1].Type, "quiz")
+	assert.Equal(t, materials[2].LinkTarget, "_blank")
+	assert.Equal(t, materials[3].Type, "d2lbadges")
+}
+
+func load(t *testing.T, p string) commoncartridge.IMSCC {
+	cc, err := commoncartridge.Load(p)
+	require.Nil(t, err)
+	return cc
+}
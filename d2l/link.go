@@ -0,0 +1,154 @@
+package d2l
+
+import (
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+)
+
+// OrgUnitID is the placeholder that D2L substitutes to the identifier of the course in the links of its HTML content, e.g. `/content/enforced/{orgUnitId}-ENG101/notes.pdf`.
+const OrgUnitID = "{orgUnitId}"
+
+var orgUnitPattern = regexp.MustCompile(`(?i)\{orgUnitId\}`)
+
+// LinkResolver resolves the links found in the HTML content of a D2L course, including those holding the `{orgUnitId}` placeholder.
+type LinkResolver struct {
+	files commoncartridge.LinkResolver
+	// names are the paths of the files of the archive, in order.
+	names []string
+	// resources are the paths of the main files of the resources, by their identifier.
+	resources map[string]string
+}
+
+// NewLinkResolver returns a LinkResolver for the HTML of a D2L Brightspace course, which indexes the files of the archive and the main files of the resources that the links of D2L refer to. The other links are resolved from the base directory, as commoncartridge.IMSCC.NewLinkResolver does.
+func NewLinkResolver(cc commoncartridge.IMSCC, base string) LinkResolver {
+	lr := LinkResolver{
+		files:     cc.NewLinkResolver(base),
+		names:     make([]string, 0),
+		resources: make(map[string]string),
+	}
+
+	for _, f := range cc.Reader.File {
+		if !strings.HasSuffix(f.Name, "/") {
+			lr.names = append(lr.names, f.Name)
+		}
+	}
+
+	if manifest, err := cc.Manifest(); err == nil {
+		for _, r := range manifest.Resources.Resource {
+			lr.resources[r.Identifier] = commoncartridge.ResourceHref(r)
+		}
+	}
+
+	return lr
+}
+
+// WithBase returns a copy of the LinkResolver whose relative links are resolved from another base directory, without indexing the files of the archive and of the resources again.
+func (lr LinkResolver) WithBase(base string) LinkResolver {
+	lr.files = lr.files.WithBase(base)
+	return lr
+}
+
+// Links returns all the `src` and `href` references found in the given HTML, resolved to the files of the cartridge.
+func (lr LinkResolver) Links(content string) []commoncartridge.Link {
+	links := lr.files.Links(content)
+	for i, l := range links {
+		links[i] = lr.Resolve(l.Attribute, l.Raw)
+	}
+
+	return links
+}
+
+// Resolve returns the Link corresponding to the raw value of the given attribute. A link holding the `{orgUnitId}` placeholder is resolved either from its query, when it is a quicklink to a course file (`type=coursefile&fileId=…`) or to a resource (`rcode=…`), or from the part of its path which follows the placeholder, matched against the end of the paths of the files of the archive. Other links are resolved as commoncartridge.LinkResolver does.
+func (lr LinkResolver) Resolve(attribute, raw string) commoncartridge.Link {
+	ref := html.UnescapeString(strings.TrimSpace(raw))
+	if !orgUnitPattern.MatchString(ref) {
+		return lr.files.Resolve(attribute, raw)
+	}
+
+	link := commoncartridge.Link{Attribute: strings.ToLower(attribute), Raw: raw}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return link
+	}
+
+	query := u.Query()
+	if rcode := query.Get("rcode"); rcode != "" {
+		if p, ok := lr.resources[rcode]; ok && p != "" {
+			link = lr.files.Resolve(attribute, "/"+p)
+			link.Raw, link.Identifier = raw, rcode
+		}
+		return link
+	}
+
+	rest := ""
+	if strings.EqualFold(query.Get("type"), "coursefile") {
+		rest = query.Get("fileId")
+	} else {
+		segments := strings.Split(u.Path, "/")
+		for i, s := range segments {
+			if orgUnitPattern.MatchString(s) {
+				rest = strings.Join(segments[i+1:], "/")
+				break
+			}
+		}
+	}
+
+	if p := lr.file(rest); p != "" {
+		link = lr.files.Resolve(attribute, "/"+p)
+		link.Raw = raw
+	}
+
+	return link
+}
+
+// file returns the path of the file of the archive which is, or ends with, the given path, or an empty string when there is none.
+func (lr LinkResolver) file(rest string) string {
+	rest = strings.TrimPrefix(path.Clean("/"+rest), "/")
+	if rest == "" {
+		return ""
+	}
+
+	for _, name := range lr.names {
+		if name == rest {
+			return name
+		}
+	}
+	for _, name := range lr.names {
+		if strings.HasSuffix(name, "/"+rest) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Unresolved returns the links of the HTML files of a D2L course which could not be resolved to a file of the cartridge, e.g. because the file they refer to was not part of the export.
+func Unresolved(cc commoncartridge.IMSCC) ([]commoncartridge.UnresolvedLink, error) {
+	unresolved := make([]commoncartridge.UnresolvedLink, 0)
+
+	lr := NewLinkResolver(cc, ".")
+	for _, f := range cc.Reader.File {
+		if ext := strings.ToLower(path.Ext(f.Name)); ext != ".html" && ext != ".htm" {
+			continue
+		}
+
+		bytesArray, err := cc.ReadFile(f.Name)
+		if err != nil {
+			return unresolved, err
+		}
+
+		for _, l := range lr.WithBase(path.Dir(f.Name)).Links(string(bytesArray)) {
+			if l.Unresolved() {
+				unresolved = append(unresolved, commoncartridge.UnresolvedLink{Source: f.Name, Link: l})
+			}
+		}
+	}
+
+	return unresolved, nil
+}
@@ -0,0 +1,51 @@
+package d2l
+
+import (
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	cc := load(t, testcc.Archive(t, d2lFiles))
+	lr := NewLinkResolver(cc, "Content/Week 1")
+
+	link := lr.Resolve("href", "/content/enforced/{orgUnitId}-ENG101/Week%201/notes.pdf")
+	assert.Equal(t, link.Path, "Content/Week 1/notes.pdf")
+	assert.Equal(t, link.Identifier, "RES_5")
+
+	link = lr.Resolve("href", "/d2l/common/dialogs/quickLink/quickLink.d2l?ou={orgUnitId}&amp;type=coursefile&amp;fileId=Week%201%2Fnotes.pdf")
+	assert.Equal(t, link.Path, "Content/Week 1/notes.pdf")
+
+	link = lr.Resolve("href", "/d2l/common/dialogs/quickLink/quickLink.d2l?ou={ORGUNITID}&amp;type=quiz&amp;rcode=RES_2")
+	assert.Equal(t, link.Path, "quiz_d2l_1.xml")
+	assert.Equal(t, link.Identifier, "RES_2")
+
+	link = lr.Resolve("src", "/content/enforced/{orgUnitId}-ENG101/images/banner.png")
+	assert.Empty(t, link.Path)
+
+	link = lr.Resolve("href", "notes.pdf")
+	assert.Equal(t, link.Path, "Content/Week 1/notes.pdf")
+
+	link = lr.WithBase("Content").Resolve("href", "Week 1/notes.pdf")
+	assert.Equal(t, link.Path, "Content/Week 1/notes.pdf")
+	assert.Equal(t, link.Identifier, "RES_5")
+}
+
+func TestUnresolved(t *testing.T) {
+	cc := load(t, testcc.Archive(t, d2lFiles))
+	unresolved, err := Unresolved(cc)
+	require.Nil(t, err)
+
+	raw := make([]string, 0)
+	for _, u := range unresolved {
+		assert.Equal(t, u.Source, "Content/Week 1/welcome.html")
+		raw = append(raw, u.Raw)
+	}
+	assert.Equal(t, raw, []string{
+		"/d2l/common/dialogs/quickLink/quickLink.d2l?ou={orgUnitId}&amp;type=quiz&amp;rcode=RES_9",
+		"/content/enforced/{orgUnitId}-ENG101/images/banner.png",
+	})
+}
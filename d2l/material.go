@@ -0,0 +1,90 @@
+package d2l
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// materialTypes are the types of the items of a D2L course, by the material type of their resource.
+var materialTypes = map[string]string{
+	"d2lquiz":            "quiz",
+	"d2ldropbox":         "assignment",
+	"d2ldiscussion":      "discussion",
+	"d2lquestionlibrary": "question_library",
+	"d2lsurvey":          "survey",
+	"d2lchecklist":       "checklist",
+	"d2lgrades":          "grades",
+	"d2lrubrics":         "rubrics",
+	"orgunitconfig":      "config",
+	"content":            "content",
+	"contentlink":        "link",
+}
+
+// Material is a resource of a D2L course, as described by the `d2l_2p0` attributes of the manifest.
+type Material struct {
+	Identifier string
+	Title      string
+	// MaterialType is the tool of the resource as D2L names it, e.g. `d2lquiz`, `d2ldropbox` or `content`.
+	MaterialType string
+	// Type is the kind of the resource, e.g. `quiz`, `assignment`, `discussion`, `content` or `link`. It is the MaterialType when it is unknown, or the resource type when the resource has no material type.
+	Type string
+	// Path is the path of the main file of the resource in the archive, if any.
+	Path string
+	// LinkTarget is the target of a link, e.g. `_blank`.
+	LinkTarget string
+}
+
+// Materials returns the resources of a D2L course which have a material type, or whose type belongs to D2L, in the order of the manifest.
+func Materials(cc commoncartridge.IMSCC) ([]Material, error) {
+	materials := make([]Material, 0)
+
+	manifest, err := readManifest(cc)
+	if err != nil {
+		return materials, err
+	}
+
+	for _, r := range manifest.Resources.Resource {
+		if r.MaterialType == "" && !strings.Contains(strings.ToLower(r.Type), "d2l") {
+			continue
+		}
+
+		m := Material{
+			Identifier:   r.Identifier,
+			Title:        strings.TrimSpace(r.Title),
+			MaterialType: r.MaterialType,
+			Type:         r.Type,
+			Path:         commoncartridge.ResourceHref(r.Resource),
+			LinkTarget:   r.LinkTarget,
+		}
+		if r.MaterialType != "" {
+			m.Type = r.MaterialType
+			if t, ok := materialTypes[strings.ToLower(r.MaterialType)]; ok {
+				m.Type = t
+			}
+		}
+
+		materials = append(materials, m)
+	}
+
+	return materials, nil
+}
+
+// readManifest decodes the manifest of the cartridge, with the attributes of the `d2l_2p0` namespace.
+func readManifest(cc commoncartridge.IMSCC) (types.D2LManifest, error) {
+	var manifest types.D2LManifest
+
+	bytesArray, err := cc.ReadFile(cc.ManifestPath())
+	if err != nil {
+		return manifest, err
+	}
+
+	if err := xml.Unmarshal(bytesArray, &manifest); err != nil {
+		return manifest, fmt.Errorf("could not read the manifest: %w", err)
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,139 @@
+package commoncartridge
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// Extension supports the conventions that a learning management system adds on top of the IMSCC standard in the cartridges it exports, such as the `course_settings` folder of Canvas. Extensions live in their own packages, which register them when imported, e.g.:
+//
+//	import _ "github.com/commonsyllabi/commoncartridge/canvas"
+type Extension interface {
+	// Name returns the name of the learning management system, e.g. `canvas`.
+	Name() string
+	// Detect returns true when the cartridge follows the conventions of the extension.
+	Detect(cc IMSCC) bool
+	// ExtendMetadata completes the metadata of the cartridge with the information found in its extension files. When it returns an error, the metadata is left as the manifest has it.
+	ExtendMetadata(cc IMSCC, meta *Metadata) error
+	// ExtendItems sets the settings of the modules and module items of the cartridge, whose top-level items are given, and returns them. It may add the modules and items that the manifest leaves out, and reorder them. It returns an error before changing any of the items, which are then left as the manifest has them.
+	ExtendItems(cc IMSCC, items []FullItem) ([]FullItem, error)
+}
+
+// ItemSettings are the settings that a learning management system attaches to a module, or to an item of a module, such as its publication state or the requirements to complete it.
+type ItemSettings struct {
+	// WorkflowState is the publication state of the module or item, e.g. `active` or `unpublished`.
+	WorkflowState string
+	// Position is the position of the module in the course, or of the item in its module, starting at 1.
+	Position int
+	// ContentType is the kind of content of an item, e.g. `WikiPage`, `DiscussionTopic` or `Quizzes::Quiz`.
+	ContentType string
+	// Indent is the indentation level of an item within its module.
+	Indent int
+	// URL is the address of an external link or tool.
+	URL    string
+	NewTab bool
+	// RequireSequentialProgress is true when the items of a module must be completed in order.
+	RequireSequentialProgress bool
+	Locked                    bool
+	// UnlockAt is the date at which a module becomes available, or the zero time.
+	UnlockAt time.Time
+	// Prerequisites are the identifiers of the modules which must be completed before a module.
+	Prerequisites []string
+	// CompletionRequirements are the requirements to complete a module, or the requirement to complete an item.
+	CompletionRequirements []CompletionRequirement
+}
+
+// CompletionRequirement is what a learner needs to do with an item to complete a module.
+type CompletionRequirement struct {
+	// Item is the identifier of the item.
+	Item string
+	// Type is the kind of requirement, e.g. `must_view`, `must_submit`, `must_contribute` or `min_score`.
+	Type string
+	// MinScore is the score to reach for a `min_score` requirement.
+	MinScore float64
+}
+
+// Published returns false when the module or item is hidden from learners.
+func (s ItemSettings) Published() bool {
+	return s.WorkflowState != "unpublished" && s.WorkflowState != "deleted"
+}
+
+var (
+	extensionsMu sync.RWMutex
+	extensions   = make([]Extension, 0)
+)
+
+// RegisterExtension makes an extension available to all cartridges. Registering an extension with the name of an already registered one replaces it.
+func RegisterExtension(e Extension) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+
+	for i, registered := range extensions {
+		if registered.Name() == e.Name() {
+			extensions[i] = e
+			return
+		}
+	}
+
+	extensions = append(extensions, e)
+}
+
+// Extensions returns the registered extensions whose conventions the cartridge follows, in the order they were registered.
+func (cc IMSCC) Extensions() []Extension {
+	//-- detecting an extension may look the registered extensions up, e.g. through Producer, so the lock is not held while doing so
+	extensionsMu.RLock()
+	registered := append([]Extension{}, extensions...)
+	extensionsMu.RUnlock()
+
+	detected := make([]Extension, 0)
+	for _, e := range registered {
+		if e.Detect(cc) {
+			detected = append(detected, e)
+		}
+	}
+
+	return detected
+}
+
+// HasFile returns true when the archive of the cartridge contains a file at the given path.
+func (cc IMSCC) HasFile(name string) bool {
+	for _, f := range cc.Reader.File {
+		if f.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ManifestPath returns the path of the imsmanifest.xml file in the archive of the cartridge, which is not always at its root, or an empty string if there is none.
+func (cc IMSCC) ManifestPath() string {
+	if f := cc.manifestFile(); f != nil {
+		return f.Name
+	}
+
+	return ""
+}
+
+// ReadFile returns the content of the file of the archive of the cartridge at the given path.
+func (cc IMSCC) ReadFile(name string) ([]byte, error) {
+	file, err := cc.Reader.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// ResourceHref returns the path of the main file of a resource: its href, or its first file when it has none, as for the XML documents of discussion topics and weblinks.
+func ResourceHref(r types.Resource) string {
+	if r.Href == "" && len(r.File) > 0 {
+		return r.File[0].Href
+	}
+
+	return r.Href
+}
@@ -0,0 +1,123 @@
+package commoncartridge
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testExtension marks the modules of the cartridges which have a manifest.
+type testExtension struct{}
+
+func (testExtension) Name() string { return "test" }
+
+func (testExtension) Detect(cc IMSCC) bool { return cc.HasFile("imsmanifest.xml") }
+
+func (testExtension) ExtendMetadata(cc IMSCC, meta *Metadata) error {
+	meta.CourseCode = "TEST-101"
+	return nil
+}
+
+func (testExtension) ExtendItems(cc IMSCC, items []FullItem) ([]FullItem, error) {
+	for i := range items {
+		items[i].Settings = &ItemSettings{WorkflowState: "unpublished", Position: i + 1}
+	}
+	return items, nil
+}
+
+func TestExtensions(t *testing.T) {
+	cc := load(t, singleTestFile).(IMSCC)
+	assert.Empty(t, cc.Extensions())
+
+	RegisterExtension(testExtension{})
+	defer func() { extensions = make([]Extension, 0) }()
+	RegisterExtension(testExtension{})
+	require.Equal(t, len(cc.Extensions()), 1)
+
+	meta, err := cc.Metadata()
+	require.Nil(t, err)
+	assert.Contains(t, meta, `"CourseCode":"TEST-101"`)
+
+	items, err := cc.Items()
+	require.Nil(t, err)
+	require.NotNil(t, items[1].Settings)
+	assert.Equal(t, items[1].Settings.Position, 2)
+	assert.False(t, items[1].Settings.Published())
+	assert.Empty(t, cc.ExtensionErrors())
+}
+
+// failingExtension fails on all the cartridges, after changing their metadata.
+type failingExtension struct{}
+
+func (failingExtension) Name() string { return "failing" }
+
+func (failingExtension) Detect(cc IMSCC) bool { return true }
+
+func (failingExtension) ExtendMetadata(cc IMSCC, meta *Metadata) error {
+	meta.Title = "changed"
+	return fmt.Errorf("malformed settings")
+}
+
+func (failingExtension) ExtendItems(cc IMSCC, items []FullItem) ([]FullItem, error) {
+	return nil, fmt.Errorf("malformed settings")
+}
+
+func TestFailingExtension(t *testing.T) {
+	cc := load(t, singleTestFile).(IMSCC)
+	base, err := cc.Metadata()
+	require.Nil(t, err)
+
+	RegisterExtension(failingExtension{})
+	RegisterExtension(testExtension{})
+	defer func() { extensions = make([]Extension, 0) }()
+
+	meta, err := cc.Metadata()
+	require.Nil(t, err)
+	assert.NotContains(t, meta, `"Title":"changed"`)
+	assert.Contains(t, meta, `"CourseCode":"TEST-101"`)
+	assert.NotEqual(t, meta, base)
+
+	items, err := cc.Items()
+	require.Nil(t, err)
+	require.NotNil(t, items[0].Settings, "the other extensions should still apply")
+
+	errs := cc.ExtensionErrors()
+	require.Equal(t, len(errs), 2)
+	assert.EqualError(t, errs[0], "extension failing: malformed settings")
+	assert.EqualError(t, errs[1], "extension failing: malformed settings")
+}
+
+func TestHasFile(t *testing.T) {
+	cc := load(t, singleTestFile).(IMSCC)
+	assert.True(t, cc.HasFile("course_settings/canvas_export.txt"))
+	assert.False(t, cc.HasFile("course_settings"))
+}
+
+func TestReadFile(t *testing.T) {
+	cc := load(t, singleTestFile).(IMSCC)
+	content, err := cc.ReadFile("course_settings/canvas_export.txt")
+	require.Nil(t, err)
+	assert.NotEmpty(t, content)
+
+	_, err = cc.ReadFile("course_settings")
+	assert.NotNil(t, err)
+
+	assert.Equal(t, cc.ManifestPath(), "imsmanifest.xml")
+}
+
+func TestResourceHref(t *testing.T) {
+	r := types.Resource{Href: "page.html"}
+	assert.Equal(t, ResourceHref(r), "page.html")
+
+	r.Href = ""
+	assert.Equal(t, ResourceHref(r), "")
+
+	r.File = append(r.File, struct {
+		Text string `xml:",chardata"`
+		Href string `xml:"href,attr"`
+	}{Href: "topic.xml"})
+	assert.Equal(t, ResourceHref(r), "topic.xml")
+}
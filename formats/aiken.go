@@ -0,0 +1,155 @@
+package formats
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// aikenLetters are the labels of the choices of an Aiken question, which limits the number of choices per question.
+const aikenLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Aiken converts a QTI assessment to the Aiken text format. Aiken only supports questions with a single correct choice, so only multiple choice and true/false questions are exported, as plain text. The choices which give part of the score are reported as warnings, since they become incorrect.
+func Aiken(qti types.Questestinterop) (string, Report) {
+	quiz := commoncartridge.NewQuiz(qti)
+	report := Report{Format: "aiken", Skipped: make([]Skipped, 0)}
+
+	var b strings.Builder
+	for _, q := range quiz.Questions {
+		question, err := aikenQuestion(q)
+		if err != nil {
+			report.skip(q, err.Error())
+			continue
+		}
+
+		b.WriteString(question + "\n")
+		report.Exported++
+		if len(q.PartialCredit) > 0 {
+			report.warn(q, fmt.Sprintf("%d choice(s) give part of the score, which Aiken does not support, and are now incorrect", len(q.PartialCredit)))
+		}
+	}
+
+	return b.String(), report
+}
+
+// aikenQuestion returns the question text, its lettered choices and the answer line.
+func aikenQuestion(q commoncartridge.Question) (string, error) {
+	if q.Type != commoncartridge.MultipleChoice && q.Type != commoncartridge.TrueFalse {
+		return "", fmt.Errorf("unsupported question type: %s", q.Type)
+	}
+
+	if len(correctChoices(q)) != 1 {
+		return "", fmt.Errorf("expected a single correct choice, found %d", len(correctChoices(q)))
+	}
+
+	if len(q.Choices) > len(aikenLetters) {
+		return "", fmt.Errorf("too many choices: %d", len(q.Choices))
+	}
+
+	text := commoncartridge.SingleLine(q.Text)
+	if text == "" {
+		return "", fmt.Errorf("empty question text")
+	}
+
+	var b strings.Builder
+	b.WriteString(text + "\n")
+
+	answer := ""
+	for i, c := range q.Choices {
+		letter := string(aikenLetters[i])
+		fmt.Fprintf(&b, "%s. %s\n", letter, commoncartridge.SingleLine(c.Text))
+		if c.Correct {
+			answer = letter
+		}
+	}
+	fmt.Fprintf(&b, "ANSWER: %s\n", answer)
+
+	return b.String(), nil
+}
+
+var (
+	aikenChoicePattern = regexp.MustCompile(`^([A-Z])[.)]\s+(.*)$`)
+	aikenAnswerPattern = regexp.MustCompile(`^ANSWER:\s*([A-Z])\s*$`)
+)
+
+// ParseAiken builds a CC-profile QTI assessment with the given title from an Aiken text. Questions whose only choices are `True` and `False` are imported as true/false questions, and the others as multiple choice questions. Questions without choices, or whose answer is not one of their choices, are reported as skipped.
+func ParseAiken(text string, title string) (types.Questestinterop, Report) {
+	report := Report{Format: "aiken", Skipped: make([]Skipped, 0)}
+	quiz := commoncartridge.Quiz{Title: title, Questions: make([]commoncartridge.Question, 0)}
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" && len(lines) == 0 {
+			continue
+		}
+		lines = append(lines, line)
+
+		if match := aikenAnswerPattern.FindStringSubmatch(line); match != nil {
+			q, err := parseAikenQuestion(lines, ident(title, strconv.Itoa(len(quiz.Questions)+len(report.Skipped)), strings.Join(lines, "\n")))
+			if err != nil {
+				report.skip(q, err.Error())
+			} else {
+				quiz.Questions = append(quiz.Questions, q)
+				report.Exported++
+			}
+			lines = nil
+		}
+	}
+
+	if len(lines) > 0 {
+		report.skip(commoncartridge.Question{Title: lines[0]}, "missing ANSWER line")
+	}
+
+	return QTI(quiz), report
+}
+
+// parseAikenQuestion parses the lines of a question, from its text to its `ANSWER:` line.
+func parseAikenQuestion(lines []string, id string) (commoncartridge.Question, error) {
+	q := commoncartridge.Question{
+		Identifier: id,
+		Type:       commoncartridge.MultipleChoice,
+		Choices:    make([]commoncartridge.Choice, 0),
+		Answers:    make([]string, 0),
+		Points:     1,
+	}
+
+	text := make([]string, 0)
+	for _, line := range lines[:len(lines)-1] {
+		if match := aikenChoicePattern.FindStringSubmatch(line); match != nil {
+			q.Choices = append(q.Choices, commoncartridge.Choice{Identifier: match[1], Text: match[2]})
+		} else if len(q.Choices) == 0 && line != "" {
+			text = append(text, line)
+		}
+	}
+	q.Text = strings.Join(text, "\n")
+
+	if q.Text == "" {
+		return q, fmt.Errorf("empty question text")
+	}
+	if len(q.Choices) == 0 {
+		return q, fmt.Errorf("no choices")
+	}
+
+	answer := aikenAnswerPattern.FindStringSubmatch(lines[len(lines)-1])[1]
+	for i, c := range q.Choices {
+		if c.Identifier == answer {
+			q.Choices[i].Correct = true
+			q.Answers = append(q.Answers, answer)
+		}
+	}
+
+	if len(q.Answers) == 0 {
+		return q, fmt.Errorf("answer %s is not one of the choices", answer)
+	}
+
+	if len(q.Choices) == 2 && strings.EqualFold(q.Choices[0].Text, "true") && strings.EqualFold(q.Choices[1].Text, "false") {
+		q.Type = commoncartridge.TrueFalse
+	}
+
+	return q, nil
+}
@@ -0,0 +1,183 @@
+// The formats package converts QTI assessments to and from the question formats used by other learning management systems, such as GIFT, Aiken and Moodle XML.
+package formats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+)
+
+// Report summarizes a conversion, listing the questions which could not be represented in the target format. When importing a quiz, Exported is the number of questions imported.
+type Report struct {
+	Format   string
+	Exported int
+	Skipped  []Skipped
+	// Warnings are the questions which were exported but need manual attention, e.g. because part of them could not be converted.
+	Warnings []Skipped
+}
+
+// Skipped is a question which was left out of a conversion, or which needs attention after it, along with the reason why.
+type Skipped struct {
+	Identifier string
+	Title      string
+	Reason     string
+}
+
+// String returns a human-readable summary of the report.
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: exported %d question(s), skipped %d\n", r.Format, r.Exported, len(r.Skipped))
+	for _, s := range r.Skipped {
+		fmt.Fprintf(&b, "- %s (%s): %s\n", s.Identifier, s.Title, s.Reason)
+	}
+	for _, w := range r.Warnings {
+		fmt.Fprintf(&b, "! %s (%s): %s\n", w.Identifier, w.Title, w.Reason)
+	}
+
+	return b.String()
+}
+
+// warn records a question which was exported but needs manual attention.
+func (r *Report) warn(q commoncartridge.Question, reason string) {
+	r.Warnings = append(r.Warnings, Skipped{
+		Identifier: q.Identifier,
+		Title:      q.Title,
+		Reason:     reason,
+	})
+}
+
+// skip records a question which could not be exported.
+func (r *Report) skip(q commoncartridge.Question, reason string) {
+	r.Skipped = append(r.Skipped, Skipped{
+		Identifier: q.Identifier,
+		Title:      q.Title,
+		Reason:     reason,
+	})
+}
+
+// questionName returns the title of the question, or its identifier if it has no title.
+func questionName(q commoncartridge.Question) string {
+	if strings.TrimSpace(q.Title) != "" {
+		return strings.TrimSpace(q.Title)
+	}
+
+	return q.Identifier
+}
+
+// correctChoices returns the choices of a question which are marked as correct.
+func correctChoices(q commoncartridge.Question) []commoncartridge.Choice {
+	correct := make([]commoncartridge.Choice, 0)
+	for _, c := range q.Choices {
+		if c.Correct {
+			correct = append(correct, c)
+		}
+	}
+
+	return correct
+}
+
+// moodleGrades are the percentages of the grade that Moodle lets an answer give, which are also the only ones that GIFT and Moodle XML files can hold. The opposites of these are the penalties it lets an answer give.
+var moodleGrades = []float64{100, 90, 83.33333, 80, 75, 70, 66.66667, 60, 50, 40, 33.33333, 30, 25, 20, 16.66667, 14.28571, 12.5, 11.11111, 10, 5}
+
+// fraction returns the percentage of the grade given by each of the n correct choices of a question, with at most 5 decimals.
+func fraction(n int) string {
+	return formatGrade(100 / float64(n))
+}
+
+// moodleFraction returns the percentage of the grade that Moodle lets an answer give which is the nearest to the one given by each of the n correct choices of a question, and whether it is the same.
+func moodleFraction(n int) (string, bool) {
+	grade := moodleGrade(100 / float64(n))
+	return grade, grade == fraction(n)
+}
+
+// moodleGrade returns the percentage of the grade that Moodle lets an answer give which is the nearest to the given one.
+func moodleGrade(share float64) string {
+	nearest := moodleGrades[0]
+	for _, g := range moodleGrades {
+		if math.Abs(g-share) < math.Abs(nearest-share) {
+			nearest = g
+		}
+	}
+
+	return formatGrade(nearest)
+}
+
+// partialAnswers returns the accepted texts of a question which only give part of the score, in alphabetical order.
+func partialAnswers(q commoncartridge.Question) []string {
+	choices := make(map[string]bool)
+	for _, c := range q.Choices {
+		choices[c.Identifier] = true
+	}
+
+	answers := make([]string, 0)
+	for a := range q.PartialCredit {
+		if !choices[a] {
+			answers = append(answers, a)
+		}
+	}
+	sort.Strings(answers)
+
+	return answers
+}
+
+// warnRoundedCredit records the choices and answers of a question which give part of the score, in a percentage of the grade that Moodle does not offer, and which were rounded to the nearest one it does.
+func warnRoundedCredit(q commoncartridge.Question, report *Report) {
+	for _, c := range q.Choices {
+		if c.Credit > 0 && moodleGrade(c.Credit) != formatGrade(c.Credit) {
+			report.warn(q, fmt.Sprintf("choice %s gives %s%% of the grade, which Moodle does not offer, and was rounded to %s%%", c.Identifier, formatGrade(c.Credit), moodleGrade(c.Credit)))
+		}
+	}
+	for _, a := range partialAnswers(q) {
+		if credit := q.PartialCredit[a]; moodleGrade(credit) != formatGrade(credit) {
+			report.warn(q, fmt.Sprintf("the answer %q gives %s%% of the grade, which Moodle does not offer, and was rounded to %s%%", a, formatGrade(credit), moodleGrade(credit)))
+		}
+	}
+}
+
+// formatGrade writes a percentage with at most 5 decimals.
+func formatGrade(grade float64) string {
+	f := strconv.FormatFloat(grade, 'f', 5, 64)
+	return strings.TrimRight(strings.TrimRight(f, "0"), ".")
+}
+
+// roundedWarning returns the warning of a multiple response question whose correct choices give a percentage of the grade that Moodle does not offer.
+func roundedWarning(n int, grade string) string {
+	return fmt.Sprintf("each of the %d correct choices gives %s%% of the grade, which Moodle does not offer, and was rounded to %s%%, while each incorrect choice still takes the whole grade away", n, fraction(n), grade)
+}
+
+// trueFalseWarning returns the warning of a true/false question whose answer was read from the position of its correct choice.
+func trueFalseWarning(truth bool) string {
+	if truth {
+		return "the text of the correct choice is not recognized as true or false, the answer is true since the choice comes first"
+	}
+
+	return "the text of the correct choice is not recognized as true or false, the answer is false since the choice does not come first"
+}
+
+// trueTexts and falseTexts are the texts, in lower case, that the choices of a true/false question have in the languages the learning management systems export most.
+var (
+	trueTexts  = map[string]bool{"true": true, "t": true, "yes": true, "vrai": true, "wahr": true, "richtig": true, "verdadero": true, "verdadeiro": true, "vero": true, "waar": true, "juist": true, "sí": true, "oui": true, "ja": true}
+	falseTexts = map[string]bool{"false": true, "f": true, "no": true, "faux": true, "falsch": true, "falso": true, "onwaar": true, "non": true, "nein": true, "nee": true}
+)
+
+// isTrue returns true when the correct choice of a true/false question stands for true, and whether its text says so. When the text is not recognized, e.g. in a language that is not listed, the choice stands for true if it comes first, since Canvas always puts true first.
+func isTrue(q commoncartridge.Question) (truth bool, recognized bool) {
+	for i, c := range q.Choices {
+		if !c.Correct {
+			continue
+		}
+
+		text := strings.ToLower(commoncartridge.SingleLine(c.Text))
+		if trueTexts[text] || falseTexts[text] {
+			return trueTexts[text], true
+		}
+
+		return i == 0, false
+	}
+
+	return false, false
+}
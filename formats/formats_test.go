@@ -0,0 +1,502 @@
+package formats
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const qtiExample = "../types/examples/qti.xml"
+
+func TestGIFT(t *testing.T) {
+	out, report := GIFT(loadQTI(t))
+
+	assert.Equal(t, report.Exported, 6)
+	assert.Empty(t, report.Skipped)
+
+	assert.Contains(t, out, "$CATEGORY: $course$/ALL QUESTION TYPES QUIZ")
+	assert.Contains(t, out, "::Question::[html]<div><p>How many letters does the word, \"RED\" have?</p></div>{")
+	assert.Contains(t, out, "\t~1#<p>add 2</p>\n")
+	assert.Contains(t, out, "\t=3#<p>good!</p>\n")
+	assert.Contains(t, out, "####<p>alright</p>}")
+	assert.Contains(t, out, "<div><p>Dogs are insects</p></div>{FALSE}")
+	assert.Contains(t, out, "\t~%33.33333%A\n")
+	assert.Contains(t, out, "\t~%-100%1\n")
+	assert.Contains(t, out, "{=Paris =Paris, France}")
+	assert.Contains(t, out, "{=*neon* =*argon*}")
+	assert.Contains(t, out, "::Tell me what you think::")
+}
+
+func TestGIFTWarnings(t *testing.T) {
+	response := commoncartridge.Question{Identifier: "q2", Type: commoncartridge.MultipleResponse, Text: "Pick", Points: 1}
+	for i := 0; i < 12; i++ {
+		id := fmt.Sprintf("c%d", i)
+		response.Choices = append(response.Choices, commoncartridge.Choice{Identifier: id, Text: id, Correct: i < 11})
+		if i < 11 {
+			response.Answers = append(response.Answers, id)
+		}
+	}
+	quiz := commoncartridge.Quiz{Identifier: "quiz", Title: "Warnings", Questions: []commoncartridge.Question{
+		{Identifier: "q1", Type: commoncartridge.FillInTheBlank, Text: "Capital?", Answers: []string{"Paris"}, CaseSensitive: true, Points: 1},
+		response,
+	}}
+
+	out, report := GIFT(QTI(quiz))
+	assert.Equal(t, report.Exported, 2)
+	require.Equal(t, len(report.Warnings), 2)
+	assert.Equal(t, report.Warnings[0].Identifier, "q1")
+	assert.Contains(t, report.Warnings[0].Reason, "case-insensitive")
+	assert.Equal(t, report.Warnings[1].Identifier, "q2")
+	assert.Contains(t, report.Warnings[1].Reason, "9.09091%")
+	assert.Contains(t, report.Warnings[1].Reason, "rounded to 10%")
+	assert.Contains(t, out, "\t~%10%c0\n")
+	assert.NotContains(t, out, "9.09091")
+
+	data, report, err := MoodleXML(QTI(quiz))
+	require.Nil(t, err)
+	require.Equal(t, len(report.Warnings), 1)
+	assert.Equal(t, report.Warnings[0].Identifier, "q2")
+	assert.Contains(t, report.Warnings[0].Reason, "rounded to 10%")
+
+	var moodle MoodleQuiz
+	require.Nil(t, xml.Unmarshal(data, &moodle))
+	assert.Equal(t, moodle.Question[2].Answer[0].Fraction, "10")
+	assert.Equal(t, moodle.Question[2].Answer[11].Fraction, "-100")
+
+	_, report = GIFT(loadQTI(t))
+	assert.Empty(t, report.Warnings)
+}
+
+func TestTrueFalseTexts(t *testing.T) {
+	trueFalse := func(id string, first, second string, firstCorrect bool) commoncartridge.Question {
+		return commoncartridge.Question{Identifier: id, Type: commoncartridge.TrueFalse, Text: id, Points: 1, Choices: []commoncartridge.Choice{
+			{Identifier: "1", Text: first, Correct: firstCorrect},
+			{Identifier: "2", Text: second, Correct: !firstCorrect},
+		}}
+	}
+	quiz := commoncartridge.Quiz{Identifier: "quiz", Title: "True or false", Questions: []commoncartridge.Question{
+		trueFalse("fr", "Vrai", "Faux", true),
+		trueFalse("de", "Wahr", "Falsch", false),
+		trueFalse("fi", "Oikein", "Väärin", true),
+	}}
+
+	out, report := GIFT(QTI(quiz))
+	assert.Contains(t, out, "::fr::[html]fr{TRUE}")
+	assert.Contains(t, out, "::de::[html]de{FALSE}")
+	assert.Contains(t, out, "::fi::[html]fi{TRUE}")
+	require.Equal(t, len(report.Warnings), 1)
+	assert.Equal(t, report.Warnings[0].Identifier, "fi")
+	assert.Contains(t, report.Warnings[0].Reason, "not recognized")
+
+	data, report, err := MoodleXML(QTI(quiz))
+	require.Nil(t, err)
+	require.Equal(t, len(report.Warnings), 1)
+	assert.Equal(t, report.Warnings[0].Identifier, "fi")
+
+	var moodle MoodleQuiz
+	require.Nil(t, xml.Unmarshal(data, &moodle))
+	assert.Equal(t, moodle.Question[1].Answer[0].Fraction, "100")
+	assert.Equal(t, moodle.Question[2].Answer[1].Fraction, "100")
+	assert.Equal(t, moodle.Question[3].Answer[0].Fraction, "100")
+}
+
+func TestGIFTEscape(t *testing.T) {
+	assert.Equal(t, giftEscape("a = b: {c} ~d #e\nf"), `a \= b\: \{c\} \~d \#e f`)
+}
+
+func TestAiken(t *testing.T) {
+	out, report := Aiken(loadQTI(t))
+
+	assert.Equal(t, report.Exported, 2)
+	assert.Equal(t, len(report.Skipped), 4)
+	assert.Equal(t, report.Skipped[0].Identifier, "ia87c485e2981093da808cd01d157c30b")
+
+	questions := strings.Split(strings.TrimSpace(out), "\n\n")
+	require.Equal(t, len(questions), 2)
+	assert.Equal(t, questions[0], "How many letters does the word, \"RED\" have?\nA. 1\nB. 2\nC. 3\nD. 4\nANSWER: C")
+	assert.Equal(t, questions[1], "Dogs are insects\nA. True\nB. False\nANSWER: B")
+}
+
+func TestMoodleXML(t *testing.T) {
+	out, report, err := MoodleXML(loadQTI(t))
+	require.Nil(t, err)
+
+	assert.Equal(t, report.Exported, 6)
+
+	var quiz MoodleQuiz
+	err = xml.Unmarshal(out, &quiz)
+	require.Nil(t, err)
+	require.Equal(t, len(quiz.Question), 7)
+
+	assert.Equal(t, quiz.Question[0].Type, "category")
+	assert.Equal(t, quiz.Question[1].Type, "multichoice")
+	assert.Equal(t, quiz.Question[1].Single, "true")
+	assert.Equal(t, quiz.Question[1].Answer[2].Fraction, "100")
+	assert.Equal(t, quiz.Question[2].Type, "truefalse")
+	assert.Equal(t, quiz.Question[2].Answer[1].Fraction, "100")
+	assert.Equal(t, quiz.Question[3].Single, "false")
+	assert.Equal(t, quiz.Question[4].Type, "essay")
+	assert.Equal(t, quiz.Question[5].Type, "shortanswer")
+	assert.Equal(t, quiz.Question[5].DefaultGrade, "2")
+	assert.Equal(t, quiz.Question[6].Answer[0].Text, "*neon*")
+}
+
+func TestParseGIFT(t *testing.T) {
+	text := `// a comment
+$CATEGORY: $course$/Geography
+
+::Capital::[html]<p>What is the capital of France?</p>{
+	=Paris#<p>right</p>
+	~Lyon#<p>wrong</p>
+	####<p>Paris has been the capital since 987.</p>
+}
+
+Which are prime numbers?{~%50%2 ~%50%3 ~%-100%4}
+
+The sky is green.{F#it is not#indeed}
+
+Name a noble gas.{=*neon* =*argon*}
+
+Who wrote 1984?{=Orwell =George Orwell}
+
+Write an essay about rivers.{}
+
+2 + 2 \= ?{#4}
+
+Match the capitals.{=France -> Paris =Italy -> Rome}
+`
+
+	qti, report := ParseGIFT(text, "Imported")
+	assert.Equal(t, report.Exported, 6)
+	require.Equal(t, len(report.Skipped), 2)
+	assert.Equal(t, report.Skipped[0].Reason, "numerical questions are not supported")
+	assert.Equal(t, report.Skipped[1].Reason, "matching questions are not supported")
+
+	quiz := commoncartridge.NewQuiz(qti)
+	assert.Equal(t, quiz.Title, "Geography")
+	require.Equal(t, len(quiz.Questions), 6)
+
+	mc := quiz.Questions[0]
+	assert.Equal(t, mc.Type, commoncartridge.MultipleChoice)
+	assert.Equal(t, mc.Title, "Capital")
+	assert.Equal(t, mc.Text, "<p>What is the capital of France?</p>")
+	assert.Equal(t, mc.Answers, []string{"1"})
+	assert.Equal(t, mc.Choices[1].Feedback, "<p>wrong</p>")
+	assert.Equal(t, mc.Feedback.General, "<p>Paris has been the capital since 987.</p>")
+
+	mr := quiz.Questions[1]
+	assert.Equal(t, mr.Type, commoncartridge.MultipleResponse)
+	assert.Equal(t, mr.Answers, []string{"1", "2"})
+
+	tf := quiz.Questions[2]
+	assert.Equal(t, tf.Type, commoncartridge.TrueFalse)
+	assert.True(t, tf.Choices[1].Correct)
+	assert.Equal(t, tf.Feedback.Incorrect, "it is not")
+	assert.Equal(t, tf.Feedback.Correct, "indeed")
+
+	assert.Equal(t, quiz.Questions[3].Type, commoncartridge.PatternMatch)
+	assert.Equal(t, quiz.Questions[3].Answers, []string{"neon", "argon"})
+	assert.Equal(t, quiz.Questions[4].Type, commoncartridge.FillInTheBlank)
+	assert.Equal(t, quiz.Questions[4].Answers, []string{"Orwell", "George Orwell"})
+	assert.Equal(t, quiz.Questions[5].Type, commoncartridge.Essay)
+
+	again, _ := ParseGIFT(text, "Imported")
+	assert.Equal(t, again.Items()[0].Ident, qti.Items()[0].Ident)
+}
+
+func TestGIFTRoundTrip(t *testing.T) {
+	out, _ := GIFT(loadQTI(t))
+
+	qti, report := ParseGIFT(out, "")
+	assert.Equal(t, report.Exported, 6)
+	assert.Empty(t, report.Skipped)
+
+	again, _ := GIFT(qti)
+	assert.Equal(t, again, out)
+}
+
+func TestParseAiken(t *testing.T) {
+	text := `What is the correct answer to this question?
+A. Is it this one?
+B) Maybe this answer?
+C. Possibly this one?
+ANSWER: B
+Is the earth flat?
+A. True
+B. False
+ANSWER: B
+
+Which one is a vowel?
+A. b
+B. c
+ANSWER: E
+`
+
+	qti, report := ParseAiken(text, "Aiken quiz")
+	assert.Equal(t, report.Exported, 2)
+	require.Equal(t, len(report.Skipped), 1)
+	assert.Equal(t, report.Skipped[0].Reason, "answer E is not one of the choices")
+
+	quiz := commoncartridge.NewQuiz(qti)
+	assert.Equal(t, quiz.Title, "Aiken quiz")
+	require.Equal(t, len(quiz.Questions), 2)
+	assert.Equal(t, quiz.Questions[0].Type, commoncartridge.MultipleChoice)
+	assert.Equal(t, quiz.Questions[0].Text, "What is the correct answer to this question?")
+	assert.Equal(t, quiz.Questions[0].Answers, []string{"B"})
+	assert.Equal(t, quiz.Questions[0].Choices[1].Text, "Maybe this answer?")
+	assert.Equal(t, quiz.Questions[1].Type, commoncartridge.TrueFalse)
+}
+
+func TestAikenRoundTrip(t *testing.T) {
+	out, _ := Aiken(loadQTI(t))
+
+	qti, report := ParseAiken(out, "ALL QUESTION TYPES QUIZ")
+	assert.Equal(t, report.Exported, 2)
+
+	again, _ := Aiken(qti)
+	assert.Equal(t, again, out)
+}
+
+func TestQTI(t *testing.T) {
+	quiz := commoncartridge.NewQuiz(loadQTI(t))
+
+	out, err := types.MarshalDocument(QTI(quiz))
+	require.Nil(t, err)
+
+	var qti types.Questestinterop
+	err = xml.Unmarshal(out, &qti)
+	require.Nil(t, err)
+
+	back := commoncartridge.NewQuiz(qti)
+	assert.Equal(t, back.Identifier, quiz.Identifier)
+	assert.Equal(t, back.Settings.Profile, "cc.exam.v0p1")
+	require.Equal(t, len(back.Questions), len(quiz.Questions))
+	for i, q := range quiz.Questions {
+		assert.Equal(t, back.Questions[i].Type, q.Type)
+		assert.Equal(t, back.Questions[i].Answers, q.Answers)
+		assert.Equal(t, back.Questions[i].Points, q.Points)
+		assert.Equal(t, back.Questions[i].CaseSensitive, q.CaseSensitive)
+		assert.Equal(t, len(back.Questions[i].Choices), len(q.Choices))
+	}
+	assert.Equal(t, back.Questions[0].Feedback, quiz.Questions[0].Feedback)
+}
+
+func TestPartialCredit(t *testing.T) {
+	q := commoncartridge.Question{
+		Identifier: "q1",
+		Type:       commoncartridge.MultipleChoice,
+		Text:       "The capital of France?",
+		Choices: []commoncartridge.Choice{
+			{Identifier: "A", Text: "Paris", Correct: true},
+			{Identifier: "B", Text: "Lyon", Credit: 50},
+			{Identifier: "C", Text: "Berlin"},
+		},
+		Answers:       []string{"A"},
+		PartialCredit: map[string]float64{"B": 50},
+		Points:        1,
+	}
+
+	answers, err := giftAnswers(q)
+	require.Nil(t, err)
+	assert.Contains(t, answers, "=Paris")
+	assert.Contains(t, answers, "~%50%Lyon")
+	assert.Contains(t, answers, "~Berlin")
+
+	mq, err := moodleQuestion(q)
+	require.Nil(t, err)
+	require.Equal(t, len(mq.Answer), 3)
+	assert.Equal(t, []string{mq.Answer[0].Fraction, mq.Answer[1].Fraction, mq.Answer[2].Fraction}, []string{"100", "50", "0"})
+
+	qti := QTI(commoncartridge.Quiz{Identifier: "quiz1", Questions: []commoncartridge.Question{q}})
+	back := commoncartridge.NewQuiz(qti)
+	require.Equal(t, len(back.Questions), 1)
+	assert.Equal(t, back.Questions[0].Answers, []string{"A"})
+	assert.Equal(t, back.Questions[0].PartialCredit, map[string]float64{"B": 50})
+
+	q.Choices[1].Credit, q.PartialCredit["B"] = 45, 45
+	var report Report
+	warnRoundedCredit(q, &report)
+	require.Equal(t, len(report.Warnings), 1)
+	assert.Contains(t, report.Warnings[0].Reason, "rounded to 50%")
+}
+
+func TestQTI21(t *testing.T) {
+	pkg, report := QTI21(loadQTI(t))
+	assert.Equal(t, report.Exported, 6)
+	assert.Empty(t, report.Skipped)
+	require.Equal(t, len(report.Warnings), 5)
+	assert.Equal(t, report.Warnings[1].Identifier, "i5ccb43157aa894608ffdeb23aace604a")
+	assert.Contains(t, report.Warnings[4].Reason, "allows 1 attempt(s)")
+
+	require.Equal(t, len(pkg.Items), 6)
+	mc := pkg.Items[0]
+	assert.Equal(t, mc.Href, "items/ib5fe05d8f6665faf019cffb4846fa301.xml")
+	assert.Equal(t, mc.ResponseDeclaration.CorrectResponse.Value, []string{"CHOICE_2798"})
+	assert.Equal(t, mc.ResponseProcessing.Template, MatchCorrect)
+	assert.Equal(t, mc.ItemBody.ChoiceInteraction.SimpleChoice[0].Identifier, "CHOICE_5713")
+
+	mr := pkg.Items[2]
+	assert.Equal(t, mr.ResponseDeclaration.Cardinality, "multiple")
+	assert.Equal(t, len(mr.ResponseDeclaration.CorrectResponse.Value), 3)
+	assert.Equal(t, mr.ItemBody.ChoiceInteraction.MaxChoices, 0)
+
+	essay := pkg.Items[3]
+	assert.Nil(t, essay.ResponseProcessing)
+	assert.NotNil(t, essay.ItemBody.ExtendedTextInteraction)
+	assert.Contains(t, essay.ItemBody.Content, `height="128"></img>`)
+
+	fib := pkg.Items[4]
+	assert.Equal(t, fib.ResponseDeclaration.BaseType, "string")
+	assert.Equal(t, fib.ResponseDeclaration.Mapping.MapEntry[1].MapKey, "Paris, France")
+	assert.Equal(t, fib.ResponseProcessing.Template, MapResponse)
+
+	section := pkg.Test.TestPart.AssessmentSection[0]
+	require.Equal(t, len(section.AssessmentItemRef), 6)
+	assert.Equal(t, section.AssessmentItemRef[4].Weight[0].Value, "2")
+
+	out, err := xml.Marshal(mc)
+	require.Nil(t, err)
+	assert.Contains(t, string(out), `<itemBody><div><div><p>How many letters does the word, &#34;RED&#34; have?</p></div></div><choiceInteraction`)
+}
+
+func TestQTI21Zip(t *testing.T) {
+	pkg, _ := QTI21(loadQTI(t))
+
+	var buf bytes.Buffer
+	err := pkg.WriteZip(&buf)
+	require.Nil(t, err)
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.Nil(t, err)
+	require.Equal(t, len(r.File), 8)
+	assert.Equal(t, r.File[0].Name, "imsmanifest.xml")
+
+	f, err := r.Open("imsmanifest.xml")
+	require.Nil(t, err)
+	var manifest cpManifest
+	err = xml.NewDecoder(f).Decode(&manifest)
+	require.Nil(t, err)
+	assert.Equal(t, manifest.Resources[0].Type, "imsqti_test_xmlv2p1")
+	assert.Equal(t, len(manifest.Resources[0].Dependency), 6)
+
+	f, err = r.Open(pkg.Items[0].Href)
+	require.Nil(t, err)
+	var item AssessmentItem
+	err = xml.NewDecoder(f).Decode(&item)
+	require.Nil(t, err)
+	assert.Equal(t, item.Identifier, "ib5fe05d8f6665faf019cffb4846fa301")
+}
+
+func TestQTI21UniqueIdentifiers(t *testing.T) {
+	qti := loadQTI(t)
+	items := qti.Assessment.Section[0].Item
+	items[0].Ident, items[1].Ident = "a b", "a/b"
+
+	pkg, report := QTI21(qti)
+	require.Equal(t, report.Exported, 6)
+	assert.Equal(t, pkg.Items[0].Identifier, "a_b")
+	assert.Equal(t, pkg.Items[1].Identifier, "a_b_2")
+	assert.Equal(t, pkg.Items[1].Href, "items/a_b_2.xml")
+
+	var buf bytes.Buffer
+	require.Nil(t, pkg.WriteZip(&buf))
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.Nil(t, err)
+	assert.Equal(t, len(r.File), 8)
+
+	q := commoncartridge.Question{
+		Identifier: "q1",
+		Type:       commoncartridge.MultipleChoice,
+		Choices:    []commoncartridge.Choice{{Identifier: "1 a", Text: "One"}, {Identifier: "1/a", Text: "Two", Correct: true}},
+	}
+	item, err := assessmentItem(q, make(identifiers), &Report{})
+	require.Nil(t, err)
+	assert.Equal(t, item.ItemBody.ChoiceInteraction.SimpleChoice[0].Identifier, "CHOICE_1_a")
+	assert.Equal(t, item.ItemBody.ChoiceInteraction.SimpleChoice[1].Identifier, "CHOICE_1_a_2")
+	assert.Equal(t, item.ResponseDeclaration.CorrectResponse.Value, []string{"CHOICE_1_a_2"})
+}
+
+func TestXHTML(t *testing.T) {
+	out, err := xhtml("<p>a&nbsp;b<br>c</p>")
+	require.Nil(t, err)
+	assert.Equal(t, out, "<p>a\u00a0b<br></br>c</p>")
+
+	_, err = xhtml("<p>unclosed</div>")
+	assert.NotNil(t, err)
+}
+
+func TestReport(t *testing.T) {
+	_, report := Aiken(loadQTI(t))
+	assert.True(t, strings.HasPrefix(report.String(), "aiken: exported 2 question(s), skipped 4\n"))
+}
+
+func loadQTI(t *testing.T) types.Questestinterop {
+	var qti types.Questestinterop
+	bytes, err := os.ReadFile(qtiExample)
+	require.Nil(t, err)
+
+	err = xml.Unmarshal(bytes, &qti)
+	require.Nil(t, err)
+	return qti
+}
+
+func TestHTML(t *testing.T) {
+	out := HTML(loadQTI(t), RenderOptions{})
+
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.Contains(t, out, "<h1>ALL QUESTION TYPES QUIZ</h1>")
+	assert.Contains(t, out, "<li class=\"question\" id=\"q1\">\n<div class=\"text\"><div><p>How many letters does the word, \"RED\" have?</p></div></div>")
+	assert.Contains(t, out, "<li class=\"question\" id=\"q6\">")
+	assert.Contains(t, out, "<div class=\"essay\"></div>")
+	assert.NotContains(t, out, "Answer key")
+
+	out = HTML(loadQTI(t), RenderOptions{AnswerKey: true})
+	assert.Contains(t, out, "<h2>Answer key</h2>")
+	assert.Contains(t, out, "<p><strong>Answer:</strong> (c) 3</p>")
+	assert.Contains(t, out, "<p><strong>Answer:</strong> Paris; Paris, France</p>")
+	assert.Contains(t, out, "<p><strong>Feedback (a):</strong> <p>add 2</p></p>")
+	assert.Contains(t, out, "<em>graded manually</em>")
+}
+
+func TestMarkdown(t *testing.T) {
+	out := Markdown(loadQTI(t), RenderOptions{AnswerKey: true})
+
+	assert.True(t, strings.HasPrefix(out, "# ALL QUESTION TYPES QUIZ\n\n_6 question(s)"))
+	assert.Contains(t, out, "1. How many letters does the word, \"RED\" have? _(1 point)_\n\n   - (a) 1\n")
+	assert.Contains(t, out, "   - (c) 3\n")
+	assert.Contains(t, out, "## Answer key\n\n1. **Answer:** (c) 3\n")
+	assert.Contains(t, out, "4. Write an Essay. Any Essay\n   ![profile.jpg](<https://lorprod.instructure.com/users/2/files/1018/preview?verifier=")
+	assert.Contains(t, out, "   - **Feedback (a):** add 2\n")
+	assert.Contains(t, out, "6. **Answer:** neon; argon\n")
+}
+
+func TestEmbedImages(t *testing.T) {
+	cc, err := commoncartridge.Load("../test_files/test_01.imscc")
+	require.Nil(t, err)
+	lr := cc.NewLinkResolver("wiki_content")
+	opts := RenderOptions{Links: &lr}
+
+	content := `<p>Look: <img src="$IMS-CC-FILEBASE$/Example%20File.jpg" alt="example"> <img src="missing.png"></p>`
+	embedded := embedImages(content, opts)
+	assert.Contains(t, embedded, `<img src="data:image/jpeg;base64,`)
+	assert.Contains(t, embedded, `<img src="missing.png">`)
+
+	text := markdownText(content, opts)
+	assert.True(t, strings.HasPrefix(text, "Look: ![example](<data:image/jpeg;base64,"))
+	assert.True(t, strings.HasSuffix(text, "![](<missing.png>)"))
+}
+
+func TestChoiceLabel(t *testing.T) {
+	assert.Equal(t, choiceLabel(0), "a")
+	assert.Equal(t, choiceLabel(25), "z")
+	assert.Equal(t, choiceLabel(26), "aa")
+}
@@ -0,0 +1,390 @@
+package formats
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// giftEscaper escapes the characters which have a meaning in the GIFT syntax.
+var giftEscaper = strings.NewReplacer(`\`, `\\`, `~`, `\~`, `=`, `\=`, `#`, `\#`, `{`, `\{`, `}`, `\}`, `:`, `\:`)
+
+// GIFT converts a QTI assessment to the GIFT text format, as imported by Moodle. Pattern match questions are exported as short answers with wildcards. The questions which GIFT cannot fully express are reported as warnings: case-sensitive answers, since matching a short answer is case-insensitive in GIFT, and multiple response questions whose correct choices share a grade that Moodle does not offer, which is rounded to the nearest one it does. Each incorrect choice of a multiple response question takes the whole grade away, as the response must be exactly the correct choices in the CC profile, while the choices and answers which give part of the score keep their share of the grade, rounded in the same way. So are the true/false questions whose correct choice has a text that is not recognized as true or false, whose answer is then read from its position.
+func GIFT(qti types.Questestinterop) (string, Report) {
+	quiz := commoncartridge.NewQuiz(qti)
+	report := Report{Format: "gift", Skipped: make([]Skipped, 0)}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n", commoncartridge.SingleLine(quiz.Title))
+	fmt.Fprintf(&b, "$CATEGORY: $course$/%s\n\n", commoncartridge.SingleLine(quiz.Title))
+
+	for _, q := range quiz.Questions {
+		answers, err := giftAnswers(q)
+		if err != nil {
+			report.skip(q, err.Error())
+			continue
+		}
+
+		fmt.Fprintf(&b, "::%s::[html]%s%s\n\n", giftEscape(questionName(q)), giftEscape(q.Text), answers)
+		report.Exported++
+		warnRoundedCredit(q, &report)
+
+		switch q.Type {
+		case commoncartridge.FillInTheBlank, commoncartridge.PatternMatch:
+			if q.CaseSensitive {
+				report.warn(q, "GIFT short answers are case-insensitive, the accepted answers now match in any case")
+			}
+		case commoncartridge.MultipleResponse:
+			if correct := len(correctChoices(q)); correct > 0 {
+				if weight, exact := moodleFraction(correct); !exact {
+					report.warn(q, roundedWarning(correct, weight))
+				}
+			}
+		case commoncartridge.TrueFalse:
+			if truth, recognized := isTrue(q); !recognized {
+				report.warn(q, trueFalseWarning(truth))
+			}
+		}
+	}
+
+	return b.String(), report
+}
+
+// giftAnswers returns the answer block of a question, between curly braces.
+func giftAnswers(q commoncartridge.Question) (string, error) {
+	var b strings.Builder
+	b.WriteString("{")
+
+	switch q.Type {
+	case commoncartridge.MultipleChoice:
+		if len(correctChoices(q)) != 1 {
+			return "", fmt.Errorf("expected a single correct choice, found %d", len(correctChoices(q)))
+		}
+
+		for _, c := range q.Choices {
+			prefix := "~"
+			if c.Correct {
+				prefix = "="
+			} else if c.Credit > 0 {
+				prefix = "~%" + moodleGrade(c.Credit) + "%"
+			}
+
+			b.WriteString("\n\t" + prefix + giftEscape(c.Text) + giftFeedback(c.Feedback, q, c.Correct))
+		}
+		b.WriteString("\n")
+	case commoncartridge.MultipleResponse:
+		correct := len(correctChoices(q))
+		if correct == 0 {
+			return "", fmt.Errorf("no correct choice")
+		}
+
+		weight, _ := moodleFraction(correct)
+		for _, c := range q.Choices {
+			prefix := "~%-100%"
+			if c.Correct {
+				prefix = "~%" + weight + "%"
+			} else if c.Credit > 0 {
+				prefix = "~%" + moodleGrade(c.Credit) + "%"
+			}
+
+			b.WriteString("\n\t" + prefix + giftEscape(c.Text) + giftFeedback(c.Feedback, q, c.Correct))
+		}
+		b.WriteString("\n")
+	case commoncartridge.TrueFalse:
+		correct := correctChoices(q)
+		if len(correct) != 1 {
+			return "", fmt.Errorf("expected a single correct choice, found %d", len(correct))
+		}
+
+		if truth, _ := isTrue(q); truth {
+			b.WriteString("TRUE")
+		} else {
+			b.WriteString("FALSE")
+		}
+	case commoncartridge.FillInTheBlank, commoncartridge.PatternMatch:
+		if len(q.Answers) == 0 {
+			return "", fmt.Errorf("no accepted answer")
+		}
+
+		answers := make([]string, 0)
+		for _, a := range q.Answers {
+			if q.Type == commoncartridge.PatternMatch {
+				a = "*" + a + "*"
+			}
+			answers = append(answers, "="+giftEscape(a))
+		}
+		for _, a := range partialAnswers(q) {
+			grade := moodleGrade(q.PartialCredit[a])
+			if q.Type == commoncartridge.PatternMatch {
+				a = "*" + a + "*"
+			}
+			answers = append(answers, "=%"+grade+"%"+giftEscape(a))
+		}
+		b.WriteString(strings.Join(answers, " "))
+	case commoncartridge.Essay:
+	default:
+		return "", fmt.Errorf("unsupported question type: %s", q.Type)
+	}
+
+	if q.Feedback.General != "" {
+		b.WriteString("####" + giftEscape(q.Feedback.General))
+	}
+	b.WriteString("}")
+
+	return b.String(), nil
+}
+
+// giftFeedback returns the feedback of a choice, falling back to the correct or incorrect feedback of the question.
+func giftFeedback(feedback string, q commoncartridge.Question, correct bool) string {
+	if feedback == "" && correct {
+		feedback = q.Feedback.Correct
+	} else if feedback == "" {
+		feedback = q.Feedback.Incorrect
+	}
+
+	if feedback == "" {
+		return ""
+	}
+
+	return "#" + giftEscape(feedback)
+}
+
+// giftEscape escapes the GIFT special characters and puts the text on a single line.
+func giftEscape(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return giftEscaper.Replace(s)
+}
+
+var (
+	// giftProtector replaces the GIFT escape sequences with private use characters while parsing, so that the characters they stand for are not mistaken for the GIFT syntax.
+	giftProtector = strings.NewReplacer(`\\`, "\ue000", `\~`, "\ue001", `\=`, "\ue002", `\#`, "\ue003", `\{`, "\ue004", `\}`, "\ue005", `\:`, "\ue006", `\n`, "\ue007")
+	// giftUnescaper turns the private use characters back into the characters they stand for.
+	giftUnescaper = strings.NewReplacer("\ue000", `\`, "\ue001", `~`, "\ue002", `=`, "\ue003", `#`, "\ue004", `{`, "\ue005", `}`, "\ue006", `:`, "\ue007", "\n")
+)
+
+// ParseGIFT builds a CC-profile QTI assessment from a GIFT text, such as the ones exported by Moodle or by the GIFT function of this package. The title of the assessment is the last part of the `$CATEGORY`, if any, or the given title. Multiple choice, multiple response (choices with a percentage), true/false, short answer, pattern match (short answers wrapped in `*`) and essay questions are imported, while numerical and matching questions are reported as skipped.
+func ParseGIFT(text string, title string) (types.Questestinterop, Report) {
+	report := Report{Format: "gift", Skipped: make([]Skipped, 0)}
+	quiz := commoncartridge.Quiz{Title: title, Questions: make([]commoncartridge.Question, 0)}
+
+	for i, block := range giftBlocks(giftProtector.Replace(text)) {
+		if strings.HasPrefix(block, "$CATEGORY:") {
+			category, rest, _ := strings.Cut(block, "\n")
+			path := strings.Split(strings.TrimSpace(strings.TrimPrefix(category, "$CATEGORY:")), "/")
+			quiz.Title = giftUnescaper.Replace(path[len(path)-1])
+
+			if block = strings.TrimSpace(rest); block == "" {
+				continue
+			}
+		}
+
+		q, err := parseGIFTQuestion(block, ident(block, strconv.Itoa(i)))
+		if err != nil {
+			report.skip(q, err.Error())
+			continue
+		}
+
+		quiz.Questions = append(quiz.Questions, q)
+		report.Exported++
+	}
+
+	return QTI(quiz), report
+}
+
+// giftBlocks splits a GIFT text into questions, which are separated by blank lines outside of the answer blocks, leaving out the comments.
+func giftBlocks(text string) []string {
+	blocks := make([]string, 0)
+
+	var current []string
+	depth := 0
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+
+		if trimmed == "" && depth <= 0 {
+			if len(current) > 0 {
+				blocks = append(blocks, strings.Join(current, "\n"))
+			}
+			current, depth = nil, 0
+			continue
+		}
+
+		current = append(current, trimmed)
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+	}
+
+	if len(current) > 0 {
+		blocks = append(blocks, strings.Join(current, "\n"))
+	}
+
+	return blocks
+}
+
+// parseGIFTQuestion parses a single question whose escape sequences have been protected, i.e. an optional `::title::`, an optional `[format]`, the question text and its answer block.
+func parseGIFTQuestion(block string, id string) (commoncartridge.Question, error) {
+	q := commoncartridge.Question{
+		Identifier: id,
+		Choices:    make([]commoncartridge.Choice, 0),
+		Answers:    make([]string, 0),
+		Points:     1,
+	}
+
+	s := strings.TrimSpace(block)
+	if strings.HasPrefix(s, "::") {
+		title, rest, found := strings.Cut(s[2:], "::")
+		if !found {
+			return q, fmt.Errorf("unterminated title")
+		}
+		q.Title = giftUnescaper.Replace(strings.TrimSpace(title))
+		s = strings.TrimSpace(rest)
+	}
+
+	for _, format := range []string{"[html]", "[plain]", "[markdown]", "[moodle]"} {
+		s = strings.TrimSpace(strings.TrimPrefix(s, format))
+	}
+
+	open, close := strings.Index(s, "{"), strings.LastIndex(s, "}")
+	if open < 0 || close < open {
+		return q, fmt.Errorf("missing answer block")
+	}
+
+	q.Text = strings.TrimSpace(s[:open])
+	if after := strings.TrimSpace(s[close+1:]); after != "" {
+		q.Text += " _____ " + after
+	}
+	q.Text = giftUnescaper.Replace(q.Text)
+	if q.Text == "" {
+		return q, fmt.Errorf("empty question text")
+	}
+
+	answers, general, _ := strings.Cut(s[open+1:close], "####")
+	q.Feedback.General = giftUnescaper.Replace(strings.TrimSpace(general))
+	answers = strings.TrimSpace(answers)
+
+	head, feedback, _ := strings.Cut(answers, "#")
+	switch strings.ToUpper(strings.TrimSpace(head)) {
+	case "":
+		if answers == "" {
+			q.Type = commoncartridge.Essay
+			return q, nil
+		}
+		return q, fmt.Errorf("numerical questions are not supported")
+	case "T", "TRUE", "F", "FALSE":
+		q.Type = commoncartridge.TrueFalse
+		truth := strings.HasPrefix(strings.ToUpper(strings.TrimSpace(head)), "T")
+		q.Choices = append(q.Choices,
+			commoncartridge.Choice{Identifier: "1", Text: "True", Correct: truth},
+			commoncartridge.Choice{Identifier: "2", Text: "False", Correct: !truth},
+		)
+
+		incorrect, correct, _ := strings.Cut(feedback, "#")
+		q.Feedback.Incorrect = giftUnescaper.Replace(strings.TrimSpace(incorrect))
+		q.Feedback.Correct = giftUnescaper.Replace(strings.TrimSpace(correct))
+		return q, nil
+	}
+
+	if strings.Contains(answers, "->") {
+		return q, fmt.Errorf("matching questions are not supported")
+	}
+
+	return q, parseGIFTAnswers(&q, answers)
+}
+
+// giftAnswer is one of the `=` or `~` answers of an answer block.
+type giftAnswer struct {
+	text     string
+	feedback string
+	correct  bool
+	weighted bool
+}
+
+// parseGIFTAnswers sets the type, choices and accepted answers of a question from its `=` and `~` answers. Answers which are all marked with `=` are the accepted texts of a short answer question, and the question is a multiple response one when its choices are weighted or when more than one is correct.
+func parseGIFTAnswers(q *commoncartridge.Question, block string) error {
+	answers := make([]giftAnswer, 0)
+	choices := false
+
+	start := strings.IndexAny(block, "=~")
+	if start != 0 {
+		return fmt.Errorf("unexpected answer block: %s", giftUnescaper.Replace(block))
+	}
+
+	for start >= 0 {
+		end := strings.IndexAny(block[start+1:], "=~")
+		if end >= 0 {
+			end += start + 1
+		} else {
+			end = len(block)
+		}
+
+		marker, content := block[start], strings.TrimSpace(block[start+1:end])
+		a := giftAnswer{correct: marker == '='}
+		choices = choices || marker == '~'
+
+		if strings.HasPrefix(content, "%") {
+			weight, rest, found := strings.Cut(content[1:], "%")
+			w, err := strconv.ParseFloat(weight, 64)
+			if !found || err != nil {
+				return fmt.Errorf("invalid answer weight: %s", giftUnescaper.Replace(content))
+			}
+			a.correct, a.weighted, content = w > 0, true, rest
+		}
+
+		text, feedback, _ := strings.Cut(content, "#")
+		a.text = giftUnescaper.Replace(strings.TrimSpace(text))
+		a.feedback = giftUnescaper.Replace(strings.TrimSpace(feedback))
+		answers = append(answers, a)
+
+		if end == len(block) {
+			break
+		}
+		start = end
+	}
+
+	if !choices {
+		q.Type = commoncartridge.PatternMatch
+		for _, a := range answers {
+			if len(a.text) < 3 || !strings.HasPrefix(a.text, "*") || !strings.HasSuffix(a.text, "*") {
+				q.Type = commoncartridge.FillInTheBlank
+			}
+		}
+
+		for _, a := range answers {
+			if q.Type == commoncartridge.PatternMatch {
+				a.text = a.text[1 : len(a.text)-1]
+			}
+			q.Answers = append(q.Answers, a.text)
+			if q.Feedback.Correct == "" {
+				q.Feedback.Correct = a.feedback
+			}
+		}
+
+		return nil
+	}
+
+	correct, weighted := 0, false
+	for i, a := range answers {
+		id := strconv.Itoa(i + 1)
+		q.Choices = append(q.Choices, commoncartridge.Choice{Identifier: id, Text: a.text, Correct: a.correct, Feedback: a.feedback})
+		if a.correct {
+			q.Answers = append(q.Answers, id)
+			correct++
+		}
+		weighted = weighted || a.weighted
+	}
+
+	if correct == 0 {
+		return fmt.Errorf("no correct choice")
+	}
+
+	q.Type = commoncartridge.MultipleChoice
+	if weighted || correct > 1 {
+		q.Type = commoncartridge.MultipleResponse
+	}
+
+	return nil
+}
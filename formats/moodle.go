@@ -0,0 +1,191 @@
+package formats
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// MoodleQuiz is the root element of a Moodle XML question file.
+type MoodleQuiz struct {
+	XMLName  xml.Name         `xml:"quiz"`
+	Question []MoodleQuestion `xml:"question"`
+}
+
+// MoodleQuestion is a question of a Moodle XML file. The category of the questions is also declared as a question, of type `category`.
+type MoodleQuestion struct {
+	Type              string         `xml:"type,attr"`
+	Category          *MoodleText    `xml:"category,omitempty"`
+	Name              *MoodleText    `xml:"name,omitempty"`
+	QuestionText      *MoodleText    `xml:"questiontext,omitempty"`
+	GeneralFeedback   *MoodleText    `xml:"generalfeedback,omitempty"`
+	DefaultGrade      string         `xml:"defaultgrade,omitempty"`
+	Single            string         `xml:"single,omitempty"`
+	ShuffleAnswers    string         `xml:"shuffleanswers,omitempty"`
+	AnswerNumbering   string         `xml:"answernumbering,omitempty"`
+	UseCase           string         `xml:"usecase,omitempty"`
+	ResponseFormat    string         `xml:"responseformat,omitempty"`
+	CorrectFeedback   *MoodleText    `xml:"correctfeedback,omitempty"`
+	IncorrectFeedback *MoodleText    `xml:"incorrectfeedback,omitempty"`
+	Answer            []MoodleAnswer `xml:"answer"`
+}
+
+// MoodleText is a text node of a Moodle XML file, with its format (e.g. `html`).
+type MoodleText struct {
+	Format string `xml:"format,attr,omitempty"`
+	Text   string `xml:"text"`
+}
+
+// MoodleAnswer is an answer of a Moodle XML question, with the fraction of the grade it gives.
+type MoodleAnswer struct {
+	Fraction string      `xml:"fraction,attr"`
+	Format   string      `xml:"format,attr,omitempty"`
+	Text     string      `xml:"text"`
+	Feedback *MoodleText `xml:"feedback,omitempty"`
+}
+
+// MoodleXML converts a QTI assessment to a Moodle XML question file, in which all questions are placed in a category named after the assessment. The correct choices of a multiple response question share the grade, rounded to the nearest percentage that Moodle offers, and each incorrect one takes the whole grade away, as the response must be exactly the correct choices in the CC profile. The choices and answers which give part of the score keep their share of the grade, rounded in the same way. The questions whose grade was rounded are reported as warnings, and so are the true/false questions whose correct choice has a text that is not recognized as true or false, whose answer is read from its position.
+func MoodleXML(qti types.Questestinterop) ([]byte, Report, error) {
+	quiz := commoncartridge.NewQuiz(qti)
+	report := Report{Format: "moodle", Skipped: make([]Skipped, 0)}
+
+	mq := MoodleQuiz{
+		Question: []MoodleQuestion{{
+			Type:     "category",
+			Category: &MoodleText{Text: "$course$/" + commoncartridge.SingleLine(quiz.Title)},
+		}},
+	}
+
+	for _, q := range quiz.Questions {
+		question, err := moodleQuestion(q)
+		if err != nil {
+			report.skip(q, err.Error())
+			continue
+		}
+
+		mq.Question = append(mq.Question, question)
+		report.Exported++
+		warnRoundedCredit(q, &report)
+
+		switch q.Type {
+		case commoncartridge.MultipleResponse:
+			correct := len(correctChoices(q))
+			if grade, exact := moodleFraction(correct); !exact {
+				report.warn(q, roundedWarning(correct, grade))
+			}
+		case commoncartridge.TrueFalse:
+			if truth, recognized := isTrue(q); !recognized {
+				report.warn(q, trueFalseWarning(truth))
+			}
+		}
+	}
+
+	data, err := xml.MarshalIndent(mq, "", "  ")
+	if err != nil {
+		return data, report, err
+	}
+
+	return append([]byte(xml.Header), data...), report, nil
+}
+
+// moodleQuestion converts a question to the Moodle question type closest to it.
+func moodleQuestion(q commoncartridge.Question) (MoodleQuestion, error) {
+	mq := MoodleQuestion{
+		Name:         &MoodleText{Text: questionName(q)},
+		QuestionText: &MoodleText{Format: "html", Text: q.Text},
+		DefaultGrade: strconv.FormatFloat(q.Points, 'f', -1, 64),
+		Answer:       make([]MoodleAnswer, 0),
+	}
+
+	if q.Feedback.General != "" {
+		mq.GeneralFeedback = &MoodleText{Format: "html", Text: q.Feedback.General}
+	}
+
+	switch q.Type {
+	case commoncartridge.MultipleChoice, commoncartridge.MultipleResponse:
+		correct := len(correctChoices(q))
+		if correct == 0 {
+			return mq, fmt.Errorf("no correct choice")
+		}
+		if q.Type == commoncartridge.MultipleChoice && correct != 1 {
+			return mq, fmt.Errorf("expected a single correct choice, found %d", correct)
+		}
+
+		mq.Type = "multichoice"
+		mq.Single = strconv.FormatBool(q.Type == commoncartridge.MultipleChoice)
+		mq.ShuffleAnswers = "true"
+		mq.AnswerNumbering = "abc"
+		mq.CorrectFeedback = &MoodleText{Format: "html", Text: q.Feedback.Correct}
+		mq.IncorrectFeedback = &MoodleText{Format: "html", Text: q.Feedback.Incorrect}
+
+		grade, _ := moodleFraction(correct)
+		for _, c := range q.Choices {
+			answer := MoodleAnswer{Fraction: "0", Format: "html", Text: c.Text}
+			if c.Correct {
+				answer.Fraction = grade
+			} else if c.Credit > 0 {
+				answer.Fraction = moodleGrade(c.Credit)
+			} else if q.Type == commoncartridge.MultipleResponse {
+				answer.Fraction = "-100"
+			}
+			if c.Feedback != "" {
+				answer.Feedback = &MoodleText{Format: "html", Text: c.Feedback}
+			}
+
+			mq.Answer = append(mq.Answer, answer)
+		}
+	case commoncartridge.TrueFalse:
+		correct := correctChoices(q)
+		if len(correct) != 1 {
+			return mq, fmt.Errorf("expected a single correct choice, found %d", len(correct))
+		}
+
+		mq.Type = "truefalse"
+		truth, falsehood := MoodleAnswer{Fraction: "0", Text: "true"}, MoodleAnswer{Fraction: "0", Text: "false"}
+		if t, _ := isTrue(q); t {
+			truth.Fraction = "100"
+		} else {
+			falsehood.Fraction = "100"
+		}
+		mq.Answer = append(mq.Answer, truth, falsehood)
+	case commoncartridge.FillInTheBlank, commoncartridge.PatternMatch:
+		if len(q.Answers) == 0 {
+			return mq, fmt.Errorf("no accepted answer")
+		}
+
+		mq.Type = "shortanswer"
+		mq.UseCase = "0"
+		if q.CaseSensitive {
+			mq.UseCase = "1"
+		}
+
+		for _, a := range q.Answers {
+			if q.Type == commoncartridge.PatternMatch {
+				a = "*" + a + "*"
+			}
+
+			answer := MoodleAnswer{Fraction: "100", Text: a}
+			if q.Feedback.Correct != "" {
+				answer.Feedback = &MoodleText{Format: "html", Text: q.Feedback.Correct}
+			}
+			mq.Answer = append(mq.Answer, answer)
+		}
+		for _, a := range partialAnswers(q) {
+			grade := moodleGrade(q.PartialCredit[a])
+			if q.Type == commoncartridge.PatternMatch {
+				a = "*" + a + "*"
+			}
+			mq.Answer = append(mq.Answer, MoodleAnswer{Fraction: grade, Text: a})
+		}
+	case commoncartridge.Essay:
+		mq.Type = "essay"
+		mq.ResponseFormat = "editor"
+	default:
+		return mq, fmt.Errorf("unsupported question type: %s", q.Type)
+	}
+
+	return mq, nil
+}
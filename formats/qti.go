@@ -0,0 +1,244 @@
+package formats
+
+import (
+	"crypto/md5"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// QTI builds a CC-profile QTI 1.2 assessment from a quiz, which is the reverse of commoncartridge.NewQuiz. All the questions are placed in a single root section, and the response processing gives a score of 100 to correct responses, and their share of it to the partially correct ones. Marshaling the result with types.MarshalDocument produces a valid `questestinterop` document.
+func QTI(quiz commoncartridge.Quiz) types.Questestinterop {
+	var qti types.Questestinterop
+	qti.Xmlns = types.QTINamespace
+	qti.Xsi = types.XSINamespace
+	qti.SchemaLocation = types.QTISchemaLocation
+
+	qti.Assessment.Ident = quiz.Identifier
+	if qti.Assessment.Ident == "" {
+		qti.Assessment.Ident = ident(quiz.Title)
+	}
+	qti.Assessment.Title = quiz.Title
+
+	attempts := "unlimited"
+	if quiz.Settings.MaxAttempts > 0 {
+		attempts = strconv.Itoa(quiz.Settings.MaxAttempts)
+	}
+
+	meta := &qti.Assessment.Qtimetadata
+	addField(meta, "cc_profile", defaultString(quiz.Settings.Profile, "cc.exam.v0p1"))
+	addField(meta, "qmd_assessmenttype", defaultString(quiz.Settings.AssessmentType, "Examination"))
+	addField(meta, "qmd_scoretype", defaultString(quiz.Settings.ScoreType, "Percentage"))
+	addField(meta, "cc_maxattempts", attempts)
+	if quiz.Settings.TimeLimit > 0 {
+		addField(meta, "qmd_timelimit", strconv.Itoa(quiz.Settings.TimeLimit))
+	}
+
+	section := types.Section{Ident: "root_section"}
+	for _, q := range quiz.Questions {
+		section.Item = append(section.Item, qtiItem(q))
+	}
+	qti.Assessment.Section = []types.Section{section}
+
+	return qti
+}
+
+// qtiItem builds the item of a question, with the presentation and response processing required by its CC profile.
+func qtiItem(q commoncartridge.Question) types.QTIItem {
+	var item types.QTIItem
+	item.Ident = q.Identifier
+	item.Title = q.Title
+
+	addField(&item.Itemmetadata.Qtimetadata, "cc_profile", "cc."+string(q.Type)+".v0p1")
+	if q.Points > 0 && q.Points != 1 {
+		addField(&item.Itemmetadata.Qtimetadata, "cc_weighting", strconv.FormatFloat(q.Points, 'f', -1, 64))
+	}
+
+	item.Presentation.Material = material(q.Text)
+	switch q.Type {
+	case commoncartridge.MultipleChoice, commoncartridge.MultipleResponse, commoncartridge.TrueFalse:
+		item.Presentation.ResponseLid.Ident = "response1"
+		item.Presentation.ResponseLid.Rcardinality = "Single"
+		if q.Type == commoncartridge.MultipleResponse {
+			item.Presentation.ResponseLid.Rcardinality = "Multiple"
+		}
+
+		for _, c := range q.Choices {
+			item.Presentation.ResponseLid.RenderChoice.ResponseLabel = append(item.Presentation.ResponseLid.RenderChoice.ResponseLabel, types.ResponseLabel{
+				Ident:    c.Identifier,
+				Material: material(c.Text),
+			})
+		}
+	default:
+		item.Presentation.ResponseStr.Ident = "response1"
+		item.Presentation.ResponseStr.Rcardinality = "Single"
+		item.Presentation.ResponseStr.RenderFib.ResponseLabel.Ident = "answer1"
+		item.Presentation.ResponseStr.RenderFib.ResponseLabel.Rshuffle = "No"
+	}
+
+	maxScore := "100"
+	if q.MaxScore > 0 {
+		maxScore = strconv.FormatFloat(q.MaxScore, 'f', -1, 64)
+	}
+
+	decvar := &item.Resprocessing.Outcomes.Decvar
+	decvar.Maxvalue, decvar.Minvalue, decvar.Varname, decvar.Vartype = maxScore, "0", "SCORE", "Decimal"
+
+	if q.Feedback.General != "" {
+		item.Resprocessing.Respcondition = append(item.Resprocessing.Respcondition, feedbackCondition(types.Conditionvar{Other: &struct{}{}}, "general_fb"))
+		item.Itemfeedback = append(item.Itemfeedback, itemfeedback("general_fb", q.Feedback.General))
+	}
+
+	for _, c := range q.Choices {
+		if c.Feedback == "" {
+			continue
+		}
+
+		id := c.Identifier + "_fb"
+		cond := types.Conditionvar{Varequal: []types.Vartest{{Respident: "response1", Text: c.Identifier}}}
+		item.Resprocessing.Respcondition = append(item.Resprocessing.Respcondition, feedbackCondition(cond, id))
+		item.Itemfeedback = append(item.Itemfeedback, itemfeedback(id, c.Feedback))
+	}
+
+	if q.Type == commoncartridge.Essay {
+		return item
+	}
+
+	scoring := types.Respcondition{Continue: "No", Conditionvar: scoringCondition(q)}
+	scoring.Setvar.Action, scoring.Setvar.Varname, scoring.Setvar.Text = "Set", "SCORE", maxScore
+	if q.Feedback.Correct != "" {
+		scoring.Displayfeedback.Feedbacktype, scoring.Displayfeedback.Linkrefid = "Response", "correct_fb"
+		item.Itemfeedback = append(item.Itemfeedback, itemfeedback("correct_fb", q.Feedback.Correct))
+	}
+	item.Resprocessing.Respcondition = append(item.Resprocessing.Respcondition, scoring)
+	item.Resprocessing.Respcondition = append(item.Resprocessing.Respcondition, partialConditions(q, maxScore)...)
+
+	if q.Feedback.Incorrect != "" {
+		incorrect := feedbackCondition(types.Conditionvar{Other: &struct{}{}}, "general_incorrect_fb")
+		incorrect.Continue = "No"
+		item.Resprocessing.Respcondition = append(item.Resprocessing.Respcondition, incorrect)
+		item.Itemfeedback = append(item.Itemfeedback, itemfeedback("general_incorrect_fb", q.Feedback.Incorrect))
+	}
+
+	return item
+}
+
+// scoringCondition returns the condition met by a correct response: one of the correct choices or accepted texts, or, for multiple response questions, all of the correct choices and none of the others.
+func scoringCondition(q commoncartridge.Question) types.Conditionvar {
+	var cond types.Conditionvar
+
+	caseSensitive := "No"
+	if q.CaseSensitive {
+		caseSensitive = "Yes"
+	}
+
+	switch q.Type {
+	case commoncartridge.MultipleResponse:
+		for _, c := range q.Choices {
+			test := types.Vartest{Respident: "response1", Text: c.Identifier}
+			if c.Correct {
+				cond.And.Varequal = append(cond.And.Varequal, test)
+			} else {
+				cond.And.Not = append(cond.And.Not, types.Not{Varequal: test})
+			}
+		}
+	case commoncartridge.MultipleChoice, commoncartridge.TrueFalse:
+		for _, c := range correctChoices(q) {
+			cond.Varequal = append(cond.Varequal, types.Vartest{Respident: "response1", Text: c.Identifier})
+		}
+	case commoncartridge.PatternMatch:
+		for _, a := range q.Answers {
+			cond.Varsubstring = append(cond.Varsubstring, types.Vartest{Respident: "response1", Case: caseSensitive, Text: a})
+		}
+	default:
+		for _, a := range q.Answers {
+			cond.Varequal = append(cond.Varequal, types.Vartest{Respident: "response1", Case: caseSensitive, Text: a})
+		}
+	}
+
+	return cond
+}
+
+// partialConditions returns the conditions met by the choices or accepted texts which give part of the score, each setting it to its share of the maximum score. The choices of multiple response questions are scored as a whole, and cannot give part of the score.
+func partialConditions(q commoncartridge.Question, maxScore string) []types.Respcondition {
+	conditions := make([]types.Respcondition, 0)
+	if q.Type == commoncartridge.MultipleResponse {
+		return conditions
+	}
+
+	max, _ := strconv.ParseFloat(maxScore, 64)
+	partial := func(test types.Vartest, credit float64) types.Respcondition {
+		rc := types.Respcondition{Continue: "No"}
+		if q.Type == commoncartridge.PatternMatch {
+			rc.Conditionvar.Varsubstring = []types.Vartest{test}
+		} else {
+			rc.Conditionvar.Varequal = []types.Vartest{test}
+		}
+		rc.Setvar.Action, rc.Setvar.Varname, rc.Setvar.Text = "Set", "SCORE", strconv.FormatFloat(credit*max/100, 'f', -1, 64)
+
+		return rc
+	}
+
+	caseSensitive := "No"
+	if q.CaseSensitive {
+		caseSensitive = "Yes"
+	}
+
+	for _, c := range q.Choices {
+		if c.Credit > 0 {
+			conditions = append(conditions, partial(types.Vartest{Respident: "response1", Text: c.Identifier}, c.Credit))
+		}
+	}
+	for _, a := range partialAnswers(q) {
+		conditions = append(conditions, partial(types.Vartest{Respident: "response1", Case: caseSensitive, Text: a}, q.PartialCredit[a]))
+	}
+
+	return conditions
+}
+
+// feedbackCondition returns a condition which displays the given feedback and lets the processing continue.
+func feedbackCondition(cond types.Conditionvar, feedback string) types.Respcondition {
+	rc := types.Respcondition{Continue: "Yes", Conditionvar: cond}
+	rc.Displayfeedback.Feedbacktype, rc.Displayfeedback.Linkrefid = "Response", feedback
+
+	return rc
+}
+
+func itemfeedback(id string, text string) types.Itemfeedback {
+	fb := types.Itemfeedback{Ident: id}
+	fb.FlowMat.Material = material(text)
+
+	return fb
+}
+
+// material returns the material holding the given text, as HTML if it contains any tag.
+func material(text string) types.Material {
+	var m types.Material
+	m.Mattext.Text = text
+	m.Mattext.Texttype = "text/plain"
+	if commoncartridge.IsHTML(text) {
+		m.Mattext.Texttype = "text/html"
+	}
+
+	return m
+}
+
+func addField(meta *types.Qtimetadata, label string, entry string) {
+	meta.Qtimetadatafield = append(meta.Qtimetadatafield, types.Qtimetadatafield{Fieldlabel: label, Fieldentry: entry})
+}
+
+func defaultString(s string, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+
+	return s
+}
+
+// ident returns an identifier derived from the given parts, so that importing the same text twice gives the same identifiers. Like the ones of Canvas, it is a hexadecimal hash prefixed with `i`, which makes it a valid XML ID.
+func ident(parts ...string) string {
+	return fmt.Sprintf("i%x", md5.Sum([]byte(strings.Join(parts, "\x00"))))
+}
@@ -0,0 +1,590 @@
+package formats
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+const (
+	qti21Namespace      = "http://www.imsglobal.org/xsd/imsqti_v2p1"
+	qti21SchemaLocation = "http://www.imsglobal.org/xsd/imsqti_v2p1 http://www.imsglobal.org/xsd/qti/qtiv2p1/imsqti_v2p1p1.xsd"
+	cpNamespace         = "http://www.imsglobal.org/xsd/imscp_v1p1"
+	cpSchemaLocation    = "http://www.imsglobal.org/xsd/imscp_v1p1 http://www.imsglobal.org/xsd/qti/qtiv2p1/qtiv2p1_imscpv1p2_v1p0.xsd"
+
+	// MatchCorrect is the standard response processing template giving a score of 1 when the response is the correct one.
+	MatchCorrect = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct"
+	// MapResponse is the standard response processing template giving the score mapped to the response.
+	MapResponse = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/map_response"
+)
+
+// identifierPattern matches the characters which are not allowed in a QTI 2.1 identifier.
+var identifierPattern = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// QTI21Package is a QTI 2.1 content package, made of an assessmentTest and the assessmentItems it refers to.
+type QTI21Package struct {
+	Test  AssessmentTest
+	Items []AssessmentItem
+}
+
+// AssessmentItem is a QTI 2.1 question. Its Href is the path of the item in the package.
+type AssessmentItem struct {
+	XMLName             xml.Name             `xml:"assessmentItem"`
+	Xmlns               string               `xml:"xmlns,attr"`
+	Xsi                 string               `xml:"xmlns:xsi,attr"`
+	SchemaLocation      string               `xml:"xsi:schemaLocation,attr"`
+	Identifier          string               `xml:"identifier,attr"`
+	Title               string               `xml:"title,attr"`
+	Adaptive            bool                 `xml:"adaptive,attr"`
+	TimeDependent       bool                 `xml:"timeDependent,attr"`
+	ResponseDeclaration ResponseDeclaration  `xml:"responseDeclaration"`
+	OutcomeDeclaration  []OutcomeDeclaration `xml:"outcomeDeclaration"`
+	ItemBody            ItemBody             `xml:"itemBody"`
+	ResponseProcessing  *ResponseProcessing  `xml:"responseProcessing,omitempty"`
+	Href                string               `xml:"-"`
+	Weight              float64              `xml:"-"`
+}
+
+// ResponseDeclaration declares the response of an item, along with its correct value and the score mapped to each accepted value.
+type ResponseDeclaration struct {
+	Identifier      string   `xml:"identifier,attr"`
+	Cardinality     string   `xml:"cardinality,attr"`
+	BaseType        string   `xml:"baseType,attr"`
+	CorrectResponse *Values  `xml:"correctResponse,omitempty"`
+	Mapping         *Mapping `xml:"mapping,omitempty"`
+}
+
+// OutcomeDeclaration declares an outcome variable, such as the SCORE of an item or of a test.
+type OutcomeDeclaration struct {
+	Identifier   string  `xml:"identifier,attr"`
+	Cardinality  string  `xml:"cardinality,attr"`
+	BaseType     string  `xml:"baseType,attr"`
+	DefaultValue *Values `xml:"defaultValue,omitempty"`
+}
+
+// Values is a list of `<value>`, e.g. the correct response of an item.
+type Values struct {
+	Value []string `xml:"value"`
+}
+
+// Mapping maps the accepted values of a response to a score.
+type Mapping struct {
+	DefaultValue string     `xml:"defaultValue,attr"`
+	MapEntry     []MapEntry `xml:"mapEntry"`
+}
+
+// MapEntry is a single value of a Mapping.
+type MapEntry struct {
+	MapKey        string `xml:"mapKey,attr"`
+	MappedValue   string `xml:"mappedValue,attr"`
+	CaseSensitive bool   `xml:"caseSensitive,attr"`
+}
+
+// ItemBody holds the XHTML content of the question, followed by the interaction through which the learner responds.
+type ItemBody struct {
+	Content                 string                   `xml:",innerxml"`
+	ChoiceInteraction       *ChoiceInteraction       `xml:"choiceInteraction,omitempty"`
+	TextEntryInteraction    *TextEntryInteraction    `xml:"p>textEntryInteraction,omitempty"`
+	ExtendedTextInteraction *ExtendedTextInteraction `xml:"extendedTextInteraction,omitempty"`
+}
+
+// ChoiceInteraction presents choices, of which up to MaxChoices can be selected, 0 meaning any number.
+type ChoiceInteraction struct {
+	ResponseIdentifier string         `xml:"responseIdentifier,attr"`
+	Shuffle            bool           `xml:"shuffle,attr"`
+	MaxChoices         int            `xml:"maxChoices,attr"`
+	SimpleChoice       []SimpleChoice `xml:"simpleChoice"`
+}
+
+// SimpleChoice is one of the choices of a ChoiceInteraction.
+type SimpleChoice struct {
+	Identifier string `xml:"identifier,attr"`
+	Content    string `xml:",innerxml"`
+}
+
+// TextEntryInteraction is a single line of text typed in by the learner.
+type TextEntryInteraction struct {
+	ResponseIdentifier string `xml:"responseIdentifier,attr"`
+}
+
+// ExtendedTextInteraction is a free text typed in by the learner, e.g. an essay.
+type ExtendedTextInteraction struct {
+	ResponseIdentifier string `xml:"responseIdentifier,attr"`
+}
+
+// ResponseProcessing refers to one of the standard response processing templates, e.g. MatchCorrect.
+type ResponseProcessing struct {
+	Template string `xml:"template,attr"`
+}
+
+// AssessmentTest is a QTI 2.1 test, whose single test part holds the sections of the assessment.
+type AssessmentTest struct {
+	XMLName            xml.Name             `xml:"assessmentTest"`
+	Xmlns              string               `xml:"xmlns,attr"`
+	Xsi                string               `xml:"xmlns:xsi,attr"`
+	SchemaLocation     string               `xml:"xsi:schemaLocation,attr"`
+	Identifier         string               `xml:"identifier,attr"`
+	Title              string               `xml:"title,attr"`
+	OutcomeDeclaration []OutcomeDeclaration `xml:"outcomeDeclaration"`
+	TimeLimits         *TimeLimits          `xml:"timeLimits,omitempty"`
+	TestPart           TestPart             `xml:"testPart"`
+	OutcomeProcessing  OutcomeProcessing    `xml:"outcomeProcessing"`
+	Href               string               `xml:"-"`
+}
+
+// TimeLimits is the time allowed for a test, in seconds.
+type TimeLimits struct {
+	MaxTime int `xml:"maxTime,attr"`
+}
+
+// TestPart groups the sections of a test, along with the way the learner navigates through them.
+type TestPart struct {
+	Identifier        string              `xml:"identifier,attr"`
+	NavigationMode    string              `xml:"navigationMode,attr"`
+	SubmissionMode    string              `xml:"submissionMode,attr"`
+	AssessmentSection []AssessmentSection `xml:"assessmentSection"`
+}
+
+// AssessmentSection refers to the items of a section and to its subsections, along with the rules to select and order them.
+type AssessmentSection struct {
+	Identifier        string              `xml:"identifier,attr"`
+	Title             string              `xml:"title,attr"`
+	Visible           bool                `xml:"visible,attr"`
+	Selection         *Selection          `xml:"selection,omitempty"`
+	Ordering          *Ordering           `xml:"ordering,omitempty"`
+	AssessmentItemRef []AssessmentItemRef `xml:"assessmentItemRef"`
+	AssessmentSection []AssessmentSection `xml:"assessmentSection"`
+}
+
+// Selection is the number of items drawn from a section.
+type Selection struct {
+	Select int `xml:"select,attr"`
+}
+
+// Ordering tells whether the items of a section are shuffled.
+type Ordering struct {
+	Shuffle bool `xml:"shuffle,attr"`
+}
+
+// AssessmentItemRef refers to an item of the package, with an optional weight applied to its score.
+type AssessmentItemRef struct {
+	Identifier string   `xml:"identifier,attr"`
+	Href       string   `xml:"href,attr"`
+	Weight     []Weight `xml:"weight"`
+}
+
+// Weight is a factor applied to the score of an item.
+type Weight struct {
+	Identifier string `xml:"identifier,attr"`
+	Value      string `xml:"value,attr"`
+}
+
+// OutcomeProcessing computes the SCORE of the test as the weighted sum of the scores of its items.
+type OutcomeProcessing struct {
+	SetOutcomeValue struct {
+		Identifier string `xml:"identifier,attr"`
+		Sum        struct {
+			TestVariables struct {
+				VariableIdentifier string `xml:"variableIdentifier,attr"`
+				WeightIdentifier   string `xml:"weightIdentifier,attr"`
+			} `xml:"testVariables"`
+		} `xml:"sum"`
+	} `xml:"setOutcomeValue"`
+}
+
+// QTI21 converts a QTI 1.2 assessment to a QTI 2.1 package, with one assessmentItem per question and an assessmentTest keeping the sections, the selection and ordering rules and the weights of the questions. Items are scored with the standard response processing templates: choice-based questions match the correct response, while fill-in-the-blank questions map their accepted answers to a score of 1, or to their share of it for the ones which give part of the score. The report lists the questions which could not be converted, and the ones which need manual attention, such as essays, pattern match questions, feedback and the choices giving part of the score, which the standard templates do not support, as well as the number of attempts of the quiz, which is left out.
+func QTI21(qti types.Questestinterop) (QTI21Package, Report) {
+	quiz := commoncartridge.NewQuiz(qti)
+	report := Report{Format: "qti21", Skipped: make([]Skipped, 0), Warnings: make([]Skipped, 0)}
+	pkg := QTI21Package{Items: make([]AssessmentItem, 0)}
+
+	used := make(identifiers)
+	testID := used.unique("TEST_", quiz.Identifier)
+
+	refs := make(map[string]AssessmentItemRef)
+	for _, q := range quiz.Questions {
+		item, err := assessmentItem(q, used, &report)
+		if err != nil {
+			report.skip(q, err.Error())
+			continue
+		}
+
+		ref := AssessmentItemRef{Identifier: item.Identifier, Href: item.Href, Weight: make([]Weight, 0)}
+		if item.Weight != 1 {
+			ref.Weight = append(ref.Weight, Weight{Identifier: "WEIGHT", Value: strconv.FormatFloat(item.Weight, 'f', -1, 64)})
+		}
+
+		refs[q.Identifier] = ref
+		pkg.Items = append(pkg.Items, item)
+		report.Exported++
+	}
+
+	test := AssessmentTest{
+		Xmlns:              qti21Namespace,
+		Xsi:                types.XSINamespace,
+		SchemaLocation:     qti21SchemaLocation,
+		Identifier:         testID,
+		Title:              quiz.Title,
+		OutcomeDeclaration: []OutcomeDeclaration{scoreDeclaration()},
+		TestPart: TestPart{
+			Identifier:        "part1",
+			NavigationMode:    "nonlinear",
+			SubmissionMode:    "simultaneous",
+			AssessmentSection: make([]AssessmentSection, 0),
+		},
+		Href: "assessment.xml",
+	}
+
+	if quiz.Settings.TimeLimit > 0 {
+		test.TimeLimits = &TimeLimits{MaxTime: quiz.Settings.TimeLimit * 60}
+	}
+	if quiz.Settings.MaxAttempts > 0 {
+		report.Warnings = append(report.Warnings, Skipped{
+			Identifier: quiz.Identifier,
+			Title:      quiz.Title,
+			Reason:     fmt.Sprintf("the quiz allows %d attempt(s), which QTI 2.1 can only limit item by item, and was left out", quiz.Settings.MaxAttempts),
+		})
+	}
+
+	test.OutcomeProcessing.SetOutcomeValue.Identifier = "SCORE"
+	test.OutcomeProcessing.SetOutcomeValue.Sum.TestVariables.VariableIdentifier = "SCORE"
+	test.OutcomeProcessing.SetOutcomeValue.Sum.TestVariables.WeightIdentifier = "WEIGHT"
+
+	for _, s := range quiz.Sections {
+		test.TestPart.AssessmentSection = append(test.TestPart.AssessmentSection, assessmentSection(s, refs, used, &report))
+	}
+
+	pkg.Test = test
+	return pkg, report
+}
+
+// assessmentSection converts a section of the quiz, referring to the items that were converted.
+func assessmentSection(s commoncartridge.QuizSection, refs map[string]AssessmentItemRef, used identifiers, report *Report) AssessmentSection {
+	section := AssessmentSection{
+		Identifier:        used.unique("SECTION_", s.Identifier),
+		Title:             s.Title,
+		Visible:           true,
+		AssessmentItemRef: make([]AssessmentItemRef, 0),
+		AssessmentSection: make([]AssessmentSection, 0),
+	}
+
+	if section.Title == "" {
+		section.Title = section.Identifier
+	}
+
+	for _, q := range s.Questions {
+		if ref, ok := refs[q.Identifier]; ok {
+			section.AssessmentItemRef = append(section.AssessmentItemRef, ref)
+		}
+	}
+
+	for _, sub := range s.Sections {
+		section.AssessmentSection = append(section.AssessmentSection, assessmentSection(sub, refs, used, report))
+	}
+
+	size := len(section.AssessmentItemRef) + len(section.AssessmentSection)
+	if s.SourceBank != "" && len(s.Questions) == 0 {
+		report.Warnings = append(report.Warnings, Skipped{
+			Identifier: s.Identifier,
+			Title:      s.Title,
+			Reason:     fmt.Sprintf("the section draws %d question(s) from the question bank %s, which is not part of the assessment", s.Pick, s.SourceBank),
+		})
+	} else if s.Pick > 0 && s.Pick < size {
+		section.Selection = &Selection{Select: s.Pick}
+	}
+
+	if s.PointsPerItem > 0 {
+		for i := range section.AssessmentItemRef {
+			section.AssessmentItemRef[i].Weight = []Weight{{Identifier: "WEIGHT", Value: strconv.FormatFloat(s.PointsPerItem, 'f', -1, 64)}}
+		}
+	}
+
+	if s.Shuffle {
+		section.Ordering = &Ordering{Shuffle: true}
+	}
+
+	return section
+}
+
+// assessmentItem converts a question to an item with a single RESPONSE, recording in the report what needs manual attention. The identifier of the item is unique among the used ones, which it is added to.
+func assessmentItem(q commoncartridge.Question, used identifiers, report *Report) (AssessmentItem, error) {
+	id := used.unique("ITEM_", q.Identifier)
+	item := AssessmentItem{
+		Xmlns:              qti21Namespace,
+		Xsi:                types.XSINamespace,
+		SchemaLocation:     qti21SchemaLocation,
+		Identifier:         id,
+		Title:              questionName(q),
+		OutcomeDeclaration: []OutcomeDeclaration{scoreDeclaration()},
+		ResponseDeclaration: ResponseDeclaration{
+			Identifier:  "RESPONSE",
+			Cardinality: "single",
+			BaseType:    "identifier",
+		},
+		Href:   "items/" + id + ".xml",
+		Weight: q.Points,
+	}
+
+	content, err := xhtml(q.Text)
+	if err != nil {
+		content = "<p>" + html.EscapeString(commoncartridge.PlainText(q.Text)) + "</p>"
+		report.warn(q, "the question text is not well-formed and was converted to plain text")
+	}
+	item.ItemBody.Content = "<div>" + content + "</div>"
+
+	switch q.Type {
+	case commoncartridge.MultipleChoice, commoncartridge.TrueFalse, commoncartridge.MultipleResponse:
+		correct := correctChoices(q)
+		if len(correct) == 0 {
+			return item, fmt.Errorf("no correct choice")
+		}
+
+		interaction := &ChoiceInteraction{ResponseIdentifier: "RESPONSE", MaxChoices: 1, SimpleChoice: make([]SimpleChoice, 0)}
+		if q.Type == commoncartridge.MultipleResponse {
+			item.ResponseDeclaration.Cardinality = "multiple"
+			interaction.MaxChoices = 0
+		} else if len(correct) != 1 {
+			return item, fmt.Errorf("expected a single correct choice, found %d", len(correct))
+		}
+
+		choices := make(identifiers)
+		if len(q.PartialCredit) > 0 {
+			report.warn(q, fmt.Sprintf("%d choice(s) give part of the score, which the standard templates do not support, and are now incorrect", len(q.PartialCredit)))
+		}
+
+		item.ResponseDeclaration.CorrectResponse = &Values{Value: make([]string, 0)}
+		for _, c := range q.Choices {
+			text, err := xhtml(c.Text)
+			if err != nil {
+				text = html.EscapeString(commoncartridge.SingleLine(c.Text))
+				report.warn(q, fmt.Sprintf("the text of choice %s is not well-formed and was converted to plain text", c.Identifier))
+			}
+
+			choice := SimpleChoice{Identifier: choices.unique("CHOICE_", c.Identifier), Content: text}
+			interaction.SimpleChoice = append(interaction.SimpleChoice, choice)
+			if c.Correct {
+				item.ResponseDeclaration.CorrectResponse.Value = append(item.ResponseDeclaration.CorrectResponse.Value, choice.Identifier)
+			}
+		}
+
+		item.ItemBody.ChoiceInteraction = interaction
+		item.ResponseProcessing = &ResponseProcessing{Template: MatchCorrect}
+	case commoncartridge.FillInTheBlank, commoncartridge.PatternMatch:
+		if len(q.Answers) == 0 {
+			return item, fmt.Errorf("no accepted answer")
+		}
+		if q.Type == commoncartridge.PatternMatch {
+			report.warn(q, "the standard templates do not match substrings, the accepted answers must now be typed in exactly")
+		}
+
+		item.ResponseDeclaration.BaseType = "string"
+		item.ResponseDeclaration.CorrectResponse = &Values{Value: []string{q.Answers[0]}}
+		item.ResponseDeclaration.Mapping = &Mapping{DefaultValue: "0", MapEntry: make([]MapEntry, 0)}
+		for _, a := range q.Answers {
+			item.ResponseDeclaration.Mapping.MapEntry = append(item.ResponseDeclaration.Mapping.MapEntry, MapEntry{MapKey: a, MappedValue: "1", CaseSensitive: q.CaseSensitive})
+		}
+		for _, a := range partialAnswers(q) {
+			value := strconv.FormatFloat(q.PartialCredit[a]/100, 'f', -1, 64)
+			item.ResponseDeclaration.Mapping.MapEntry = append(item.ResponseDeclaration.Mapping.MapEntry, MapEntry{MapKey: a, MappedValue: value, CaseSensitive: q.CaseSensitive})
+		}
+
+		item.ItemBody.TextEntryInteraction = &TextEntryInteraction{ResponseIdentifier: "RESPONSE"}
+		item.ResponseProcessing = &ResponseProcessing{Template: MapResponse}
+	case commoncartridge.Essay:
+		item.ResponseDeclaration.BaseType = "string"
+		item.ItemBody.ExtendedTextInteraction = &ExtendedTextInteraction{ResponseIdentifier: "RESPONSE"}
+		report.warn(q, "essays have no response processing and must be scored manually")
+	default:
+		return item, fmt.Errorf("unsupported question type: %s", q.Type)
+	}
+
+	if q.Feedback != (commoncartridge.Feedback{}) || hasChoiceFeedback(q) {
+		report.warn(q, "the feedback is not supported by the standard templates and was left out")
+	}
+
+	return item, nil
+}
+
+func hasChoiceFeedback(q commoncartridge.Question) bool {
+	for _, c := range q.Choices {
+		if c.Feedback != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func scoreDeclaration() OutcomeDeclaration {
+	return OutcomeDeclaration{
+		Identifier:   "SCORE",
+		Cardinality:  "single",
+		BaseType:     "float",
+		DefaultValue: &Values{Value: []string{"0"}},
+	}
+}
+
+// qti21Identifier turns an identifier into a valid QTI 2.1 identifier, adding the prefix when it does not start with a letter or an underscore (e.g. the numeric identifiers of the choices exported by Canvas).
+func qti21Identifier(prefix string, id string) string {
+	id = identifierPattern.ReplaceAllString(id, "_")
+	if id == "" || !(id[0] == '_' || (id[0] >= 'a' && id[0] <= 'z') || (id[0] >= 'A' && id[0] <= 'Z')) {
+		id = prefix + id
+	}
+
+	return id
+}
+
+// identifiers is the set of the QTI 2.1 identifiers already given, in which each one must be unique.
+type identifiers map[string]bool
+
+// unique returns the QTI 2.1 identifier of the given one, as qti21Identifier does, followed by a number when it was already given (e.g. `a_b_2` when both `a b` and `a/b` are given), and adds it to the set. The identifiers of the items name their files, which would otherwise overwrite each other.
+func (used identifiers) unique(prefix string, id string) string {
+	id = qti21Identifier(prefix, id)
+	unique := id
+	for n := 2; used[unique]; n++ {
+		unique = id + "_" + strconv.Itoa(n)
+	}
+	used[unique] = true
+
+	return unique
+}
+
+// xhtml returns the given HTML content as well-formed XML, which QTI 2.1 requires, closing the void elements and replacing the HTML entities. It returns an error when the content cannot be fixed.
+func xhtml(content string) (string, error) {
+	d := xml.NewDecoder(strings.NewReader("<div>" + content + "</div>"))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	var b strings.Builder
+	e := xml.NewEncoder(&b)
+	depth := 0
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				continue
+			}
+			t.Name.Space = ""
+			token = t
+		case xml.EndElement:
+			depth--
+			if depth == 0 {
+				continue
+			}
+			t.Name.Space = ""
+			token = t
+		case xml.ProcInst, xml.Directive, xml.Comment:
+			continue
+		}
+
+		if err := e.EncodeToken(token); err != nil {
+			return "", err
+		}
+	}
+
+	if err := e.Flush(); err != nil {
+		return "", err
+	}
+	if depth != 0 {
+		return "", fmt.Errorf("unbalanced content")
+	}
+
+	return b.String(), nil
+}
+
+// cpManifest is the IMS content packaging manifest of a QTI 2.1 package.
+type cpManifest struct {
+	XMLName        xml.Name `xml:"manifest"`
+	Xmlns          string   `xml:"xmlns,attr"`
+	Xsi            string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	Identifier     string   `xml:"identifier,attr"`
+	Metadata       struct {
+		Schema        string `xml:"schema"`
+		Schemaversion string `xml:"schemaversion"`
+	} `xml:"metadata"`
+	Organizations string       `xml:"organizations"`
+	Resources     []cpResource `xml:"resources>resource"`
+}
+
+type cpResource struct {
+	Identifier string `xml:"identifier,attr"`
+	Type       string `xml:"type,attr"`
+	Href       string `xml:"href,attr"`
+	File       struct {
+		Href string `xml:"href,attr"`
+	} `xml:"file"`
+	Dependency []cpDependency `xml:"dependency"`
+}
+
+type cpDependency struct {
+	Identifierref string `xml:"identifierref,attr"`
+}
+
+// WriteZip writes the package as a zip archive, with an `imsmanifest.xml` listing the test and its items.
+func (p QTI21Package) WriteZip(w io.Writer) error {
+	manifest := cpManifest{
+		Xmlns:          cpNamespace,
+		Xsi:            types.XSINamespace,
+		SchemaLocation: cpSchemaLocation,
+		Identifier:     "MANIFEST_" + p.Test.Identifier,
+	}
+	manifest.Metadata.Schema = "QTIv2.1 Package"
+	manifest.Metadata.Schemaversion = "1.0.0"
+
+	test := cpResource{Identifier: p.Test.Identifier, Type: "imsqti_test_xmlv2p1", Href: p.Test.Href}
+	test.File.Href = p.Test.Href
+	resources := []cpResource{test}
+	for _, item := range p.Items {
+		res := cpResource{Identifier: item.Identifier, Type: "imsqti_item_xmlv2p1", Href: item.Href}
+		res.File.Href = item.Href
+		resources = append(resources, res)
+
+		resources[0].Dependency = append(resources[0].Dependency, cpDependency{Identifierref: item.Identifier})
+	}
+	manifest.Resources = resources
+
+	files := map[string]interface{}{"imsmanifest.xml": manifest, p.Test.Href: p.Test}
+	names := []string{"imsmanifest.xml", p.Test.Href}
+	for _, item := range p.Items {
+		files[item.Href] = item
+		names = append(names, item.Href)
+	}
+
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		content, err := xml.MarshalIndent(files[name], "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fw, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fw.Write(append([]byte(xml.Header), content...)); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
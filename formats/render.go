@@ -0,0 +1,301 @@
+package formats
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// RenderOptions are the options of the printable documents of a quiz.
+type RenderOptions struct {
+	// AnswerKey appends the correct answers and the feedback of each question to the document.
+	AnswerKey bool
+	// Links resolves the images of the quiz to the files of the cartridge, which are then embedded in the document as data URIs. Images are left untouched when it is nil.
+	Links *commoncartridge.LinkResolver
+}
+
+var (
+	imgPattern     = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	imgAttrPattern = regexp.MustCompile(`(?i)\b(src|alt)\s*=\s*("[^"]*"|'[^']*')`)
+)
+
+// markdownEscaper escapes the characters which have a meaning in inline Markdown.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `\<`)
+
+// renderStyle is the stylesheet of the HTML documents, meant for printing.
+const renderStyle = `body { font-family: sans-serif; max-width: 50em; margin: 2em auto; line-height: 1.4; }
+li.question { margin-bottom: 1.5em; page-break-inside: avoid; }
+ol.choices { list-style-type: lower-alpha; }
+.points, .summary { color: #555; font-size: 0.9em; }
+.blank { border-bottom: 1px solid #000; min-height: 1.5em; width: 20em; }
+.essay { border: 1px solid #000; min-height: 10em; }
+img { max-width: 100%; }
+section.answer-key { page-break-before: always; }
+`
+
+// HTML renders a QTI assessment as a standalone HTML document to be printed, with its questions numbered in order and their choices labelled with letters. The content of the questions is kept as HTML, while plain text is escaped.
+func HTML(qti types.Questestinterop, opts RenderOptions) string {
+	quiz := commoncartridge.NewQuiz(qti)
+	title := html.EscapeString(commoncartridge.SingleLine(quiz.Title))
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<style>\n%s</style>\n</head>\n<body>\n", title, renderStyle)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<p class=\"summary\">%s</p>\n", title, html.EscapeString(summary(quiz)))
+
+	b.WriteString("<ol class=\"questions\">\n")
+	for i, q := range quiz.Questions {
+		fmt.Fprintf(&b, "<li class=\"question\" id=\"q%d\">\n", i+1)
+		fmt.Fprintf(&b, "<div class=\"text\">%s</div>\n<p class=\"points\">(%s)</p>\n", htmlContent(q.Text, opts), points(q.Points))
+
+		switch {
+		case len(q.Choices) > 0:
+			b.WriteString("<ol class=\"choices\">\n")
+			for _, c := range q.Choices {
+				fmt.Fprintf(&b, "<li>%s</li>\n", htmlContent(c.Text, opts))
+			}
+			b.WriteString("</ol>\n")
+		case q.Type == commoncartridge.Essay:
+			b.WriteString("<div class=\"essay\"></div>\n")
+		default:
+			b.WriteString("<div class=\"blank\"></div>\n")
+		}
+
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ol>\n")
+
+	if opts.AnswerKey {
+		b.WriteString("<section class=\"answer-key\">\n<h2>Answer key</h2>\n<ol>\n")
+		for i, q := range quiz.Questions {
+			fmt.Fprintf(&b, "<li value=\"%d\">\n<p><strong>Answer:</strong> %s</p>\n", i+1, htmlAnswer(q, opts))
+			for _, f := range feedbacks(q) {
+				fmt.Fprintf(&b, "<p><strong>%s:</strong> %s</p>\n", html.EscapeString(f[0]), htmlContent(f[1], opts))
+			}
+			b.WriteString("</li>\n")
+		}
+		b.WriteString("</ol>\n</section>\n")
+	}
+
+	b.WriteString("</body>\n</html>\n")
+
+	return b.String()
+}
+
+// htmlAnswer returns the correct answers of a question, as HTML.
+func htmlAnswer(q commoncartridge.Question, opts RenderOptions) string {
+	answers := make([]string, 0)
+	switch {
+	case len(q.Choices) > 0:
+		for i, c := range q.Choices {
+			if c.Correct {
+				answers = append(answers, fmt.Sprintf("(%s) %s", choiceLabel(i), htmlContent(c.Text, opts)))
+			}
+		}
+	default:
+		for _, a := range q.Answers {
+			answers = append(answers, html.EscapeString(a))
+		}
+	}
+
+	if len(answers) == 0 {
+		return "<em>graded manually</em>"
+	}
+
+	return strings.Join(answers, "; ")
+}
+
+// htmlContent returns the HTML content of a question or a choice with its images embedded, or escapes it if it is plain text.
+func htmlContent(content string, opts RenderOptions) string {
+	if !commoncartridge.IsHTML(content) {
+		return html.EscapeString(strings.TrimSpace(content))
+	}
+
+	return embedImages(strings.TrimSpace(content), opts)
+}
+
+// Markdown renders a QTI assessment as a standalone Markdown document, with its questions numbered in order and their choices labelled with letters. The HTML of the questions is converted to plain text, except for the images which are kept as Markdown images.
+func Markdown(qti types.Questestinterop, opts RenderOptions) string {
+	quiz := commoncartridge.NewQuiz(qti)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n_%s_\n\n", markdownText(quiz.Title, opts), summary(quiz))
+
+	for i, q := range quiz.Questions {
+		fmt.Fprintf(&b, "%d. %s _(%s)_\n\n", i+1, indent(markdownText(q.Text, opts)), points(q.Points))
+
+		switch {
+		case len(q.Choices) > 0:
+			for j, c := range q.Choices {
+				fmt.Fprintf(&b, "   - (%s) %s\n", choiceLabel(j), indent(markdownText(c.Text, opts)))
+			}
+		case q.Type == commoncartridge.Essay:
+			b.WriteString("   Answer:\n\n   &nbsp;\n\n   &nbsp;\n\n   &nbsp;\n")
+		default:
+			b.WriteString("   Answer: \\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\\_\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if opts.AnswerKey {
+		b.WriteString("## Answer key\n\n")
+		for i, q := range quiz.Questions {
+			fmt.Fprintf(&b, "%d. **Answer:** %s\n", i+1, markdownAnswer(q, opts))
+			for _, f := range feedbacks(q) {
+				fmt.Fprintf(&b, "   - **%s:** %s\n", f[0], indent(markdownText(f[1], opts)))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// markdownAnswer returns the correct answers of a question, as Markdown.
+func markdownAnswer(q commoncartridge.Question, opts RenderOptions) string {
+	answers := make([]string, 0)
+	switch {
+	case len(q.Choices) > 0:
+		for i, c := range q.Choices {
+			if c.Correct {
+				answers = append(answers, fmt.Sprintf("(%s) %s", choiceLabel(i), commoncartridge.SingleLine(markdownText(c.Text, opts))))
+			}
+		}
+	default:
+		for _, a := range q.Answers {
+			answers = append(answers, markdownEscaper.Replace(a))
+		}
+	}
+
+	if len(answers) == 0 {
+		return "_graded manually_"
+	}
+
+	return strings.Join(answers, "; ")
+}
+
+// markdownText converts HTML content to escaped Markdown text, replacing its images with Markdown images, which are set aside behind private use characters while the text is converted.
+func markdownText(content string, opts RenderOptions) string {
+	images := make([]string, 0)
+	content = imgPattern.ReplaceAllStringFunc(embedImages(content, opts), func(img string) string {
+		var src, alt string
+		for _, m := range imgAttrPattern.FindAllStringSubmatch(img, -1) {
+			value := html.UnescapeString(m[2][1 : len(m[2])-1])
+			if strings.EqualFold(m[1], "src") {
+				src = value
+			} else {
+				alt = value
+			}
+		}
+
+		images = append(images, fmt.Sprintf("![%s](<%s>)", markdownEscaper.Replace(alt), src))
+		return fmt.Sprintf(" \ue010%d\ue011 ", len(images)-1)
+	})
+
+	text := markdownEscaper.Replace(commoncartridge.PlainText(content))
+	for i, img := range images {
+		text = strings.Replace(text, fmt.Sprintf("\ue010%d\ue011", i), img, 1)
+	}
+
+	return text
+}
+
+// indent indents the lines following the first one, so that they stay within their list item.
+func indent(text string) string {
+	return strings.ReplaceAll(text, "\n", "\n   ")
+}
+
+// embedImages replaces the sources of the images of the content which point to files of the cartridge with data URIs.
+func embedImages(content string, opts RenderOptions) string {
+	if opts.Links == nil {
+		return content
+	}
+
+	return imgPattern.ReplaceAllStringFunc(content, func(img string) string {
+		return opts.Links.RewriteLinks(img, func(l commoncartridge.Link) string {
+			if l.Attribute != "src" || l.Path == "" {
+				return l.Raw
+			}
+
+			data, err := opts.Links.ReadFile(l)
+			if err != nil {
+				return l.Raw
+			}
+
+			return dataURI(l.Path, data)
+		})
+	})
+}
+
+// dataURI returns a base64-encoded data URI of the file content, whose media type is guessed from its name, or from its content.
+func dataURI(name string, data []byte) string {
+	mediaType := mime.TypeByExtension(strings.ToLower(path.Ext(name)))
+	if mediaType == "" {
+		mediaType = http.DetectContentType(data)
+	}
+
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// feedbacks returns the labelled feedback texts of a question: its general, correct and incorrect feedback, followed by the feedback of each choice.
+func feedbacks(q commoncartridge.Question) [][2]string {
+	fb := make([][2]string, 0)
+	for _, f := range [][2]string{{"Feedback", q.Feedback.General}, {"Correct", q.Feedback.Correct}, {"Incorrect", q.Feedback.Incorrect}} {
+		if strings.TrimSpace(f[1]) != "" {
+			fb = append(fb, f)
+		}
+	}
+
+	for i, c := range q.Choices {
+		if strings.TrimSpace(c.Feedback) != "" {
+			fb = append(fb, [2]string{"Feedback (" + choiceLabel(i) + ")", c.Feedback})
+		}
+	}
+
+	return fb
+}
+
+// summary returns the number of questions, the total of points and the time limit of a quiz.
+func summary(quiz commoncartridge.Quiz) string {
+	total := 0.0
+	for _, q := range quiz.Questions {
+		total += q.Points
+	}
+
+	s := fmt.Sprintf("%d question(s), %s", len(quiz.Questions), points(total))
+	if quiz.Settings.TimeLimit > 0 {
+		s += fmt.Sprintf(", %d minutes", quiz.Settings.TimeLimit)
+	}
+
+	return s
+}
+
+// points returns the number of points, e.g. `1 point` or `2.5 points`.
+func points(p float64) string {
+	s := strconv.FormatFloat(p, 'f', -1, 64)
+	if p == 1 {
+		return s + " point"
+	}
+
+	return s + " points"
+}
+
+// choiceLabel returns the letter labelling the choice at the given index, e.g. `a` or `aa`.
+func choiceLabel(i int) string {
+	label := ""
+	for i >= 0 {
+		label = string(rune('a'+i%26)) + label
+		i = i/26 - 1
+	}
+
+	return label
+}
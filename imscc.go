@@ -16,7 +16,8 @@ import (
 
 // IMSCC loads the IMSCC-specific cartridge into a zip.Reader from the given Path. It also stores the manifest for convenient access.
 type IMSCC struct {
-	Reader   zip.Reader
+	// Reader is a pointer since a zip.Reader must not be copied: it indexes its files for Open behind a sync.Once, which the value receivers of IMSCC would otherwise copy on every call.
+	Reader   *zip.Reader
 	Path     string
 	manifest types.Manifest
 }
@@ -30,7 +31,7 @@ func Load(path string) (IMSCC, error) {
 		return cc, err
 	}
 
-	cc.Reader = r.Reader
+	cc.Reader = &r.Reader
 	cc.Path = path
 	cc.manifest, err = cc.parseManifest()
 
@@ -57,25 +58,45 @@ type Metadata struct {
 	Date                 string
 	Copyright            string
 	CopyrightDescription string
+	// CourseCode, StartAt and ConcludeAt are only found in the extension files of some learning management systems, such as the course settings of Canvas.
+	CourseCode string `json:",omitempty"`
+	StartAt    string `json:",omitempty"`
+	ConcludeAt string `json:",omitempty"`
 }
 
-// Metadata returns a user-friendly, stringified, JSON-encoded version of the Metadata field.
+// Metadata returns a user-friendly, stringified, JSON-encoded version of the Metadata field, completed by the registered extensions that the cartridge follows. An extension which fails, e.g. on a malformed settings file, leaves the metadata as the manifest has it while the others still apply: its error is not returned, but reported by ExtensionErrors.
 func (cc IMSCC) Metadata() (string, error) {
+	meta, _ := cc.metadata()
+	serialized, err := json.Marshal(meta)
+	return string(serialized), err
+}
+
+// metadata returns the metadata of the manifest completed by the extensions, and the errors of the extensions which failed, prefixed by their name.
+func (cc IMSCC) metadata() (Metadata, []error) {
 
 	meta := Metadata{
-		cc.manifest.Metadata.Lom.General.Title.String.Text,
-		cc.manifest.Metadata.Schema,
-		cc.manifest.Metadata.Schemaversion,
-		cc.manifest.Metadata.Lom.General.Language,
-		cc.manifest.Metadata.Lom.General.Description.String.Text,
-		cc.manifest.Metadata.Lom.General.Keyword.String.Text,
-		cc.manifest.Metadata.Lom.LifeCycle.Contribute.Date.DateTime,
-		cc.manifest.Metadata.Lom.Rights.CopyrightAndOtherRestrictions.Value,
-		cc.manifest.Metadata.Lom.Rights.Description.String,
+		Title:                cc.manifest.Metadata.Lom.General.Title.String.Text,
+		Schema:               cc.manifest.Metadata.Schema,
+		SchemaVersion:        cc.manifest.Metadata.Schemaversion,
+		Language:             cc.manifest.Metadata.Lom.General.Language,
+		Description:          cc.manifest.Metadata.Lom.General.Description.String.Text,
+		Keyword:              cc.manifest.Metadata.Lom.General.Keyword.String.Text,
+		Date:                 cc.manifest.Metadata.Lom.LifeCycle.Contribute.Date.DateTime,
+		Copyright:            cc.manifest.Metadata.Lom.Rights.CopyrightAndOtherRestrictions.Value,
+		CopyrightDescription: cc.manifest.Metadata.Lom.Rights.Description.String,
 	}
 
-	serialized, err := json.Marshal(meta)
-	return string(serialized), err
+	errs := make([]error, 0)
+	for _, e := range cc.Extensions() {
+		extended := meta
+		if err := e.ExtendMetadata(cc, &extended); err != nil {
+			errs = append(errs, fmt.Errorf("extension %s: %w", e.Name(), err))
+			continue
+		}
+		meta = extended
+	}
+
+	return meta, errs
 }
 
 // FullItem is a union of an Item and all Resources that refer to it, along with possible children.
@@ -83,24 +104,53 @@ type FullItem struct {
 	Resources []types.Resource
 	Item      types.Item
 	Children  []FullItem
+	// Settings are the settings of the module or of the module item, as exported by some learning management systems, or nil.
+	Settings *ItemSettings `json:",omitempty"`
 }
 
-// Items returns all items with their associated resources. It goes through each item at the top level and recursively looks for FullItems at the level n-1.
+// Items returns all items with their associated resources. It goes through each item at the top level and recursively looks for FullItems at the level n-1. The registered extensions that the cartridge follows then add their settings to the items. As for Metadata, an extension which fails leaves the items as they are while the others still apply, and its error is reported by ExtensionErrors.
 func (cc IMSCC) Items() ([]FullItem, error) {
-	items := make([]FullItem, 0)
+	items, _, err := cc.items()
+	return items, err
+}
+
+// items returns the items of the manifest extended by the extensions, the errors of the extensions which failed, prefixed by their name, and an error when the items of the manifest cannot be read.
+func (cc IMSCC) items() ([]FullItem, []error, error) {
+	items, errs := make([]FullItem, 0), make([]error, 0)
 
 	//-- A CC always have only one top level item, so we can directly jump to its children
 	for _, i := range cc.manifest.Organizations.Organization.Item.Item {
 		full, err := cc.traverseItems(i)
 
 		if err != nil {
-			return items, err
+			return items, errs, err
 		}
 
 		items = append(items, full)
 	}
 
-	return items, nil
+	for _, e := range cc.Extensions() {
+		extended, err := e.ExtendItems(cc, items)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("extension %s: %w", e.Name(), err))
+			continue
+		}
+		items = extended
+	}
+
+	return items, errs, nil
+}
+
+// ExtensionErrors returns the errors of the registered extensions that the cartridge follows which failed to complete its metadata or its items, e.g. on a malformed settings file, each prefixed by the name of the extension. Metadata and Items leave out what these extensions would have added, without returning their errors.
+func (cc IMSCC) ExtensionErrors() []error {
+	_, errs := cc.metadata()
+
+	_, itemErrs, err := cc.items()
+	if err != nil {
+		return append(errs, err)
+	}
+
+	return append(errs, itemErrs...)
 }
 
 // traverseItems checks that an Item has an identifierref—e.g. that it is being refered to by a resource—, then appends referring resources and recursively appends children Items.
@@ -455,19 +505,22 @@ func (cc IMSCC) findResourcesByType(pattern string) ([]string, error) {
 	return paths, nil
 }
 
-// parseManifest finds and marshals the imsccmanifest.xml file into the Manifest struct
-func (cc IMSCC) parseManifest() (types.Manifest, error) {
-
-	var manifest types.Manifest
-	var path string
+// manifestFile returns the imsmanifest.xml file of the archive, which is usually at its root but can sit in a folder, e.g. `course/imsmanifest.xml`, or nil if there is none.
+func (cc IMSCC) manifestFile() *zip.File {
 	for _, f := range cc.Reader.File {
 		if strings.Contains(f.Name, "imsmanifest.xml") {
-			path = f.Name
-			break
+			return f
 		}
 	}
 
-	file, err := cc.Reader.Open(path)
+	return nil
+}
+
+// parseManifest finds and marshals the imsccmanifest.xml file into the Manifest struct
+func (cc IMSCC) parseManifest() (types.Manifest, error) {
+
+	var manifest types.Manifest
+	file, err := cc.Reader.Open(cc.ManifestPath())
 
 	if err != nil {
 		fmt.Printf("Error in opening manifest: %v\n", cc.Path)
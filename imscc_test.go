@@ -190,7 +190,8 @@ func TestMarshalJSON(t *testing.T) {
 	assert.NotEqual(t, len(obj), 0)
 }
 
-func load(t *testing.T, p string) Cartridge {
+// load returns the cartridge at the given path, as the CourseCartridge that IMSCC implements.
+func load(t *testing.T, p string) CourseCartridge {
 	cc, err := Load(p)
 	require.Nil(t, err)
 	return cc
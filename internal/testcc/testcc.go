@@ -0,0 +1,79 @@
+// Package testcc builds the cartridges used by the tests of this module, from the sample exports of the `test_files` folder or from synthetic files. It only writes archives, so that the tests of the root package can use it as well as those of the extensions.
+package testcc
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// SingleTestFile is the Canvas export that most tests are run against, and AllTestFilesDir the folder of the other sample exports.
+	SingleTestFile  = filepath.Join(testFilesDir(), "test_01.imscc")
+	AllTestFilesDir = filepath.Join(testFilesDir(), "dump")
+)
+
+// testFilesDir returns the path of the `test_files` folder at the root of the module, wherever the tests run from.
+func testFilesDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "test_files")
+}
+
+// Archive writes a cartridge made of the given files, in the order of their paths, to a temporary file and returns its path.
+func Archive(t *testing.T, files map[string]string) string {
+	return write(t, "archive.imscc", func(zw *zip.Writer) {
+		add(t, zw, files)
+	})
+}
+
+// Rewrite writes a copy of the cartridge at the given path, in which the given files are replaced or added, to a temporary file and returns its path.
+func Rewrite(t *testing.T, p string, files map[string]string) string {
+	r, err := zip.OpenReader(p)
+	require.Nil(t, err)
+	defer r.Close()
+
+	return write(t, filepath.Base(p), func(zw *zip.Writer) {
+		for _, f := range r.File {
+			if _, ok := files[f.Name]; !ok {
+				require.Nil(t, zw.Copy(f))
+			}
+		}
+		add(t, zw, files)
+	})
+}
+
+// write creates a zip archive with the given name in a temporary folder, fills it and returns its path.
+func write(t *testing.T, name string, fill func(zw *zip.Writer)) string {
+	dst := filepath.Join(t.TempDir(), name)
+	file, err := os.Create(dst)
+	require.Nil(t, err)
+
+	zw := zip.NewWriter(file)
+	fill(zw)
+	require.Nil(t, zw.Close())
+	require.Nil(t, file.Close())
+
+	return dst
+}
+
+// add writes the given files to the archive, in the order of their paths.
+func add(t *testing.T, zw *zip.Writer, files map[string]string) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		w, err := zw.Create(name)
+		require.Nil(t, err)
+		_, err = io.WriteString(w, files[name])
+		require.Nil(t, err)
+	}
+}
@@ -0,0 +1,282 @@
+package commoncartridge
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// FileBase is the substitution token used by the IMSCC standard to refer to the location of the files of a cartridge from within HTML content.
+const FileBase = "$IMS-CC-FILEBASE$"
+
+// webResourcesDir is the folder in which some exporters (e.g. Canvas) place all the files that `$IMS-CC-FILEBASE$` refers to.
+const webResourcesDir = "web_resources"
+
+// linkPattern matches the `src` and `href` attributes of HTML elements, with either double or single quotes, along with the whitespace before them, so that attributes such as `data-src` are left out.
+var linkPattern = regexp.MustCompile(`(?i)(^|\s)(src|href)\s*=\s*("[^"]*"|'[^']*')`)
+
+// Link is a reference found in the `src` or `href` attribute of an HTML fragment, along with the file and resource it points to within the cartridge.
+type Link struct {
+	// Attribute is the name of the attribute the link was found in, either `src` or `href`.
+	Attribute string
+	// Raw is the value of the attribute, as found in the HTML.
+	Raw string
+	// Path is the path of the file in the archive, or empty if the link could not be resolved to a file.
+	Path string
+	// Identifier is the identifier of the resource which contains the file at Path, if any.
+	Identifier string
+	// External is true when the link points outside of the cartridge (e.g. `https://`, `mailto:`).
+	External bool
+}
+
+// UnresolvedLink is a link of the content of a cartridge which points neither outside of the cartridge nor to one of its files, such as a vendor token which could not be resolved.
+type UnresolvedLink struct {
+	// Source is the path of the file in which the link is found.
+	Source string
+	Link
+}
+
+// Unresolved returns true when the link points neither outside of the cartridge nor to one of its files, leaving out empty links and the anchors of the page the link is in.
+func (l Link) Unresolved() bool {
+	raw := strings.TrimSpace(l.Raw)
+	return !l.External && l.Path == "" && raw != "" && !strings.HasPrefix(raw, "#")
+}
+
+// LinkResolver resolves the links found in HTML content relative to the location of a given resource.
+type LinkResolver struct {
+	cc    IMSCC
+	base  string
+	files map[string]bool
+}
+
+// NewLinkResolver returns a LinkResolver whose relative links are resolved from the base directory, within the archive of the cartridge.
+func (cc IMSCC) NewLinkResolver(base string) LinkResolver {
+	lr := LinkResolver{
+		cc:    cc,
+		base:  path.Clean(base),
+		files: make(map[string]bool),
+	}
+
+	for _, f := range cc.Reader.File {
+		lr.files[f.Name] = true
+	}
+
+	return lr
+}
+
+// WithBase returns a copy of the LinkResolver whose relative links are resolved from another base directory, sharing the index of the files of the archive.
+func (lr LinkResolver) WithBase(base string) LinkResolver {
+	lr.base = path.Clean(base)
+	return lr
+}
+
+// ResourceLinkResolver returns a LinkResolver whose relative links are resolved from the directory of the main file of the resource with the given id.
+func (cc IMSCC) ResourceLinkResolver(id string) (LinkResolver, error) {
+	r, err := cc.findResource(id)
+	if err != nil {
+		return LinkResolver{}, err
+	}
+
+	return cc.NewLinkResolver(resourceDir(r)), nil
+}
+
+// Links returns all the links found in the HTML content of the resource with the given id: the text of a topic or an assignment, the `mattext` of a QTI, or the HTML files of a webcontent.
+func (cc IMSCC) Links(id string) ([]Link, error) {
+	links := make([]Link, 0)
+
+	r, err := cc.findResource(id)
+	if err != nil {
+		return links, err
+	}
+
+	fragments, err := cc.resourceHTML(r)
+	if err != nil {
+		return links, err
+	}
+
+	lr := cc.NewLinkResolver(resourceDir(r))
+	for _, f := range fragments {
+		links = append(links, lr.Links(f)...)
+	}
+
+	return links, nil
+}
+
+// Links returns all the `src` and `href` references found in the given HTML, resolved to the files and resources of the cartridge.
+func (lr LinkResolver) Links(content string) []Link {
+	links := make([]Link, 0)
+	for _, m := range linkPattern.FindAllStringSubmatch(content, -1) {
+		links = append(links, lr.Resolve(m[2], m[3][1:len(m[3])-1]))
+	}
+
+	return links
+}
+
+// RewriteLinks replaces the value of every `src` and `href` attribute in the given HTML with the value returned by fn for the corresponding Link.
+func (lr LinkResolver) RewriteLinks(content string, fn func(ref Link) string) string {
+	return linkPattern.ReplaceAllStringFunc(content, func(attr string) string {
+		m := linkPattern.FindStringSubmatch(attr)
+		quote := m[3][:1]
+		link := lr.Resolve(m[2], m[3][1:len(m[3])-1])
+
+		value := strings.ReplaceAll(fn(link), quote, html.EscapeString(quote))
+
+		return fmt.Sprintf("%s%s=%s%s%s", m[1], m[2], quote, value, quote)
+	})
+}
+
+// Resolve returns the Link corresponding to the raw value of the given attribute. Links starting with `$IMS-CC-FILEBASE$` are first looked up relative to the base directory, then in the `web_resources` folder.
+func (lr LinkResolver) Resolve(attribute, raw string) Link {
+	link := Link{Attribute: strings.ToLower(attribute), Raw: raw}
+
+	ref := html.UnescapeString(strings.TrimSpace(raw))
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		ref = ref[:i]
+	}
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+
+	if ref == "" {
+		return link
+	}
+
+	if u, err := url.Parse(ref); err == nil && (u.Scheme != "" || u.Host != "") {
+		link.External = true
+		return link
+	}
+
+	candidates := make([]string, 0)
+	switch {
+	case strings.HasPrefix(ref, FileBase):
+		rest := strings.TrimPrefix(ref, FileBase)
+		candidates = append(candidates, path.Join(lr.base, rest), path.Join(webResourcesDir, rest))
+	case strings.HasPrefix(ref, "$"):
+		//-- other substitution tokens are vendor-specific
+		return link
+	case strings.HasPrefix(ref, "/"):
+		candidates = append(candidates, strings.TrimPrefix(path.Clean(ref), "/"))
+	default:
+		candidates = append(candidates, path.Join(lr.base, ref))
+	}
+
+	for _, c := range candidates {
+		if lr.files[c] {
+			link.Path = c
+			link.Identifier = lr.cc.findResourceByFile(c)
+			break
+		}
+	}
+
+	return link
+}
+
+// ReadFile returns the content of the file of the cartridge that the link points to.
+func (lr LinkResolver) ReadFile(link Link) ([]byte, error) {
+	if link.Path == "" {
+		return nil, fmt.Errorf("the link %s does not point to a file of the cartridge", link.Raw)
+	}
+
+	return lr.cc.ReadFile(link.Path)
+}
+
+// findResource returns the manifest resource with the given identifier.
+func (cc IMSCC) findResource(id string) (types.Resource, error) {
+	for _, r := range cc.manifest.Resources.Resource {
+		if r.Identifier == id {
+			return r, nil
+		}
+	}
+
+	return types.Resource{}, fmt.Errorf("could not find resource with id: %v", id)
+}
+
+// findResourceByFile returns the identifier of the resource which has the given path as its href or as one of its files, giving priority to the href.
+func (cc IMSCC) findResourceByFile(p string) string {
+	for _, r := range cc.manifest.Resources.Resource {
+		if r.Href == p {
+			return r.Identifier
+		}
+	}
+
+	for _, r := range cc.manifest.Resources.Resource {
+		for _, f := range r.File {
+			if f.Href == p {
+				return r.Identifier
+			}
+		}
+	}
+
+	return ""
+}
+
+// resourceDir returns the directory in which the main file of the resource is located.
+func resourceDir(r types.Resource) string {
+	if r.Href != "" {
+		return path.Dir(r.Href)
+	}
+
+	if len(r.File) > 0 {
+		return path.Dir(r.File[0].Href)
+	}
+
+	return "."
+}
+
+// resourceHTML returns all the HTML fragments contained in a resource, depending on its type.
+func (cc IMSCC) resourceHTML(r types.Resource) ([]string, error) {
+	fragments := make([]string, 0)
+
+	if r.Type == "webcontent" {
+		for _, f := range r.File {
+			ext := strings.ToLower(path.Ext(f.Href))
+			if ext != ".html" && ext != ".htm" {
+				continue
+			}
+
+			file, err := cc.Reader.Open(f.Href)
+			if err != nil {
+				return fragments, err
+			}
+
+			bytes, err := io.ReadAll(file)
+			if err != nil {
+				return fragments, err
+			}
+
+			fragments = append(fragments, string(bytes))
+		}
+
+		return fragments, nil
+	}
+
+	found, err := cc.Find(r.Identifier)
+	if err != nil {
+		return fragments, err
+	}
+
+	switch res := found.(type) {
+	case types.Topic:
+		fragments = append(fragments, res.Text.Text)
+	case types.Assignment:
+		fragments = append(fragments, res.Text.Text)
+	case types.Questestinterop:
+		for _, item := range res.Items() {
+			fragments = append(fragments, item.Presentation.Material.Mattext.Text)
+			for _, label := range item.Presentation.ResponseLid.RenderChoice.ResponseLabel {
+				fragments = append(fragments, label.Material.Mattext.Text)
+			}
+			for _, feedback := range item.Itemfeedback {
+				fragments = append(fragments, feedback.FlowMat.Material.Mattext.Text)
+			}
+		}
+	}
+
+	return fragments, nil
+}
@@ -0,0 +1,101 @@
+package commoncartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinks(t *testing.T) {
+	cc := load(t, singleTestFile)
+	links, err := cc.Links("i4f8a2f796e0466d931a65228358e5124")
+	require.Nil(t, err)
+
+	assert.Equal(t, len(links), 2)
+	assert.Equal(t, links[0].Attribute, "href")
+	assert.True(t, links[0].External)
+	assert.Equal(t, links[1].Attribute, "src")
+
+	_, err = cc.Links("not-an-id")
+	assert.NotNil(t, err)
+}
+
+func TestResolveLink(t *testing.T) {
+	cc, err := Load(singleTestFile)
+	require.Nil(t, err)
+	lr := cc.NewLinkResolver("wiki_content")
+
+	for _, raw := range []string{
+		"$IMS-CC-FILEBASE$/Example%20File.jpg",
+		"%24IMS-CC-FILEBASE%24/Example%20File.jpg?canvas_download=1",
+		"../web_resources/Example File.jpg",
+		"/web_resources/Example%20File.jpg",
+	} {
+		link := lr.Resolve("src", raw)
+		assert.Equal(t, "web_resources/Example File.jpg", link.Path, raw)
+		assert.Equal(t, "i5cb9a100311595ea2ffc33d3f2f48b36", link.Identifier, raw)
+		assert.False(t, link.External)
+	}
+
+	link := lr.Resolve("href", "$WIKI_REFERENCE$/pages/front-page")
+	assert.Equal(t, "", link.Path)
+	assert.False(t, link.External)
+
+	link = lr.Resolve("href", "mailto:teacher@example.com")
+	assert.True(t, link.External)
+
+	link = lr.Resolve("href", "front-page.html")
+	assert.Equal(t, "wiki_content/front-page.html", link.Path)
+	assert.Equal(t, "i4f8a2f796e0466d931a65228358e5124", link.Identifier)
+
+	link = lr.WithBase("web_resources").Resolve("href", "Example Folder/Example.doc")
+	assert.Equal(t, "web_resources/Example Folder/Example.doc", link.Path)
+	link = lr.Resolve("href", "Example Folder/Example.doc")
+	assert.Equal(t, "", link.Path, "the base of the original resolver is left as is")
+}
+
+func TestRewriteLinks(t *testing.T) {
+	cc, err := Load(singleTestFile)
+	require.Nil(t, err)
+	lr := cc.NewLinkResolver("wiki_content")
+
+	content := `<p><img src="$IMS-CC-FILEBASE$/Example%20File.jpg" alt="example"> <a href='https://example.com'>link</a></p>`
+	rewritten := lr.RewriteLinks(content, func(ref Link) string {
+		if ref.Path == "" {
+			return ref.Raw
+		}
+		return "/files/" + ref.Identifier
+	})
+
+	assert.Equal(t, `<p><img src="/files/i5cb9a100311595ea2ffc33d3f2f48b36" alt="example"> <a href='https://example.com'>link</a></p>`, rewritten)
+
+	content = `<img data-src="lazy.jpg" src="$IMS-CC-FILEBASE$/Example%20File.jpg"><a data-href="x.html"
+href="front-page.html">page</a>`
+	links := lr.Links(content)
+	require.Equal(t, len(links), 2)
+	assert.Equal(t, links[0].Raw, "$IMS-CC-FILEBASE$/Example%20File.jpg")
+	assert.Equal(t, links[1].Raw, "front-page.html")
+
+	rewritten = lr.RewriteLinks(content, func(ref Link) string { return "/files/" + ref.Identifier })
+	assert.Equal(t, `<img data-src="lazy.jpg" src="/files/i5cb9a100311595ea2ffc33d3f2f48b36"><a data-href="x.html"
+href="/files/i4f8a2f796e0466d931a65228358e5124">page</a>`, rewritten)
+}
+
+func TestReadLinkedFile(t *testing.T) {
+	cc, err := Load(singleTestFile)
+	require.Nil(t, err)
+
+	lr, err := cc.ResourceLinkResolver("i4f8a2f796e0466d931a65228358e5124")
+	require.Nil(t, err)
+
+	data, err := lr.ReadFile(lr.Resolve("src", "$IMS-CC-FILEBASE$/Example%20File.jpg"))
+	require.Nil(t, err)
+	assert.NotEmpty(t, data)
+
+	_, err = lr.ReadFile(lr.Resolve("href", "https://example.com"))
+	assert.NotNil(t, err)
+
+	_, err = cc.ResourceLinkResolver("not-an-id")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,64 @@
+// The moodle package decodes the conventions of the cartridges that Moodle exports, which carry the structure of the course in the organization of the manifest only: its sections, with their summaries, and the labels placed between their activities. Importing the package registers its Extension, which sets the type of the sections and items of Moodle cartridges.
+package moodle
+
+import (
+	"github.com/commonsyllabi/commoncartridge"
+)
+
+func init() {
+	commoncartridge.RegisterExtension(Extension{})
+}
+
+// Extension is the commoncartridge.Extension of Moodle exports.
+type Extension struct{}
+
+// Name returns `moodle`.
+func (Extension) Name() string {
+	return commoncartridge.MoodleProducer
+}
+
+// Detect returns true when the manifest of the cartridge points to Moodle, e.g. because it names Moodle as its generator or uses a Moodle namespace. Only the manifest is read, see commoncartridge.IMSCC.ManifestProducer.
+func (Extension) Detect(cc commoncartridge.IMSCC) bool {
+	producer, err := cc.ManifestProducer()
+	return err == nil && producer.Name == commoncartridge.MoodleProducer
+}
+
+// ExtendMetadata leaves the metadata as is, since Moodle exports all of it in the manifest.
+func (Extension) ExtendMetadata(cc commoncartridge.IMSCC, meta *commoncartridge.Metadata) error {
+	return nil
+}
+
+// ExtendItems sets the position and type of the sections of the course, and of their items at any depth: SectionType for the sections, SummaryType and LabelType for their summaries and labels, and the name of the Moodle activity of the others, e.g. `page` or `quiz`.
+func (Extension) ExtendItems(cc commoncartridge.IMSCC, items []commoncartridge.FullItem) ([]commoncartridge.FullItem, error) {
+	sections, err := Sections(cc)
+	if err != nil {
+		return items, err
+	}
+
+	for i := range items {
+		for _, s := range sections {
+			if s.Identifier != items[i].Item.Identifier {
+				continue
+			}
+
+			items[i].Settings = &commoncartridge.ItemSettings{Position: s.Position, ContentType: SectionType}
+			activities := make(map[string]Item)
+			for _, item := range s.Items {
+				activities[item.Identifier] = item
+			}
+			extendChildren(items[i].Children, activities)
+		}
+	}
+
+	return items, nil
+}
+
+// extendChildren sets the position and type of the given items and of their children, from the activities of their section by identifier.
+func extendChildren(children []commoncartridge.FullItem, activities map[string]Item) {
+	for j := range children {
+		if item, ok := activities[children[j].Item.Identifier]; ok {
+			children[j].Settings = &commoncartridge.ItemSettings{Position: item.Position, ContentType: item.Type}
+		}
+		extendChildren(children[j].Children, activities)
+	}
+}
@@ -0,0 +1,123 @@
+package moodle
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moodleFiles are the files of a cartridge following the conventions of Moodle exports.
+var moodleFiles = map[string]string{
+	"imsmanifest.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<!-- This package has been created with Moodle 4.1 -->
+<manifest identifier="M_1" xmlns="http://www.imsglobal.org/xsd/imsccv1p1/imscp_v1p1">
+  <metadata><schema>IMS Common Cartridge</schema><schemaversion>1.1.0</schemaversion></metadata>
+  <organizations>
+    <organization identifier="O_1" structure="rooted-hierarchy">
+      <item identifier="I_root">
+        <item identifier="S_0" identifierref="R_S0">
+          <title>General</title>
+          <item identifier="I_1" identifierref="R_1"><title>Announcements</title></item>
+        </item>
+        <item identifier="S_1">
+          <title>Topic 1</title>
+          <item identifier="I_2" identifierref="R_2"><title>Topic 1</title></item>
+          <item identifier="I_3" identifierref="R_3"><title>Read the following chapters before...</title></item>
+          <item identifier="I_4" identifierref="R_4"><title>Introduction</title></item>
+          <item identifier="I_5"><title>Optional readings &amp; videos</title></item>
+          <item identifier="I_6" identifierref="R_6"><title>Quiz 1</title></item>
+        </item>
+      </item>
+    </organization>
+  </organizations>
+  <resources>
+    <resource identifier="R_S0" type="webcontent" href="S_0/summary.html"><file href="S_0/summary.html"/></resource>
+    <resource identifier="R_1" type="imsdt_xmlv1p1"><file href="R_1/forum.xml"/></resource>
+    <resource identifier="R_2" type="webcontent" href="R_2/page.html"><file href="R_2/page.html"/></resource>
+    <resource identifier="R_3" type="webcontent" href="R_3/page.html"><file href="R_3/page.html"/></resource>
+    <resource identifier="R_4" type="webcontent" href="R_4/introduction.html"><file href="R_4/introduction.html"/></resource>
+    <resource identifier="R_6" type="imsqti_xmlv1p2/imscc_xmlv1p1/assessment"><file href="R_6/assessment.xml"/></resource>
+    <resource identifier="R_QB" type="imsqti_xmlv1p2/imscc_xmlv1p1/question-bank"><file href="QB/questions.xml"/></resource>
+  </resources>
+</manifest>`,
+	"S_0/summary.html":      `<p>Welcome to the course</p>`,
+	"R_1/forum.xml":         `<topic/>`,
+	"R_2/page.html":         `<p>In this topic, we read <em>Hamlet</em>.</p>`,
+	"R_3/page.html":         `<html><body><p>Read the following chapters before the first class.</p></body></html>`,
+	"R_4/introduction.html": `<h1>Introduction</h1><p>The introduction of the course.</p>`,
+	"R_6/assessment.xml":    `<questestinterop/>`,
+	"QB/questions.xml": `<questestinterop>
+  <objectbank ident="QB_1">
+    <qtimetadata><qtimetadatafield><fieldlabel>bank_title</fieldlabel><fieldentry>Default for Literature</fieldentry></qtimetadatafield></qtimetadata>
+    <item ident="q1" title="Author">
+      <itemmetadata><qtimetadata><qtimetadatafield><fieldlabel>cc_profile</fieldlabel><fieldentry>cc.essay.v0p1</fieldentry></qtimetadatafield></qtimetadata></itemmetadata>
+      <presentation><material><mattext texttype="text/html">Who wrote Hamlet?</mattext></material></presentation>
+    </item>
+    <section ident="QB_2" title="Week 1">
+      <item ident="q2" title="Setting">
+        <itemmetadata><qtimetadata><qtimetadatafield><fieldlabel>cc_profile</fieldlabel><fieldentry>cc.essay.v0p1</fieldentry></qtimetadatafield></qtimetadata></itemmetadata>
+        <presentation><material><mattext texttype="text/html">Where does Hamlet take place?</mattext></material></presentation>
+      </item>
+    </section>
+  </objectbank>
+</questestinterop>`,
+}
+
+func TestDetect(t *testing.T) {
+	cc := load(t, testcc.Archive(t, moodleFiles))
+	require.Equal(t, len(cc.Extensions()), 1)
+	assert.Equal(t, cc.Extensions()[0].Name(), "moodle")
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	assert.Empty(t, cc.Extensions())
+}
+
+func TestExtendItems(t *testing.T) {
+	cc := load(t, testcc.Archive(t, moodleFiles))
+	items, err := cc.Items()
+	require.Nil(t, err)
+
+	require.Equal(t, len(items), 2)
+	require.NotNil(t, items[1].Settings)
+	assert.Equal(t, items[1].Settings.ContentType, SectionType)
+	assert.Equal(t, items[1].Settings.Position, 2)
+
+	types := make([]string, 0)
+	for _, child := range items[1].Children {
+		require.NotNil(t, child.Settings)
+		types = append(types, child.Settings.ContentType)
+	}
+	assert.Equal(t, types, []string{SummaryType, LabelType, "page", LabelType, "quiz"})
+}
+
+func TestExtendNestedItems(t *testing.T) {
+	files := make(map[string]string)
+	for name, content := range moodleFiles {
+		files[name] = content
+	}
+	files["imsmanifest.xml"] = strings.Replace(files["imsmanifest.xml"], `<item identifier="I_5"><title>Optional readings &amp; videos</title></item>
+          <item identifier="I_6" identifierref="R_6"><title>Quiz 1</title></item>`, `<item identifier="I_5"><title>Optional readings &amp; videos</title>
+            <item identifier="I_6" identifierref="R_6"><title>Quiz 1</title></item>
+          </item>`, 1)
+
+	cc := load(t, testcc.Archive(t, files))
+	items, err := cc.Items()
+	require.Nil(t, err)
+
+	require.Equal(t, len(items[1].Children), 4)
+	group := items[1].Children[3]
+	require.Equal(t, len(group.Children), 1)
+	require.NotNil(t, group.Children[0].Settings)
+	assert.Equal(t, group.Children[0].Settings.ContentType, "quiz")
+}
+
+func load(t *testing.T, p string) commoncartridge.IMSCC {
+	cc, err := commoncartridge.Load(p)
+	require.Nil(t, err)
+	return cc
+}
@@ -0,0 +1,88 @@
+package moodle
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// QuestionCategory is a category of the question bank of a Moodle course, with its questions and subcategories.
+type QuestionCategory struct {
+	// Identifier is the identifier of the resource of the question bank, or the ident of the section of a subcategory.
+	Identifier string
+	Title      string
+	// Path is the path of the QTI document of the question bank in the archive.
+	Path       string
+	Questions  []commoncartridge.Question
+	Categories []QuestionCategory
+}
+
+// QuestionCategories returns the question categories of a Moodle course. Moodle exports its question bank as resources of type `imsqti_xmlv1p2/imscc_xmlv1p1/question-bank`, each of them being a category titled by the `bank_title` of its metadata, whose sections are its subcategories.
+func QuestionCategories(cc commoncartridge.IMSCC) ([]QuestionCategory, error) {
+	categories := make([]QuestionCategory, 0)
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return categories, err
+	}
+
+	for _, r := range manifest.Resources.Resource {
+		if !strings.HasPrefix(r.Type, "imsqti_") || !strings.HasSuffix(r.Type, "/question-bank") {
+			continue
+		}
+
+		content, err := cc.ReadFile(commoncartridge.ResourceHref(r))
+		if err != nil {
+			return categories, err
+		}
+
+		var qti types.QuestionBankQTI
+		if err := xml.Unmarshal(content, &qti); err != nil {
+			return categories, fmt.Errorf("could not read the question bank %s: %w", r.Identifier, err)
+		}
+
+		category := QuestionCategory{
+			Identifier: r.Identifier,
+			Title:      strings.TrimSpace(qti.Objectbank.Qtimetadata.Field("bank_title")),
+			Path:       commoncartridge.ResourceHref(r),
+			Questions:  questions(qti.Objectbank.Item),
+			Categories: subcategories(qti.Objectbank.Section, commoncartridge.ResourceHref(r)),
+		}
+		if category.Title == "" {
+			category.Title = qti.Objectbank.Ident
+		}
+
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
+
+// subcategories returns the categories of the given sections of a question bank.
+func subcategories(sections []types.Section, p string) []QuestionCategory {
+	categories := make([]QuestionCategory, 0)
+	for _, s := range sections {
+		categories = append(categories, QuestionCategory{
+			Identifier: s.Ident,
+			Title:      strings.TrimSpace(s.Title),
+			Path:       p,
+			Questions:  questions(s.Item),
+			Categories: subcategories(s.Section, p),
+		})
+	}
+
+	return categories
+}
+
+// questions returns the questions of the given QTI items.
+func questions(items []types.QTIItem) []commoncartridge.Question {
+	questions := make([]commoncartridge.Question, 0)
+	for _, item := range items {
+		questions = append(questions, commoncartridge.NewQuestion(item))
+	}
+
+	return questions
+}
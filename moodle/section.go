@@ -0,0 +1,212 @@
+package moodle
+
+import (
+	"html"
+	"path"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// The types of the sections and items of a Moodle course, besides the names of the activities of the other items.
+const (
+	SectionType = "section"
+	SummaryType = "summary"
+	LabelType   = "label"
+)
+
+// Section is a section of a Moodle course, i.e. a topic or a week, with its summary and items.
+type Section struct {
+	Identifier string
+	Title      string
+	// Position is the position of the section in the course, starting at 1.
+	Position int
+	// Summary is HTML, and is empty when the section has none.
+	Summary string
+	Items   []Item
+}
+
+// Item is an activity, resource or label of a section of a Moodle course.
+type Item struct {
+	Identifier string
+	// Identifierref is the identifier of the resource of the item, if any. Text-only labels have none.
+	Identifierref string
+	Title         string
+	// Position is the position of the item in its section, starting at 1.
+	Position int
+	// Type is SummaryType, LabelType, or the name of the Moodle activity the item is imported as, e.g. `page`, `resource`, `url`, `forum`, `quiz`, `assign` or `lti`. It is empty when the item has no known resource.
+	Type string
+	// Path is the path of the main file of the resource in the archive, if any.
+	Path string
+	// Content is the HTML of a summary or a label, and is empty for the other items.
+	Content string
+}
+
+// Sections returns the sections of a Moodle course, which are the top-level items of the organization, with their items in order. Items nested in other items follow them in their section.
+//
+// Moodle exports a label as a `webcontent` resource holding a single HTML page, which it names after the text of the label, shortened with an ellipsis, or as an item with neither resource nor children, whose title is the text. The summary of a section is either the page of the resource of the section item itself, or the label or page placed first in the section which is named after the section, has no title, or whose page is named `summary*.html`.
+func Sections(cc commoncartridge.IMSCC) ([]Section, error) {
+	sections := make([]Section, 0)
+
+	manifest, err := cc.Manifest()
+	if err != nil {
+		return sections, err
+	}
+
+	resources := make(map[string]types.Resource)
+	for _, r := range manifest.Resources.Resource {
+		resources[r.Identifier] = r
+	}
+
+	for i, top := range manifest.Organizations.Organization.Item.Item {
+		section := Section{
+			Identifier: top.Identifier,
+			Title:      strings.TrimSpace(top.Title),
+			Position:   i + 1,
+			Items:      make([]Item, 0),
+		}
+
+		if r, ok := resources[top.Identifierref]; ok && isPage(r) {
+			content, err := cc.ReadFile(commoncartridge.ResourceHref(r))
+			if err != nil {
+				return sections, err
+			}
+			section.Summary = string(content)
+		}
+
+		children, err := sectionItems(cc, top.Item, resources)
+		if err != nil {
+			return sections, err
+		}
+
+		for _, item := range children {
+			if len(section.Items) == 0 && section.Summary == "" && (item.Type == LabelType || item.Type == "page") && isSummary(item, section.Title) {
+				if item.Type == "page" {
+					content, err := cc.ReadFile(item.Path)
+					if err != nil {
+						return sections, err
+					}
+					item.Content = string(content)
+				}
+				item.Type = SummaryType
+				section.Summary = item.Content
+			}
+			item.Position = len(section.Items) + 1
+			section.Items = append(section.Items, item)
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections, nil
+}
+
+// Labels returns the labels of all the sections of a Moodle course, in order, leaving out the summaries of the sections.
+func Labels(cc commoncartridge.IMSCC) ([]Item, error) {
+	labels := make([]Item, 0)
+
+	sections, err := Sections(cc)
+	if err != nil {
+		return labels, err
+	}
+
+	for _, s := range sections {
+		for _, item := range s.Items {
+			if item.Type == LabelType {
+				labels = append(labels, item)
+			}
+		}
+	}
+
+	return labels, nil
+}
+
+// sectionItems returns the given items of a section and the items nested in them, depth first.
+func sectionItems(cc commoncartridge.IMSCC, items []types.Item, resources map[string]types.Resource) ([]Item, error) {
+	all := make([]Item, 0)
+
+	for _, i := range items {
+		item := Item{Identifier: i.Identifier, Identifierref: i.Identifierref, Title: strings.TrimSpace(i.Title)}
+
+		if r, ok := resources[i.Identifierref]; ok {
+			item.Type, item.Path = activityType(r), commoncartridge.ResourceHref(r)
+
+			if isPage(r) {
+				content, err := cc.ReadFile(item.Path)
+				if err != nil {
+					return all, err
+				}
+				if isLabel(item, string(content)) {
+					item.Type, item.Content = LabelType, string(content)
+				}
+			}
+		} else if i.Identifierref == "" && len(i.Item) == 0 && item.Title != "" {
+			item.Type, item.Content = LabelType, html.EscapeString(item.Title)
+		}
+
+		all = append(all, item)
+
+		nested, err := sectionItems(cc, i.Item, resources)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, nested...)
+	}
+
+	return all, nil
+}
+
+// isLabel returns true when the page of an item is a label, which Moodle names after its text.
+func isLabel(item Item, content string) bool {
+	if strings.HasPrefix(strings.ToLower(path.Base(item.Path)), LabelType) {
+		return true
+	}
+
+	text := strings.Join(strings.Fields(commoncartridge.PlainText(content)), " ")
+	title := strings.Join(strings.Fields(item.Title), " ")
+	if title == "" || title == text {
+		return true
+	}
+
+	for _, ellipsis := range []string{"...", "…"} {
+		if shortened := strings.TrimSpace(strings.TrimSuffix(title, ellipsis)); shortened != title && strings.HasPrefix(text, shortened) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSummary returns true when the label or page placed first in a section is the summary of the section.
+func isSummary(item Item, section string) bool {
+	return item.Title == "" || item.Title == section || strings.HasPrefix(strings.ToLower(path.Base(item.Path)), SummaryType)
+}
+
+// isPage returns true when the resource is a single HTML page.
+func isPage(r types.Resource) bool {
+	ext := strings.ToLower(path.Ext(commoncartridge.ResourceHref(r)))
+	return r.Type == "webcontent" && len(r.File) <= 1 && (ext == ".html" || ext == ".htm")
+}
+
+// activityType returns the name of the Moodle activity that a resource is imported as, or an empty string when it is unknown.
+func activityType(r types.Resource) string {
+	switch {
+	case r.Type == "webcontent" && isPage(r):
+		return "page"
+	case r.Type == "webcontent", strings.HasSuffix(r.Type, "learning-application-resource"):
+		return "resource"
+	case strings.HasPrefix(r.Type, "imswl_"):
+		return "url"
+	case strings.HasPrefix(r.Type, "imsdt_"):
+		return "forum"
+	case strings.HasPrefix(r.Type, "imsqti_") && strings.HasSuffix(r.Type, "/assessment"):
+		return "quiz"
+	case strings.HasPrefix(r.Type, "imsbasiclti_"):
+		return "lti"
+	case strings.HasPrefix(r.Type, "assignment_"):
+		return "assign"
+	}
+
+	return ""
+}
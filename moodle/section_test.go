@@ -0,0 +1,75 @@
+package moodle
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge"
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSections(t *testing.T) {
+	cc := load(t, testcc.Archive(t, moodleFiles))
+	sections, err := Sections(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(sections), 2)
+	assert.Equal(t, sections[0].Title, "General")
+	assert.Equal(t, sections[0].Summary, "<p>Welcome to the course</p>")
+	require.Equal(t, len(sections[0].Items), 1)
+	assert.Equal(t, sections[0].Items[0], Item{Identifier: "I_1", Identifierref: "R_1", Title: "Announcements", Position: 1, Type: "forum", Path: "R_1/forum.xml"})
+
+	s := sections[1]
+	assert.Equal(t, s.Position, 2)
+	assert.Equal(t, s.Summary, "<p>In this topic, we read <em>Hamlet</em>.</p>")
+	require.Equal(t, len(s.Items), 5)
+	assert.Equal(t, s.Items[0].Type, SummaryType)
+
+	//-- labels are named after their text, shortened
+	assert.Equal(t, s.Items[1].Type, LabelType)
+	assert.Contains(t, s.Items[1].Content, "Read the following chapters before the first class.")
+
+	//-- a page starting with its title is not a label
+	assert.Equal(t, s.Items[2].Type, "page")
+	assert.Equal(t, s.Items[2].Content, "")
+
+	assert.Equal(t, s.Items[3], Item{Identifier: "I_5", Title: "Optional readings & videos", Position: 4, Type: LabelType, Content: "Optional readings &amp; videos"})
+	assert.Equal(t, s.Items[4].Type, "quiz")
+	assert.Equal(t, s.Items[4].Position, 5)
+}
+
+func TestLabels(t *testing.T) {
+	cc := load(t, testcc.Archive(t, moodleFiles))
+	labels, err := Labels(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(labels), 2)
+	assert.Equal(t, labels[0].Identifier, "I_3")
+	assert.Equal(t, labels[1].Identifier, "I_5")
+
+	cc = load(t, filepath.Join(testcc.AllTestFilesDir, "py4e_export.imscc"))
+	labels, err = Labels(cc)
+	require.Nil(t, err)
+	assert.Empty(t, labels)
+}
+
+func TestQuestionCategories(t *testing.T) {
+	cc := load(t, testcc.Archive(t, moodleFiles))
+	categories, err := QuestionCategories(cc)
+	require.Nil(t, err)
+
+	require.Equal(t, len(categories), 1)
+	c := categories[0]
+	assert.Equal(t, c.Identifier, "R_QB")
+	assert.Equal(t, c.Title, "Default for Literature")
+	assert.Equal(t, c.Path, "QB/questions.xml")
+	require.Equal(t, len(c.Questions), 1)
+	assert.Equal(t, c.Questions[0].Type, commoncartridge.Essay)
+
+	require.Equal(t, len(c.Categories), 1)
+	assert.Equal(t, c.Categories[0].Title, "Week 1")
+	require.Equal(t, len(c.Categories[0].Questions), 1)
+	assert.Equal(t, c.Categories[0].Questions[0].Title, "Setting")
+}
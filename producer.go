@@ -0,0 +1,312 @@
+package commoncartridge
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// The learning management systems and generators whose cartridges Producer recognizes.
+const (
+	CanvasProducer     = "canvas"
+	MoodleProducer     = "moodle"
+	BlackboardProducer = "blackboard"
+	D2LProducer        = "d2l"
+	SakaiProducer      = "sakai"
+	// TsugiProducer is the Common Cartridge export of Tsugi, which produces the cartridges of py4e.com.
+	TsugiProducer = "tsugi"
+)
+
+// producers are the known producers, in the order in which a tie between them is broken.
+var producers = []string{CanvasProducer, MoodleProducer, BlackboardProducer, D2LProducer, SakaiProducer, TsugiProducer}
+
+// The kinds of vendor extensions.
+const (
+	NamespaceExtension    = "namespace"
+	FileExtension         = "file"
+	ResourceTypeExtension = "resource_type"
+	TokenExtension        = "token"
+)
+
+// Producer is the learning management system or generator which most likely exported a cartridge, with the clues pointing to it and the vendor extensions found in the cartridge.
+type Producer struct {
+	// Name is one of CanvasProducer, MoodleProducer, BlackboardProducer, D2LProducer, SakaiProducer or TsugiProducer, and is empty when the producer cannot be told.
+	Name string
+	// Evidence are the clues pointing to the producer, e.g. `file course_settings/canvas_export.txt`.
+	Evidence []string
+	// Extensions are the parts of the cartridge which the IMSCC standard does not define, in the order they are first found.
+	Extensions []VendorExtension
+}
+
+// VendorExtension is a namespace, file, resource type or link token that a producer adds on top of the IMSCC standard.
+type VendorExtension struct {
+	// Producer is the producer which uses the extension, and is empty when it is unknown.
+	Producer string
+	// Kind is one of NamespaceExtension, FileExtension, ResourceTypeExtension or TokenExtension.
+	Kind string
+	// Name is the namespace URI, the path of the file, the resource type or the token. The files of a vendor folder, such as `non_cc_assessments/`, and those exported next to each resource, such as `*/assessment_meta.xml`, are listed once.
+	Name string
+	// Count is the number of files, or of resources, in which the extension is found.
+	Count int
+	// Supported is true when this package, or one of the registered extensions, decodes the extension.
+	Supported bool
+}
+
+// VendorSupport is implemented by the extensions which can tell the vendor extensions of their learning management system that they decode. When an extension does not implement it, all the vendor extensions of the producer named after it are supported.
+type VendorSupport interface {
+	// Supports returns true when the extension decodes the given vendor extension.
+	Supports(v VendorExtension) bool
+}
+
+// supportedExtensions are the vendor extensions that this package decodes itself, by kind and name.
+var supportedExtensions = map[string]bool{
+	FileExtension + " " + nonCCAssessmentsDir + "/":       true,
+	FileExtension + " " + "course_settings/syllabus.html": true,
+}
+
+var (
+	// standardNamespaces are the hosts of the namespaces of the IMSCC standard and of the specifications it builds on.
+	standardNamespaces = []string{"imsglobal.org", "ltsc.ieee.org", "w3.org", "purl.org"}
+	// vendorNamespaces are the producers of the known vendor namespaces, by a part of their URI.
+	vendorNamespaces = map[string]string{
+		"canvas.instructure.com": CanvasProducer,
+		"moodle":                 MoodleProducer,
+		"blackboard.com":         BlackboardProducer,
+		"desire2learn.com":       D2LProducer,
+		"sakaiproject.org":       SakaiProducer,
+	}
+	// vendorResourceTypes are the producers of the known vendor resource types, by a part of the type.
+	vendorResourceTypes = map[string]string{
+		"x-bb-":  BlackboardProducer,
+		"d2l":    D2LProducer,
+		"moodle": MoodleProducer,
+		"sakai":  SakaiProducer,
+	}
+	// generatorNames are the names under which the producers sign the comments and contributors of a manifest.
+	generatorNames = map[string]*regexp.Regexp{
+		CanvasProducer:     regexp.MustCompile(`(?i)\binstructure\b|\bcanvas lms\b`),
+		MoodleProducer:     regexp.MustCompile(`(?i)\bmoodle\b`),
+		BlackboardProducer: regexp.MustCompile(`(?i)\bblackboard\b`),
+		D2LProducer:        regexp.MustCompile(`(?i)\bdesire2learn\b|\bbrightspace\b|\bd2l\b`),
+		SakaiProducer:      regexp.MustCompile(`(?i)\bsakai\b`),
+		TsugiProducer:      regexp.MustCompile(`(?i)\btsugi\b`),
+	}
+	// tokens are the link tokens of the producers, besides the `$IMS-CC-FILEBASE$` of the standard.
+	tokens = map[string]*regexp.Regexp{
+		CanvasProducer:     regexp.MustCompile(`\$(?:WIKI_REFERENCE|CANVAS_OBJECT_REFERENCE|CANVAS_COURSE_REFERENCE)\$`),
+		BlackboardProducer: regexp.MustCompile(`@X@[\w.]+@X@`),
+		D2LProducer:        regexp.MustCompile(`(?i)\{orgUnitId\}`),
+	}
+
+	standardResourceType = regexp.MustCompile(`^(webcontent|imsdt_xmlv1p\d|imswl_xmlv1p\d|imsbasiclti_xmlv1p\d|imsqti_xmlv1p2/imscc_xmlv1p\d/(assessment|question-bank)|associatedcontent/imscc_xmlv1p\d/learning-application-resource|assignment_xmlv1p\d|imsapip_zipv1p0|imsiwb_iwbv1p0)$`)
+	namespacePattern     = regexp.MustCompile(`xmlns(?::[\w.-]+)?\s*=\s*"([^"]+)"`)
+	generatorPattern     = regexp.MustCompile(`(?s)<!--(.*?)-->|<(?:\w+:)?entity>(.*?)</(?:\w+:)?entity>`)
+)
+
+// Producer fingerprints the learning management system or generator which exported the cartridge, from the namespaces of its XML files, the layout of its folders (e.g. the `course_settings/canvas_export.txt` of Canvas, or the `csfiles` of Blackboard), its resource types, the tokens of its links and the generator named in its manifest. The producer is the one with the most clues, and the Name is empty when there are none.
+func (cc IMSCC) Producer() (Producer, error) {
+	return cc.fingerprint(true)
+}
+
+// ManifestProducer fingerprints the producer of the cartridge like Producer, but only reads its manifest: the other files count by their names, but their namespaces and tokens are left out. It is cheap enough for the Detect method of an Extension, which runs on every call to Metadata or Items.
+func (cc IMSCC) ManifestProducer() (Producer, error) {
+	return cc.fingerprint(false)
+}
+
+// fingerprint looks for the clues of the producer in the namespaces and tokens of the manifest, and in those of all the other XML and HTML files when deep is true.
+func (cc IMSCC) fingerprint(deep bool) (Producer, error) {
+	producer := Producer{Evidence: make([]string, 0), Extensions: make([]VendorExtension, 0)}
+
+	manifest, manifestFile := "", cc.manifestFile()
+	index := make(map[string]int)
+	add := func(p, kind, name string) {
+		key := kind + " " + name
+		if i, ok := index[key]; ok {
+			producer.Extensions[i].Count++
+			return
+		}
+		index[key] = len(producer.Extensions)
+		producer.Extensions = append(producer.Extensions, VendorExtension{Producer: p, Kind: kind, Name: name, Count: 1})
+	}
+
+	for _, r := range cc.manifest.Resources.Resource {
+		if r.Type != "" && !standardResourceType.MatchString(r.Type) {
+			add(vendorResourceType(r.Type), ResourceTypeExtension, r.Type)
+		}
+	}
+
+	for _, f := range cc.Reader.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		if p, name := vendorFile(f.Name); name != "" {
+			add(p, FileExtension, name)
+		}
+
+		switch strings.ToLower(path.Ext(f.Name)) {
+		case ".xml", ".html", ".htm", ".qti", ".dat":
+		default:
+			continue
+		}
+		if !deep && f != manifestFile {
+			continue
+		}
+
+		file, err := f.Open()
+		if err != nil {
+			return producer, err
+		}
+		bytesArray, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return producer, err
+		}
+		content := string(bytesArray)
+		if f == manifestFile {
+			manifest = content
+		}
+
+		found := make(map[string]bool)
+		for _, m := range namespacePattern.FindAllStringSubmatch(content, -1) {
+			if ns := strings.TrimSpace(m[1]); !found[ns] && !isStandardNamespace(ns) {
+				found[ns] = true
+				add(vendorNamespace(ns), NamespaceExtension, ns)
+			}
+		}
+		for _, p := range producers {
+			if tokens[p] == nil {
+				continue
+			}
+			for _, token := range tokens[p].FindAllString(content, -1) {
+				if !found[token] {
+					found[token] = true
+					add(p, TokenExtension, token)
+				}
+			}
+		}
+	}
+
+	scores := make(map[string]int)
+	evidence := make(map[string][]string)
+	for i, v := range producer.Extensions {
+		producer.Extensions[i].Supported = supportsExtension(v)
+		if v.Producer != "" {
+			scores[v.Producer]++
+			evidence[v.Producer] = append(evidence[v.Producer], v.Kind+" "+v.Name)
+		}
+	}
+
+	for _, m := range generatorPattern.FindAllStringSubmatch(manifest, -1) {
+		text := strings.TrimSpace(m[1] + m[2])
+		for _, p := range producers {
+			if generatorNames[p].MatchString(text) {
+				scores[p]++
+				evidence[p] = append(evidence[p], "generator "+text)
+			}
+		}
+	}
+
+	//-- Tsugi starts its manifests from the same template, which keeps the identifiers of the IMS test cartridge
+	if cc.manifest.Identifier == "cctd0015" && cc.manifest.Organizations.Organization.Identifier == "T_1000" {
+		scores[TsugiProducer]++
+		evidence[TsugiProducer] = append(evidence[TsugiProducer], fmt.Sprintf("manifest %s, organization %s", cc.manifest.Identifier, cc.manifest.Organizations.Organization.Identifier))
+	}
+
+	for _, p := range producers {
+		if scores[p] > 0 && scores[p] > scores[producer.Name] {
+			producer.Name = p
+		}
+	}
+	if producer.Name != "" {
+		producer.Evidence = evidence[producer.Name]
+	}
+
+	return producer, nil
+}
+
+// vendorFile returns the producer and the name under which a file of the archive is listed as a vendor extension, or an empty name when it is not one.
+func vendorFile(name string) (string, string) {
+	dir, base := path.Dir(name), path.Base(name)
+	switch {
+	case strings.HasPrefix(name, "course_settings/"):
+		return CanvasProducer, name
+	case strings.HasPrefix(name, nonCCAssessmentsDir+"/"):
+		return CanvasProducer, nonCCAssessmentsDir + "/"
+	case strings.HasPrefix(name, "wiki_content/"):
+		return CanvasProducer, "wiki_content/"
+	case dir != "." && !strings.Contains(dir, "/") && (base == "assessment_meta.xml" || base == "assignment_settings.xml"):
+		return CanvasProducer, "*/" + base
+	case strings.HasPrefix(name, "csfiles/home_dir/"):
+		return BlackboardProducer, "csfiles/home_dir/"
+	case strings.HasPrefix(name, "csfiles/"):
+		return BlackboardProducer, "csfiles/"
+	case base == "questiondb.xml":
+		return D2LProducer, name
+	case strings.HasPrefix(base, "quiz_d2l_"):
+		return D2LProducer, "quiz_d2l_*.xml"
+	case base == "moodle_backup.xml":
+		return MoodleProducer, name
+	}
+
+	return "", ""
+}
+
+// isStandardNamespace returns true when the namespace belongs to the IMSCC standard, or to one of the specifications it builds on.
+func isStandardNamespace(ns string) bool {
+	for _, host := range standardNamespaces {
+		if strings.Contains(ns, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// vendorNamespace returns the producer of a namespace, or an empty string when it is unknown.
+func vendorNamespace(ns string) string {
+	for part, p := range vendorNamespaces {
+		if strings.Contains(strings.ToLower(ns), part) {
+			return p
+		}
+	}
+
+	return ""
+}
+
+// vendorResourceType returns the producer of a resource type, or an empty string when it is unknown.
+func vendorResourceType(t string) string {
+	for part, p := range vendorResourceTypes {
+		if strings.Contains(strings.ToLower(t), part) {
+			return p
+		}
+	}
+
+	return ""
+}
+
+// supportsExtension returns true when this package, or one of the registered extensions named after the producer of the vendor extension, decodes it.
+func supportsExtension(v VendorExtension) bool {
+	if supportedExtensions[v.Kind+" "+v.Name] {
+		return true
+	}
+	if v.Producer == "" {
+		return false
+	}
+
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+
+	for _, e := range extensions {
+		if e.Name() != v.Producer {
+			continue
+		}
+		if s, ok := e.(VendorSupport); ok {
+			return s.Supports(v)
+		}
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,149 @@
+package commoncartridge
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const producerManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- %s -->
+<manifest identifier="m1" xmlns="http://www.imsglobal.org/xsd/imsccv1p1/imscp_v1p1" %s>
+  <metadata><schema>IMS Common Cartridge</schema><schemaversion>1.1.0</schemaversion></metadata>
+  <organizations><organization identifier="o1" structure="rooted-hierarchy"><item identifier="root"/></organization></organizations>
+  <resources>%s</resources>
+</manifest>`
+
+func TestProducer(t *testing.T) {
+	cc := load(t, singleTestFile).(IMSCC)
+	producer, err := cc.Producer()
+	require.Nil(t, err)
+
+	assert.Equal(t, producer.Name, CanvasProducer)
+	assert.Contains(t, producer.Evidence, "file course_settings/canvas_export.txt")
+	assert.Contains(t, producer.Evidence, "namespace http://canvas.instructure.com/xsd/cccv1p0")
+
+	extensions := make(map[string]VendorExtension)
+	for _, v := range producer.Extensions {
+		extensions[v.Kind+" "+v.Name] = v
+	}
+	assert.Equal(t, extensions["file non_cc_assessments/"].Count, 33)
+	//-- the package decodes the question banks and the syllabus itself, and the rest is left to the canvas extension, which is not registered here
+	assert.True(t, extensions["file non_cc_assessments/"].Supported)
+	assert.True(t, extensions["file course_settings/syllabus.html"].Supported)
+	assert.False(t, extensions["file course_settings/module_meta.xml"].Supported)
+	assert.Equal(t, extensions["file */assessment_meta.xml"].Producer, CanvasProducer)
+	for key := range extensions {
+		assert.NotContains(t, key, "imsglobal.org")
+	}
+
+	cc = load(t, filepath.Join(allTestFilesDir, "py4e_export.imscc")).(IMSCC)
+	producer, err = cc.Producer()
+	require.Nil(t, err)
+	assert.Equal(t, producer.Name, TsugiProducer)
+	assert.Empty(t, producer.Extensions)
+
+	cc = load(t, filepath.Join(allTestFilesDir, "offline_content_module_live80.v1321.imscc")).(IMSCC)
+	producer, err = cc.Producer()
+	require.Nil(t, err)
+	assert.Equal(t, producer.Name, "")
+	assert.Empty(t, producer.Evidence)
+}
+
+func TestProducerVendors(t *testing.T) {
+	cc := archive(t, map[string]string{
+		"imsmanifest.xml": fmt.Sprintf(producerManifest, "", `xmlns:bb="http://www.blackboard.com/content-packaging/"`,
+			`<resource identifier="r1" type="resource/x-bb-document" href="res00001.dat"/>`),
+		"res00001.dat":                        `<CONTENT><BODY><TEXT>&lt;img src="@X@EmbeddedFile.requestUrlStub@X@bbcswebdav/xid-1_1"&gt;</TEXT></BODY></CONTENT>`,
+		"csfiles/home_dir/image__xid-1_1.png": "",
+	})
+	producer, err := cc.Producer()
+	require.Nil(t, err)
+	assert.Equal(t, producer.Name, BlackboardProducer)
+	assert.Equal(t, producer.Evidence, []string{
+		"resource_type resource/x-bb-document",
+		"file csfiles/home_dir/",
+		"namespace http://www.blackboard.com/content-packaging/",
+		"token @X@EmbeddedFile.requestUrlStub@X@",
+	})
+	for _, v := range producer.Extensions {
+		assert.False(t, v.Supported)
+	}
+
+	cc = archive(t, map[string]string{
+		"imsmanifest.xml": fmt.Sprintf(producerManifest, "", `xmlns:d2l_2p0="http://desire2learn.com/xsd/d2lcp_v2p0"`,
+			`<resource identifier="r1" type="webcontent" href="page.html" d2l_2p0:material_type="content"/>`),
+		"page.html":      `<a href="/d2l/common/viewFile.d2lfile/Database/{orgUnitId}/file.pdf">File</a>`,
+		"questiondb.xml": `<questestinterop/>`,
+	})
+	producer, err = cc.Producer()
+	require.Nil(t, err)
+	assert.Equal(t, producer.Name, D2LProducer)
+	assert.Equal(t, len(producer.Evidence), 3)
+
+	cc = archive(t, map[string]string{
+		"imsmanifest.xml": fmt.Sprintf(producerManifest, "Generated by Moodle 4.1", `xmlns:ext="http://example.com/extension"`, ""),
+	})
+	producer, err = cc.Producer()
+	require.Nil(t, err)
+	assert.Equal(t, producer.Name, MoodleProducer)
+	assert.Equal(t, producer.Evidence, []string{"generator Generated by Moodle 4.1"})
+	require.Equal(t, len(producer.Extensions), 1)
+	assert.Equal(t, producer.Extensions[0], VendorExtension{Kind: NamespaceExtension, Name: "http://example.com/extension", Count: 1})
+}
+
+func TestManifestProducer(t *testing.T) {
+	cc := archive(t, map[string]string{
+		"imsmanifest.xml": fmt.Sprintf(producerManifest, "", "",
+			`<resource identifier="r1" type="webcontent" href="page.html"/>`),
+		"page.html":                           `<img src="@X@EmbeddedFile.requestUrlStub@X@bbcswebdav/xid-1_1">`,
+		"csfiles/home_dir/image__xid-1_1.png": "",
+	})
+	producer, err := cc.ManifestProducer()
+	require.Nil(t, err)
+	assert.Equal(t, producer.Name, BlackboardProducer)
+	assert.Equal(t, producer.Evidence, []string{"file csfiles/home_dir/"}, "the tokens of the other files should be left out")
+
+	for _, name := range []string{"py4e_export.imscc", "sample-public-sandbox-course-export.imscc", "offline_content_module_live80.v1321.imscc"} {
+		cc = load(t, filepath.Join(allTestFilesDir, name)).(IMSCC)
+		producer, err = cc.Producer()
+		require.Nil(t, err)
+		quick, err := cc.ManifestProducer()
+		require.Nil(t, err)
+		assert.Equal(t, quick.Name, producer.Name, name)
+	}
+}
+
+func TestNestedManifestProducer(t *testing.T) {
+	cc := archive(t, map[string]string{
+		"course/imsmanifest.xml": fmt.Sprintf(producerManifest, "Generated by Moodle 4.1", "", ""),
+	})
+	assert.Equal(t, cc.ManifestPath(), "course/imsmanifest.xml")
+
+	producer, err := cc.ManifestProducer()
+	require.Nil(t, err)
+	assert.Equal(t, producer.Name, MoodleProducer)
+	assert.Equal(t, producer.Evidence, []string{"generator Generated by Moodle 4.1"})
+
+	producer, err = cc.Producer()
+	require.Nil(t, err)
+	assert.Equal(t, producer.Name, MoodleProducer)
+}
+
+func TestProducerSupport(t *testing.T) {
+	RegisterExtension(testExtension{})
+	defer func() { extensions = make([]Extension, 0) }()
+
+	assert.True(t, supportsExtension(VendorExtension{Producer: "test", Kind: FileExtension, Name: "test.xml"}))
+	assert.False(t, supportsExtension(VendorExtension{Producer: CanvasProducer, Kind: FileExtension, Name: "course_settings/module_meta.xml"}))
+	assert.False(t, supportsExtension(VendorExtension{Kind: NamespaceExtension, Name: "http://example.com/extension"}))
+}
+
+// archive returns a cartridge made of the given files.
+func archive(t *testing.T, files map[string]string) IMSCC {
+	return load(t, testcc.Archive(t, files)).(IMSCC)
+}
@@ -0,0 +1,254 @@
+package commoncartridge
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// nonCCAssessmentsDir is the folder in which Canvas exports the full definitions of its quizzes and question banks.
+const nonCCAssessmentsDir = "non_cc_assessments"
+
+// QuestionBank is a Canvas question bank, or the full definition of a Canvas quiz, as found in the `non_cc_assessments` folder of a Canvas export.
+type QuestionBank struct {
+	Identifier string
+	// Title is the `bank_title` of a question bank, or the title of a quiz.
+	Title string
+	// IsQuiz is true when the file holds the definition of a quiz rather than a question bank.
+	IsQuiz bool
+	// Path is the path of the file in the cartridge.
+	Path      string
+	Questions []BankQuestion
+}
+
+// BankQuestion is a question of a QuestionBank, along with its Canvas metadata. Its Choices and Answers are the ones of its response when it expects a single one, while questions expecting several responses (e.g. matching) only describe them in Responses.
+type BankQuestion struct {
+	Question
+	// CanvasType is the `question_type` of the question, e.g. `numerical_question`.
+	CanvasType string
+	// PointsPossible is the number of points of the question.
+	PointsPossible float64
+	// AssessmentQuestion is the identifier of the bank question that a quiz question was copied from, if any.
+	AssessmentQuestion string
+	Responses          []BankResponse
+	// Formula is the formula computing the answer of a calculated question, with the Tolerance allowed on the answer.
+	Formula   string
+	Tolerance string
+}
+
+// BankResponse is one of the responses expected by a question: the single response of most questions, or one per blank, dropdown or left-hand item of a matching question.
+type BankResponse struct {
+	Identifier string
+	// Label is the name of the blank or dropdown, or the left-hand side of a matching pair.
+	Label   string
+	Choices []Choice
+	// Answers are the identifiers of the correct choices, or the accepted texts and numbers.
+	Answers []string
+	// Ranges are the ranges of accepted values of a numerical question.
+	Ranges []NumericRange
+}
+
+// NumericRange is a range of accepted values, including its bounds.
+type NumericRange struct {
+	Min float64
+	Max float64
+}
+
+// QuestionBanks returns all the question banks and quiz definitions found in the `non_cc_assessments` folder of a Canvas export. Those files are not resources of type `imsqti_xmlv1p2`, and can hold question types which the CC profile cannot carry, such as numerical, calculated, matching and multiple dropdowns questions. It returns an error when one of those files is not a QTI document.
+func (cc IMSCC) QuestionBanks() ([]QuestionBank, error) {
+	banks := make([]QuestionBank, 0)
+
+	for _, f := range cc.Reader.File {
+		if path.Dir(f.Name) != nonCCAssessmentsDir || !strings.HasSuffix(f.Name, ".xml.qti") {
+			continue
+		}
+
+		file, err := f.Open()
+		if err != nil {
+			return banks, err
+		}
+
+		bytesArray, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return banks, err
+		}
+
+		var qti types.CanvasQuestestinterop
+		if err := xml.Unmarshal(bytesArray, &qti); err != nil {
+			return banks, fmt.Errorf("could not read the question bank %s: %w", f.Name, err)
+		}
+
+		bank := NewQuestionBank(qti)
+		bank.Path = f.Name
+		banks = append(banks, bank)
+	}
+
+	return banks, nil
+}
+
+// NewQuestionBank builds a QuestionBank from a decoded Canvas QTI document.
+func NewQuestionBank(qti types.CanvasQuestestinterop) QuestionBank {
+	bank := QuestionBank{
+		Identifier: qti.Objectbank.Ident,
+		Title:      qti.Objectbank.Qtimetadata.Field("bank_title"),
+		Questions:  make([]BankQuestion, 0),
+	}
+
+	if qti.Assessment.Ident != "" {
+		bank.Identifier = qti.Assessment.Ident
+		bank.Title = qti.Assessment.Title
+		bank.IsQuiz = true
+	}
+
+	for _, item := range qti.Items() {
+		bank.Questions = append(bank.Questions, NewBankQuestion(item))
+	}
+
+	return bank
+}
+
+// NewBankQuestion builds a BankQuestion from a Canvas item. Its type is derived from its `question_type`, and its correct answers from the conditions which give it a positive score.
+func NewBankQuestion(item types.CanvasItem) BankQuestion {
+	meta := item.Itemmetadata.Qtimetadata
+	q := BankQuestion{
+		Question:           NewQuestion(canvasQTIItem(item)),
+		CanvasType:         meta.Field("question_type"),
+		AssessmentQuestion: meta.Field("assessment_question_identifierref"),
+		Responses:          bankResponses(item),
+	}
+
+	q.Type = canvasQuestionType(q.CanvasType)
+	if points, err := strconv.ParseFloat(meta.Field("points_possible"), 64); err == nil {
+		q.PointsPossible = points
+		q.Points = points
+	}
+
+	q.Answers = make([]string, 0)
+	if len(q.Responses) == 1 {
+		q.Choices = q.Responses[0].Choices
+		q.Answers = q.Responses[0].Answers
+	}
+
+	calculated := item.ItemprocExtension.Calculated
+	if len(calculated.Formulas.Formula) > 0 {
+		q.Formula = strings.TrimSpace(calculated.Formulas.Formula[0])
+		q.Tolerance = strings.TrimSpace(calculated.AnswerTolerance)
+	}
+
+	return q
+}
+
+// canvasQuestionType returns the type corresponding to the `question_type` of a Canvas item, e.g. `multiple_answers_question`.
+func canvasQuestionType(canvasType string) QuestionType {
+	t := QuestionType(strings.TrimSuffix(canvasType, "_question"))
+	switch t {
+	case "multiple_answers":
+		return MultipleResponse
+	case "short_answer":
+		return FillInTheBlank
+	case MultipleChoice, TrueFalse, Essay, Matching, MultipleDropdowns, FillInMultipleBlanks, Numerical, Calculated, FileUpload, TextOnly:
+		return t
+	}
+
+	return UnknownQuestion
+}
+
+// canvasQTIItem copies a Canvas item to a QTIItem, in order to extract its text and feedback like the ones of CC-profile items. The responses are only copied when the item expects a single one.
+func canvasQTIItem(item types.CanvasItem) types.QTIItem {
+	var qi types.QTIItem
+	qi.Ident = item.Ident
+	qi.Title = item.Title
+	qi.Itemmetadata.Qtimetadata = item.Itemmetadata.Qtimetadata
+	qi.Presentation.Material = item.Presentation.Material
+	qi.Resprocessing.Outcomes = item.Resprocessing.Outcomes
+	qi.Resprocessing.Respcondition = item.Resprocessing.Respcondition
+	qi.Itemfeedback = item.Itemfeedback
+
+	if lids := item.Presentation.ResponseLid; len(lids) == 1 {
+		qi.Presentation.ResponseLid.Ident = lids[0].Ident
+		qi.Presentation.ResponseLid.Rcardinality = lids[0].Rcardinality
+		qi.Presentation.ResponseLid.RenderChoice.ResponseLabel = lids[0].RenderChoice.ResponseLabel
+	}
+
+	return qi
+}
+
+// bankResponses returns the responses of an item, with the answers tested by its scoring conditions.
+func bankResponses(item types.CanvasItem) []BankResponse {
+	responses := make([]BankResponse, 0)
+	index := make(map[string]int)
+
+	for _, r := range append(item.Presentation.ResponseLid, item.Presentation.ResponseStr...) {
+		response := BankResponse{
+			Identifier: r.Ident,
+			Label:      strings.TrimSpace(r.Material.Mattext.Text),
+			Choices:    make([]Choice, 0),
+			Answers:    make([]string, 0),
+			Ranges:     make([]NumericRange, 0),
+		}
+
+		for _, label := range r.RenderChoice.ResponseLabel {
+			response.Choices = append(response.Choices, Choice{Identifier: label.Ident, Text: label.Material.Mattext.Text})
+		}
+
+		index[r.Ident] = len(responses)
+		responses = append(responses, response)
+	}
+
+	for _, rc := range item.Resprocessing.Respcondition {
+		if isScoring(rc) {
+			collectAnswers(rc.Conditionvar, responses, index)
+		}
+	}
+
+	for i := range responses {
+		for j, c := range responses[i].Choices {
+			for _, a := range responses[i].Answers {
+				if a == c.Identifier {
+					responses[i].Choices[j].Correct = true
+				}
+			}
+		}
+	}
+
+	return responses
+}
+
+// collectAnswers adds the values and ranges tested by a condition to the responses they refer to, going through its alternatives.
+func collectAnswers(cond types.Conditionvar, responses []BankResponse, index map[string]int) {
+	tests := append(append(append([]types.Vartest{}, cond.Varequal...), cond.Varsubstring...), cond.And.Varequal...)
+	for _, v := range tests {
+		if i, ok := index[v.Respident]; ok {
+			responses[i].Answers = append(responses[i].Answers, strings.TrimSpace(v.Text))
+		}
+	}
+
+	addRanges(cond.Vargte, cond.Varlte, responses, index)
+	addRanges(cond.And.Vargte, cond.And.Varlte, responses, index)
+
+	for _, or := range cond.Or {
+		collectAnswers(or, responses, index)
+	}
+}
+
+// addRanges pairs the lower and upper bounds tested on the same response.
+func addRanges(lower []types.Vartest, upper []types.Vartest, responses []BankResponse, index map[string]int) {
+	for n := 0; n < len(lower) && n < len(upper); n++ {
+		i, ok := index[lower[n].Respident]
+		if !ok {
+			continue
+		}
+
+		min, errMin := strconv.ParseFloat(strings.TrimSpace(lower[n].Text), 64)
+		max, errMax := strconv.ParseFloat(strings.TrimSpace(upper[n].Text), 64)
+		if errMin == nil && errMax == nil {
+			responses[i].Ranges = append(responses[i].Ranges, NumericRange{Min: min, Max: max})
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package commoncartridge
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/internal/testcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuestionBanks(t *testing.T) {
+	cc := load(t, singleTestFile)
+	banks, err := cc.QuestionBanks()
+	require.Nil(t, err)
+	assert.Equal(t, len(banks), 33)
+
+	var unfiled QuestionBank
+	quizzes := 0
+	for _, b := range banks {
+		if b.IsQuiz {
+			quizzes++
+		}
+		if b.Identifier == "i23f63238b960bcbe4c779dbd77f559c2" {
+			unfiled = b
+		}
+	}
+	assert.Equal(t, quizzes, 17)
+
+	assert.False(t, unfiled.IsQuiz)
+	assert.Equal(t, unfiled.Title, "Unfiled Questions")
+	assert.Equal(t, unfiled.Path, "non_cc_assessments/i23f63238b960bcbe4c779dbd77f559c2.xml.qti")
+	require.NotEmpty(t, unfiled.Questions)
+
+	fitb := unfiled.Questions[0]
+	assert.Equal(t, fitb.CanvasType, "short_answer_question")
+	assert.Equal(t, fitb.Type, FillInTheBlank)
+	assert.Equal(t, fitb.PointsPossible, 1.0)
+	assert.Equal(t, fitb.Answers, []string{"FITB", "fitb"})
+
+	var calculated BankQuestion
+	for _, b := range banks {
+		for _, q := range b.Questions {
+			if q.Type == Calculated {
+				calculated = q
+			}
+		}
+	}
+	assert.Equal(t, calculated.CanvasType, "calculated_question")
+	assert.Equal(t, calculated.Formula, "6 + x")
+	assert.Equal(t, calculated.Tolerance, "0")
+}
+
+func TestMalformedQuestionBank(t *testing.T) {
+	for _, content := range []string{`<questestinterop><objectbank`, `<quiz/>`} {
+		p := testcc.Rewrite(t, singleTestFile, map[string]string{
+			"non_cc_assessments/iad7e264143b9f2ec9dbc71a9d166f6f2.xml.qti": content,
+		})
+
+		_, err := load(t, p).QuestionBanks()
+		assert.NotNil(t, err, content)
+	}
+}
+
+func TestBankQuestionTypes(t *testing.T) {
+	cc := load(t, filepath.Join(allTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	banks, err := cc.QuestionBanks()
+	require.Nil(t, err)
+	assert.Equal(t, len(banks), 24)
+
+	questions := make(map[QuestionType]BankQuestion)
+	for _, b := range banks {
+		for _, q := range b.Questions {
+			questions[q.Type] = q
+		}
+	}
+
+	assert.NotContains(t, questions, UnknownQuestion)
+
+	numerical := questions[Numerical]
+	require.Equal(t, len(numerical.Responses), 1)
+	assert.NotEmpty(t, numerical.Responses[0].Ranges)
+
+	matching := questions[Matching]
+	assert.Greater(t, len(matching.Responses), 1)
+	assert.NotEmpty(t, matching.Responses[0].Label)
+	assert.Equal(t, len(matching.Responses[0].Answers), 1)
+	assert.Empty(t, matching.Choices)
+
+	assert.Greater(t, len(questions[MultipleDropdowns].Responses), 1)
+}
@@ -0,0 +1,398 @@
+package commoncartridge
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// QuestionType is the kind of a question, as described by the `cc_profile` metadata field of a QTI item.
+type QuestionType string
+
+const (
+	MultipleChoice   QuestionType = "multiple_choice"
+	MultipleResponse QuestionType = "multiple_response"
+	TrueFalse        QuestionType = "true_false"
+	FillInTheBlank   QuestionType = "fib"
+	Essay            QuestionType = "essay"
+	PatternMatch     QuestionType = "pattern_match"
+	UnknownQuestion  QuestionType = "unknown"
+
+	// The following types can only be found in the question banks of Canvas exports, since the CC profile cannot carry them.
+	Matching             QuestionType = "matching"
+	MultipleDropdowns    QuestionType = "multiple_dropdowns"
+	FillInMultipleBlanks QuestionType = "fill_in_multiple_blanks"
+	Numerical            QuestionType = "numerical"
+	Calculated           QuestionType = "calculated"
+	FileUpload           QuestionType = "file_upload"
+	TextOnly             QuestionType = "text_only"
+)
+
+// Quiz is a user-friendly representation of a QTI assessment, independent from the QTI 1.2 XML structure.
+type Quiz struct {
+	Identifier string
+	Title      string
+	Settings   QuizSettings
+	// Questions are all the questions of the quiz, regardless of the section they belong to.
+	Questions []Question
+	Sections  []QuizSection
+}
+
+// QuizSection is a group of questions of a Quiz, along with the rules to select and order them.
+type QuizSection struct {
+	Identifier string
+	Title      string
+	// Pick is the number of questions drawn at random from the section, 0 meaning all of them.
+	Pick int
+	// PointsPerItem overrides the points of the questions drawn from the section, 0 meaning the points of each question.
+	PointsPerItem float64
+	// Shuffle is true when the questions of the section are presented in random order.
+	Shuffle bool
+	// SourceBank is the identifier of the question bank the questions are drawn from, when they are not included in the section itself.
+	SourceBank string
+	Questions  []Question
+	Sections   []QuizSection
+}
+
+// QuizSettings are the general settings of a quiz, taken from the `<qtimetadata>` of the assessment.
+type QuizSettings struct {
+	// Profile is the CC profile of the assessment, e.g. `cc.exam.v0p1`.
+	Profile        string
+	AssessmentType string
+	ScoreType      string
+	// MaxAttempts is the number of attempts allowed, 0 meaning unlimited.
+	MaxAttempts int
+	// TimeLimit is the time allowed, in minutes, 0 meaning no limit.
+	TimeLimit int
+}
+
+// Question is a single question of a Quiz, with its choices, correct answers and feedback.
+type Question struct {
+	Identifier string
+	Title      string
+	Type       QuestionType
+	Text       string
+	Choices    []Choice
+	// Answers are the identifiers of the correct choices for choice-based questions, or the accepted texts for fill-in-the-blank and pattern match questions.
+	Answers []string
+	// PartialCredit holds the identifiers of the choices, or the accepted texts, which only give part of the score, with the percentage of the maximum score they give.
+	PartialCredit map[string]float64
+	// CaseSensitive is true when the accepted texts of a fill-in-the-blank or pattern match question must match case.
+	CaseSensitive bool
+	// Points is the weight of the question, from the `cc_weighting` metadata field, defaulting to 1.
+	Points float64
+	// MaxScore is the maximum value of the score variable, usually 100.
+	MaxScore float64
+	Feedback Feedback
+}
+
+// Choice is one of the possible responses to a choice-based question.
+type Choice struct {
+	Identifier string
+	Text       string
+	Correct    bool
+	// Credit is the percentage of the maximum score given by a choice which is partially correct, e.g. 50 for one which adds 50 to a score of at most 100, and 0 for the others.
+	Credit   float64
+	Feedback string
+}
+
+// Feedback holds the texts displayed to the learner after answering a question.
+type Feedback struct {
+	General   string
+	Correct   string
+	Incorrect string
+}
+
+// Quizzes returns a slice of all QTI assessments of the cartridge as Quizzes.
+func (cc IMSCC) Quizzes() ([]Quiz, error) {
+	quizzes := make([]Quiz, 0)
+
+	qtis, err := cc.QTIs()
+	if err != nil {
+		return quizzes, err
+	}
+
+	for _, qti := range qtis {
+		quizzes = append(quizzes, NewQuiz(qti))
+	}
+
+	return quizzes, nil
+}
+
+// NewQuiz builds a Quiz from a decoded QTI assessment.
+func NewQuiz(qti types.Questestinterop) Quiz {
+	meta := qti.Assessment.Qtimetadata
+	quiz := Quiz{
+		Identifier: qti.Assessment.Ident,
+		Title:      qti.Assessment.Title,
+		Settings: QuizSettings{
+			Profile:        meta.Field("cc_profile"),
+			AssessmentType: meta.Field("qmd_assessmenttype"),
+			ScoreType:      meta.Field("qmd_scoretype"),
+		},
+		Questions: make([]Question, 0),
+	}
+
+	quiz.Settings.MaxAttempts, _ = strconv.Atoi(meta.Field("cc_maxattempts"))
+	quiz.Settings.TimeLimit, _ = strconv.Atoi(meta.Field("qmd_timelimit"))
+
+	for _, item := range qti.Items() {
+		quiz.Questions = append(quiz.Questions, NewQuestion(item))
+	}
+
+	quiz.Sections = make([]QuizSection, 0)
+	for _, s := range qti.Assessment.Section {
+		quiz.Sections = append(quiz.Sections, newQuizSection(s))
+	}
+
+	return quiz
+}
+
+// newQuizSection recursively builds a QuizSection and its subsections from a QTI section.
+func newQuizSection(s types.Section) QuizSection {
+	selection := s.SelectionOrdering.Selection
+	section := QuizSection{
+		Identifier: s.Ident,
+		Title:      s.Title,
+		Shuffle:    strings.EqualFold(s.SelectionOrdering.Order.OrderType, "random"),
+		SourceBank: strings.TrimSpace(selection.SourcebankRef),
+		Questions:  make([]Question, 0),
+		Sections:   make([]QuizSection, 0),
+	}
+
+	section.Pick, _ = strconv.Atoi(strings.TrimSpace(selection.SelectionNumber))
+	section.PointsPerItem, _ = strconv.ParseFloat(strings.TrimSpace(selection.SelectionExtension.PointsPerItem), 64)
+
+	for _, item := range s.Item {
+		section.Questions = append(section.Questions, NewQuestion(item))
+	}
+
+	for _, sub := range s.Section {
+		section.Sections = append(section.Sections, newQuizSection(sub))
+	}
+
+	return section
+}
+
+// Size returns the number of questions presented to a learner taking the quiz.
+func (q Quiz) Size() int {
+	size := 0
+	for _, s := range q.Sections {
+		size += s.Size()
+	}
+
+	return size
+}
+
+// Draw returns the questions presented to a learner for one attempt at the quiz, applying the selection and ordering rules of each section. When r is nil, the questions are drawn from a source seeded with the current time.
+func (q Quiz) Draw(r *rand.Rand) []Question {
+	r = randOrDefault(r)
+	questions := make([]Question, 0)
+	for _, s := range q.Sections {
+		questions = append(questions, s.Draw(r)...)
+	}
+
+	return questions
+}
+
+// Size returns the number of questions presented from this section, i.e. the number of questions Draw returns. The questions of a question bank which is not part of the quiz cannot be drawn, and are not counted.
+func (s QuizSection) Size() int {
+	size := len(s.Questions)
+	for _, sub := range s.Sections {
+		size += sub.Size()
+	}
+
+	if s.Pick > 0 && s.Pick < size {
+		return s.Pick
+	}
+
+	return size
+}
+
+// Draw returns the questions presented from this section: the questions of the section and the ones drawn from its subsections form the pool from which Pick questions are selected at random, then shuffled if required. Selected questions keep their order in the pool otherwise. When r is nil, the questions are drawn from a source seeded with the current time.
+func (s QuizSection) Draw(r *rand.Rand) []Question {
+	r = randOrDefault(r)
+	pool := make([]Question, 0)
+	pool = append(pool, s.Questions...)
+	for _, sub := range s.Sections {
+		pool = append(pool, sub.Draw(r)...)
+	}
+
+	if s.Pick > 0 && s.Pick < len(pool) {
+		selected := r.Perm(len(pool))[:s.Pick]
+		sort.Ints(selected)
+
+		picked := make([]Question, 0, s.Pick)
+		for _, i := range selected {
+			picked = append(picked, pool[i])
+		}
+		pool = picked
+	}
+
+	if s.Shuffle {
+		r.Shuffle(len(pool), func(i, j int) {
+			pool[i], pool[j] = pool[j], pool[i]
+		})
+	}
+
+	if s.PointsPerItem > 0 {
+		for i := range pool {
+			pool[i].Points = s.PointsPerItem
+		}
+	}
+
+	return pool
+}
+
+// randOrDefault returns r, or a source seeded with the current time when it is nil.
+func randOrDefault(r *rand.Rand) *rand.Rand {
+	if r == nil {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	return r
+}
+
+// NewQuestion builds a Question from a QTI item. The correct answers are the ones tested by the conditions which give the maximum score, and the partially correct ones those tested by the conditions which give a lower positive score, while the feedback is attributed depending on the position of the condition relative to the scoring ones.
+func NewQuestion(item types.QTIItem) Question {
+	q := Question{
+		Identifier: item.Ident,
+		Title:      item.Title,
+		Type:       questionType(item),
+		Text:       item.Presentation.Material.Mattext.Text,
+		Choices:    make([]Choice, 0),
+		Answers:    make([]string, 0),
+		Points:     1,
+
+		PartialCredit: make(map[string]float64),
+	}
+
+	if w, err := strconv.ParseFloat(item.Itemmetadata.Qtimetadata.Field("cc_weighting"), 64); err == nil {
+		q.Points = w
+	}
+	q.MaxScore, _ = strconv.ParseFloat(item.Resprocessing.Outcomes.Decvar.Maxvalue, 64)
+
+	feedbacks := make(map[string]string)
+	for _, f := range item.Itemfeedback {
+		feedbacks[f.Ident] = f.FlowMat.Material.Mattext.Text
+	}
+
+	choiceFeedbacks := make(map[string]string)
+	scored := false
+	for _, rc := range item.Resprocessing.Respcondition {
+		fb := feedbacks[rc.Displayfeedback.Linkrefid]
+		cond := rc.Conditionvar
+
+		if isScoring(rc) {
+			scored = true
+			tests := append(append(append([]types.Vartest{}, cond.Varequal...), cond.Varsubstring...), cond.And.Varequal...)
+			for _, v := range tests {
+				q.CaseSensitive = q.CaseSensitive || strings.EqualFold(v.Case, "yes")
+			}
+
+			if credit := partialCredit(rc, q.MaxScore); credit > 0 {
+				for _, v := range tests {
+					q.PartialCredit[strings.TrimSpace(v.Text)] = credit
+				}
+				if fb != "" && len(cond.Varequal) == 1 {
+					choiceFeedbacks[strings.TrimSpace(cond.Varequal[0].Text)] = fb
+				}
+				continue
+			}
+
+			if fb != "" {
+				q.Feedback.Correct = fb
+			}
+			for _, v := range tests {
+				q.Answers = append(q.Answers, strings.TrimSpace(v.Text))
+			}
+			continue
+		}
+
+		if fb == "" {
+			continue
+		}
+
+		switch {
+		case cond.IsOther() && !scored:
+			q.Feedback.General = fb
+		case cond.IsOther():
+			q.Feedback.Incorrect = fb
+		case len(cond.Varequal) == 1:
+			choiceFeedbacks[strings.TrimSpace(cond.Varequal[0].Text)] = fb
+		}
+	}
+
+	for _, label := range item.Presentation.ResponseLid.RenderChoice.ResponseLabel {
+		c := Choice{
+			Identifier: label.Ident,
+			Text:       label.Material.Mattext.Text,
+			Credit:     q.PartialCredit[label.Ident],
+			Feedback:   choiceFeedbacks[label.Ident],
+		}
+
+		for _, a := range q.Answers {
+			if a == label.Ident {
+				c.Correct = true
+			}
+		}
+
+		q.Choices = append(q.Choices, c)
+	}
+
+	return q
+}
+
+// questionType returns the type of the item from its `cc_profile` metadata field (e.g. `cc.multiple_choice.v0p1`), or guesses it from the kind of response it expects.
+func questionType(item types.QTIItem) QuestionType {
+	profile := item.Itemmetadata.Qtimetadata.Field("cc_profile")
+	profile = strings.TrimPrefix(profile, "cc.")
+	if i := strings.Index(profile, ".v"); i >= 0 {
+		profile = profile[:i]
+	}
+
+	switch QuestionType(profile) {
+	case MultipleChoice, MultipleResponse, TrueFalse, FillInTheBlank, Essay, PatternMatch:
+		return QuestionType(profile)
+	}
+
+	switch {
+	case strings.EqualFold(item.Presentation.ResponseLid.Rcardinality, "multiple"):
+		return MultipleResponse
+	case len(item.Presentation.ResponseLid.RenderChoice.ResponseLabel) > 0:
+		return MultipleChoice
+	case item.Presentation.ResponseStr.Ident != "":
+		for _, rc := range item.Resprocessing.Respcondition {
+			if isScoring(rc) {
+				return FillInTheBlank
+			}
+		}
+		return Essay
+	}
+
+	return UnknownQuestion
+}
+
+// isScoring returns true when the condition tests the response and sets or adds a positive value to the score.
+func isScoring(rc types.Respcondition) bool {
+	if rc.Conditionvar.IsOther() || rc.Setvar.Action == "Subtract" {
+		return false
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(rc.Setvar.Text), 64)
+	return err == nil && value > 0
+}
+
+// partialCredit returns the percentage of the maximum score given by a scoring condition which does not reach it, e.g. 50 for `<setvar action="Add">50</setvar>` when the score is at most 100, or 0 when the condition gives the maximum score. Without a maximum score, any scoring condition gives it.
+func partialCredit(rc types.Respcondition, maxScore float64) float64 {
+	value, err := strconv.ParseFloat(strings.TrimSpace(rc.Setvar.Text), 64)
+	if err != nil || maxScore <= 0 || value >= maxScore {
+		return 0
+	}
+
+	return value / maxScore * 100
+}
@@ -0,0 +1,217 @@
+package commoncartridge
+
+import (
+	"encoding/xml"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const qtiExample = "./types/examples/qti.xml"
+
+func TestQuizzes(t *testing.T) {
+	cc := load(t, singleTestFile)
+	quizzes, err := cc.Quizzes()
+	require.Nil(t, err)
+
+	assert.Equal(t, len(quizzes), 17)
+	assert.Equal(t, quizzes[0].Settings.Profile, "cc.exam.v0p1")
+	assert.Equal(t, quizzes[0].Settings.TimeLimit, 75)
+
+	for _, quiz := range quizzes {
+		for _, q := range quiz.Questions {
+			assert.NotEqual(t, q.Type, UnknownQuestion)
+		}
+	}
+}
+
+func TestNewQuiz(t *testing.T) {
+	quiz := NewQuiz(loadQTI(t, qtiExample))
+
+	assert.Equal(t, quiz.Identifier, "iaa8f9f400b29e514ea8d28fd7ed067f4")
+	assert.Equal(t, quiz.Title, "ALL QUESTION TYPES QUIZ")
+	assert.Equal(t, quiz.Settings.MaxAttempts, 1)
+	assert.Equal(t, quiz.Settings.AssessmentType, "Examination")
+	require.Equal(t, len(quiz.Questions), 6)
+
+	mc := quiz.Questions[0]
+	assert.Equal(t, mc.Type, MultipleChoice)
+	assert.Equal(t, mc.Answers, []string{"2798"})
+	assert.Equal(t, len(mc.Choices), 4)
+	assert.True(t, mc.Choices[2].Correct)
+	assert.False(t, mc.Choices[0].Correct)
+	assert.Equal(t, mc.Choices[0].Feedback, "<p>add 2</p>")
+	assert.Equal(t, mc.Feedback.General, "<p>alright</p>")
+	assert.Equal(t, mc.Feedback.Correct, "<p>nice job</p>")
+	assert.Equal(t, mc.Feedback.Incorrect, "<p>too bad</p>")
+	assert.Equal(t, mc.MaxScore, 100.0)
+	assert.Equal(t, mc.Points, 1.0)
+
+	tf := quiz.Questions[1]
+	assert.Equal(t, tf.Type, TrueFalse)
+	assert.Equal(t, tf.Answers, []string{"4614"})
+
+	mr := quiz.Questions[2]
+	assert.Equal(t, mr.Type, MultipleResponse)
+	assert.Equal(t, mr.Answers, []string{"561", "5073", "5816"})
+
+	essay := quiz.Questions[3]
+	assert.Equal(t, essay.Type, Essay)
+	assert.Equal(t, essay.Title, "Tell me what you think")
+	assert.Empty(t, essay.Answers)
+
+	fib := quiz.Questions[4]
+	assert.Equal(t, fib.Type, FillInTheBlank)
+	assert.Equal(t, fib.Answers, []string{"Paris", "Paris, France"})
+	assert.False(t, fib.CaseSensitive)
+	assert.Equal(t, fib.Points, 2.0)
+
+	pattern := quiz.Questions[5]
+	assert.Equal(t, pattern.Type, PatternMatch)
+	assert.Equal(t, pattern.Answers, []string{"neon", "argon"})
+}
+
+const sectionsQTI = `<questestinterop>
+  <assessment ident="sections" title="Sections">
+    <section ident="root_section">
+      <item ident="q1" title="First"/>
+      <section ident="group" title="Group">
+        <selection_ordering>
+          <selection>
+            <selection_number>2</selection_number>
+            <selection_extension>
+              <points_per_item>5</points_per_item>
+            </selection_extension>
+          </selection>
+          <order order_type="Random"/>
+        </selection_ordering>
+        <item ident="q2" title="Second"/>
+        <item ident="q3" title="Third"/>
+        <item ident="q4" title="Fourth"/>
+      </section>
+      <section ident="bank" title="Bank">
+        <selection_ordering>
+          <selection>
+            <sourcebank_ref>i6622313a6327a7d0b77b71e3758dedaf</sourcebank_ref>
+            <selection_number>3</selection_number>
+          </selection>
+        </selection_ordering>
+      </section>
+    </section>
+    <section ident="second_section">
+      <item ident="q5" title="Fifth"/>
+    </section>
+  </assessment>
+</questestinterop>`
+
+func TestNewQuestionCase(t *testing.T) {
+	var item types.QTIItem
+	require.Nil(t, xml.Unmarshal([]byte(`<item ident="q1">
+  <itemmetadata><qtimetadata><qtimetadatafield><fieldlabel>cc_profile</fieldlabel><fieldentry>cc.fib.v0p1</fieldentry></qtimetadatafield></qtimetadata></itemmetadata>
+  <resprocessing>
+    <respcondition>
+      <conditionvar><and><varequal respident="r1" case="Yes">Paris</varequal></and></conditionvar>
+      <setvar varname="SCORE" action="Set">100</setvar>
+    </respcondition>
+  </resprocessing>
+</item>`), &item))
+
+	q := NewQuestion(item)
+	assert.Equal(t, q.Answers, []string{"Paris"})
+	assert.True(t, q.CaseSensitive)
+}
+
+// partialQTI holds a multiple choice question whose choice B only gives half of the score.
+const partialQTI = `<item ident="q1">
+  <itemmetadata><qtimetadata><qtimetadatafield><fieldlabel>cc_profile</fieldlabel><fieldentry>cc.multiple_choice.v0p1</fieldentry></qtimetadatafield></qtimetadata></itemmetadata>
+  <presentation>
+    <response_lid ident="response1" rcardinality="Single">
+      <render_choice>
+        <response_label ident="A"><material><mattext>Paris</mattext></material></response_label>
+        <response_label ident="B"><material><mattext>Lyon</mattext></material></response_label>
+        <response_label ident="C"><material><mattext>Berlin</mattext></material></response_label>
+      </render_choice>
+    </response_lid>
+  </presentation>
+  <resprocessing>
+    <outcomes><decvar varname="SCORE" vartype="Decimal" minvalue="0" maxvalue="100"/></outcomes>
+    <respcondition continue="No">
+      <conditionvar><varequal respident="response1">A</varequal></conditionvar>
+      <setvar varname="SCORE" action="Set">100</setvar>
+    </respcondition>
+    <respcondition continue="No">
+      <conditionvar><varequal respident="response1">B</varequal></conditionvar>
+      <setvar varname="SCORE" action="Add">50</setvar>
+    </respcondition>
+  </resprocessing>
+</item>`
+
+func TestNewQuestionPartialCredit(t *testing.T) {
+	var item types.QTIItem
+	require.Nil(t, xml.Unmarshal([]byte(partialQTI), &item))
+
+	q := NewQuestion(item)
+	assert.Equal(t, q.Type, MultipleChoice)
+	assert.Equal(t, q.Answers, []string{"A"})
+	assert.Equal(t, q.PartialCredit, map[string]float64{"B": 50})
+	require.Equal(t, len(q.Choices), 3)
+	assert.True(t, q.Choices[0].Correct)
+	assert.False(t, q.Choices[1].Correct, "a choice giving part of the score is not correct")
+	assert.Equal(t, q.Choices[1].Credit, 50.0)
+	assert.Equal(t, q.Choices[2].Credit, 0.0)
+}
+
+func TestQuizSections(t *testing.T) {
+	var qti types.Questestinterop
+	err := xml.Unmarshal([]byte(sectionsQTI), &qti)
+	require.Nil(t, err)
+
+	quiz := NewQuiz(qti)
+	assert.Equal(t, len(quiz.Questions), 5)
+	require.Equal(t, len(quiz.Sections), 2)
+
+	root := quiz.Sections[0]
+	assert.Equal(t, len(root.Questions), 1)
+	require.Equal(t, len(root.Sections), 2)
+
+	group := root.Sections[0]
+	assert.Equal(t, group.Title, "Group")
+	assert.Equal(t, group.Pick, 2)
+	assert.Equal(t, group.PointsPerItem, 5.0)
+	assert.True(t, group.Shuffle)
+	assert.Equal(t, len(group.Questions), 3)
+	assert.Equal(t, group.Size(), 2)
+
+	bank := root.Sections[1]
+	assert.Equal(t, bank.SourceBank, "i6622313a6327a7d0b77b71e3758dedaf")
+	assert.Equal(t, bank.Pick, 3)
+	assert.Equal(t, bank.Size(), 0)
+	assert.Equal(t, len(bank.Draw(rand.New(rand.NewSource(1)))), 0)
+
+	assert.Equal(t, quiz.Size(), 4)
+
+	drawn := quiz.Draw(rand.New(rand.NewSource(1)))
+	require.Equal(t, len(drawn), quiz.Size())
+	assert.Equal(t, drawn[0].Identifier, "q1")
+	assert.Equal(t, drawn[3].Identifier, "q5")
+	assert.Equal(t, drawn[1].Points, 5.0)
+	assert.Equal(t, drawn[2].Points, 5.0)
+	assert.NotEqual(t, drawn[1].Identifier, drawn[2].Identifier)
+
+	assert.Equal(t, len(quiz.Draw(nil)), quiz.Size())
+	assert.Equal(t, len(group.Draw(nil)), group.Size())
+}
+
+func loadQTI(t *testing.T, p string) types.Questestinterop {
+	var qti types.Questestinterop
+	bytes, err := os.ReadFile(p)
+	require.Nil(t, err)
+
+	err = xml.Unmarshal(bytes, &qti)
+	require.Nil(t, err)
+	return qti
+}
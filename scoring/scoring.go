@@ -0,0 +1,319 @@
+// The scoring package evaluates the response processing of QTI 1.2 items, allowing to grade a learner's answers without importing the cartridge into a learning management system.
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// defaultVarname is the name of the outcome variable when the item does not declare one.
+const defaultVarname = "SCORE"
+
+// Response maps the identifier of a response (e.g. `response1`) to the values given by the learner: the identifiers of the selected choices, or the text that was typed in.
+type Response map[string][]string
+
+// Result is the outcome of the response processing of an item.
+type Result struct {
+	Score    float64
+	MinScore float64
+	MaxScore float64
+	// Feedback holds the identifiers of the `<itemfeedback>` to display, in the order in which they were triggered.
+	Feedback []string
+}
+
+// Ratio returns the score relative to the range of possible scores, between 0 and 1.
+func (r Result) Ratio() float64 {
+	if r.MaxScore <= r.MinScore {
+		return 0
+	}
+
+	return (r.Score - r.MinScore) / (r.MaxScore - r.MinScore)
+}
+
+// Score evaluates the response conditions of the item in order against the given response. Each condition which is met updates the score and triggers its feedback; processing stops at the first condition which is met and does not have `continue="Yes"`. The resulting score is bound by the minimum and maximum values of the declared variable. The `<varequal>` tests of a numeric fill-in-the-blank response compare it as a number, e.g. `3` to `3.00`, while the others compare it as text. It returns an error when a condition cannot be evaluated, e.g. because it holds a test which is not supported.
+func Score(item types.QTIItem, response Response) (Result, error) {
+	decvar := item.Resprocessing.Outcomes.Decvar
+	result := Result{
+		Feedback: make([]string, 0),
+	}
+
+	varname := decvar.Varname
+	if varname == "" {
+		varname = defaultVarname
+	}
+
+	var err error
+	if result.Score, err = parseValue(decvar.Defaultval, 0); err != nil {
+		return result, fmt.Errorf("invalid default value for %s: %v", varname, err)
+	}
+	if result.MinScore, err = parseValue(decvar.Minvalue, 0); err != nil {
+		return result, fmt.Errorf("invalid minimum value for %s: %v", varname, err)
+	}
+	if result.MaxScore, err = parseValue(decvar.Maxvalue, 0); err != nil {
+		return result, fmt.Errorf("invalid maximum value for %s: %v", varname, err)
+	}
+
+	numeric := numericResponses(item)
+	for _, rc := range item.Resprocessing.Respcondition {
+		met, err := evaluate(rc.Conditionvar, response, numeric)
+		if err != nil {
+			return result, err
+		}
+		if !met {
+			continue
+		}
+
+		if rc.Setvar.Text != "" && (rc.Setvar.Varname == "" || rc.Setvar.Varname == varname) {
+			result.Score, err = apply(rc.Setvar.Action, result.Score, rc.Setvar.Text)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		if rc.Displayfeedback.Linkrefid != "" {
+			result.Feedback = append(result.Feedback, rc.Displayfeedback.Linkrefid)
+		}
+
+		if !strings.EqualFold(rc.Continue, "yes") {
+			break
+		}
+	}
+
+	if decvar.Minvalue != "" && result.Score < result.MinScore {
+		result.Score = result.MinScore
+	}
+	if decvar.Maxvalue != "" && result.Score > result.MaxScore {
+		result.Score = result.MaxScore
+	}
+
+	return result, nil
+}
+
+// Evaluate returns true when the response meets the condition. Following the CC profile of QTI, the tests directly under the `<conditionvar>` or an `<or>` are alternatives (e.g. the accepted answers of a fill-in-the-blank question), while the ones under `<and>` must all be met. The `<vargte>` and `<varlte>` tests of the same element are a range, which a numeric response meets when it is within all of their bounds. A condition holding `<other/>` is always met, while one without any test is never met. The `<varequal>` tests compare the response as text. It returns an error when a bound is not a number, or when the condition holds a test which is not supported, e.g. `<vargt>` or `<unanswered>`.
+func Evaluate(cond types.Conditionvar, response Response) (bool, error) {
+	return evaluate(cond, response, nil)
+}
+
+// evaluate returns true when the response meets the condition, as Evaluate does, comparing the values of the numeric responses to the `<varequal>` tests as numbers.
+func evaluate(cond types.Conditionvar, response Response, numeric map[string]bool) (bool, error) {
+	if err := unsupported(cond); err != nil {
+		return false, err
+	}
+
+	if cond.IsOther() {
+		return true, nil
+	}
+
+	for _, v := range cond.Varequal {
+		if equal(v, response, numeric) {
+			return true, nil
+		}
+	}
+
+	for _, v := range cond.Varsubstring {
+		if contains(v, response) {
+			return true, nil
+		}
+	}
+
+	for _, n := range cond.Not {
+		if !equal(n.Varequal, response, numeric) {
+			return true, nil
+		}
+	}
+
+	if len(cond.Vargte) > 0 || len(cond.Varlte) > 0 {
+		met, err := within(cond.Vargte, cond.Varlte, response)
+		if err != nil || met {
+			return met, err
+		}
+	}
+
+	for _, or := range cond.Or {
+		met, err := evaluate(or, response, numeric)
+		if err != nil || met {
+			return met, err
+		}
+	}
+
+	if len(cond.And.Varequal) == 0 && len(cond.And.Not) == 0 && len(cond.And.Vargte) == 0 && len(cond.And.Varlte) == 0 {
+		return false, nil
+	}
+
+	for _, v := range cond.And.Varequal {
+		if !equal(v, response, numeric) {
+			return false, nil
+		}
+	}
+
+	for _, n := range cond.And.Not {
+		if equal(n.Varequal, response, numeric) {
+			return false, nil
+		}
+	}
+
+	if len(cond.And.Vargte) > 0 || len(cond.And.Varlte) > 0 {
+		return within(cond.And.Vargte, cond.And.Varlte, response)
+	}
+
+	return true, nil
+}
+
+// unsupported returns an error when the condition, or one of its alternatives, holds a test which is not modelled.
+func unsupported(cond types.Conditionvar) error {
+	tests := append(append([]types.UnknownTest{}, cond.Unknown...), cond.And.Unknown...)
+	for _, n := range append(append([]types.Not{}, cond.Not...), cond.And.Not...) {
+		tests = append(tests, n.Unknown...)
+	}
+	if len(tests) > 0 {
+		return fmt.Errorf("unsupported test in condition: <%s>", tests[0].XMLName.Local)
+	}
+
+	for _, or := range cond.Or {
+		if err := unsupported(or); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// equal returns true when one of the values of the response is the text of the test. The values of a numeric response are also equal to the same number, e.g. `3.0` to `3.00`.
+func equal(v types.Vartest, response Response, numeric map[string]bool) bool {
+	expected := strings.TrimSpace(v.Text)
+	number, numberErr := strconv.ParseFloat(expected, 64)
+	for _, value := range response[v.Respident] {
+		value = strings.TrimSpace(value)
+		if value == expected || (!strings.EqualFold(v.Case, "yes") && strings.EqualFold(value, expected)) {
+			return true
+		}
+
+		if !numeric[v.Respident] || numberErr != nil {
+			continue
+		}
+		if n, err := strconv.ParseFloat(value, 64); err == nil && n == number {
+			return true
+		}
+	}
+
+	return false
+}
+
+// numericResponses returns the identifiers of the responses of the item which are typed-in numbers, whose `<render_fib>` has a numeric fibtype. The identifiers of choices are never numbers, even when they look like ones.
+func numericResponses(item types.QTIItem) map[string]bool {
+	numeric := make(map[string]bool)
+
+	str := item.Presentation.ResponseStr
+	switch strings.ToLower(strings.TrimSpace(str.RenderFib.Fibtype)) {
+	case "integer", "decimal", "scientific":
+		numeric[str.Ident] = true
+	}
+
+	return numeric
+}
+
+// contains returns true when one of the values of the response contains the text of the test.
+func contains(v types.Vartest, response Response) bool {
+	expected := strings.TrimSpace(v.Text)
+	for _, value := range response[v.Respident] {
+		if !strings.EqualFold(v.Case, "yes") {
+			value, expected = strings.ToLower(value), strings.ToLower(expected)
+		}
+
+		if strings.Contains(value, expected) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// within returns true when one of the values of the response is a number which is greater than or equal to the lower bounds, and less than or equal to the upper bounds, tested on it.
+func within(lower []types.Vartest, upper []types.Vartest, response Response) (bool, error) {
+	respidents := make([]string, 0)
+	for _, v := range append(append([]types.Vartest{}, lower...), upper...) {
+		respidents = append(respidents, v.Respident)
+	}
+
+	for _, respident := range respidents {
+		met, err := inRange(respident, lower, upper, response)
+		if err != nil || !met {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// inRange returns true when one of the values of the given response is within the bounds tested on it.
+func inRange(respident string, lower []types.Vartest, upper []types.Vartest, response Response) (bool, error) {
+	min, max := math.Inf(-1), math.Inf(1)
+	for _, v := range lower {
+		if v.Respident != respident {
+			continue
+		}
+		bound, err := strconv.ParseFloat(strings.TrimSpace(v.Text), 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid value for vargte: %v", err)
+		}
+		min = math.Max(min, bound)
+	}
+	for _, v := range upper {
+		if v.Respident != respident {
+			continue
+		}
+		bound, err := strconv.ParseFloat(strings.TrimSpace(v.Text), 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid value for varlte: %v", err)
+		}
+		max = math.Min(max, bound)
+	}
+
+	for _, value := range response[respident] {
+		if n, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && n >= min && n <= max {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// apply returns the new value of a variable after the given `<setvar>` action.
+func apply(action string, current float64, text string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return current, fmt.Errorf("invalid value for setvar: %v", err)
+	}
+
+	switch strings.ToLower(action) {
+	case "", "set":
+		return value, nil
+	case "add":
+		return current + value, nil
+	case "subtract":
+		return current - value, nil
+	case "multiply":
+		return current * value, nil
+	case "divide":
+		if value == 0 {
+			return current, fmt.Errorf("division by zero in setvar")
+		}
+		return current / value, nil
+	default:
+		return current, fmt.Errorf("unknown setvar action: %s", action)
+	}
+}
+
+// parseValue parses a numeric attribute, returning the fallback when it is empty.
+func parseValue(s string, fallback float64) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return fallback, nil
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
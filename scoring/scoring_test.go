@@ -0,0 +1,250 @@
+package scoring
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const qtiExample = "../types/examples/qti.xml"
+
+// canvasSample holds a quiz with a numerical question, whose answer is 4, in the non-CC assessments of a Canvas export.
+const (
+	canvasSample   = "../test_files/test_01.imscc"
+	canvasQuiz     = "non_cc_assessments/iad7e264143b9f2ec9dbc71a9d166f6f2.xml.qti"
+	canvasQuestion = "i9bff8408ac866374d09f4410017d8311"
+)
+
+func TestMultipleChoice(t *testing.T) {
+	item := loadItems(t)[0]
+
+	result, err := Score(item, Response{"response1": {"2798"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 100.0)
+	assert.Equal(t, result.Ratio(), 1.0)
+	assert.Equal(t, result.Feedback, []string{"general_fb", "2798_fb", "correct_fb"})
+
+	result, err = Score(item, Response{"response1": {"5713"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0)
+	assert.Equal(t, result.Feedback, []string{"general_fb", "5713_fb", "general_incorrect_fb"})
+}
+
+func TestTrueFalse(t *testing.T) {
+	item := loadItems(t)[1]
+
+	result, err := Score(item, Response{"response1": {"4614"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 100.0)
+
+	result, err = Score(item, Response{"response1": {"9266"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0)
+	assert.Empty(t, result.Feedback)
+}
+
+func TestMultipleResponse(t *testing.T) {
+	item := loadItems(t)[2]
+
+	result, err := Score(item, Response{"response1": {"561", "5073", "5816"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 100.0)
+
+	result, err = Score(item, Response{"response1": {"561", "5073", "5816", "5963"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0)
+
+	result, err = Score(item, Response{"response1": {"561", "5073"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0)
+}
+
+func TestEssay(t *testing.T) {
+	item := loadItems(t)[3]
+
+	result, err := Score(item, Response{"response1": {"An essay."}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0)
+	assert.Equal(t, result.MaxScore, 100.0)
+}
+
+func TestFillInTheBlank(t *testing.T) {
+	item := loadItems(t)[4]
+
+	for _, answer := range []string{"Paris", "paris ", "PARIS, France"} {
+		result, err := Score(item, Response{"response1": {answer}})
+		require.Nil(t, err)
+		assert.Equal(t, result.Score, 100.0, answer)
+		assert.Equal(t, result.Feedback, []string{"correct_fb"}, answer)
+	}
+
+	result, err := Score(item, Response{"response1": {"Lyon"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0)
+	assert.Equal(t, result.Feedback, []string{"general_incorrect_fb"})
+}
+
+func TestPatternMatch(t *testing.T) {
+	item := loadItems(t)[5]
+
+	result, err := Score(item, Response{"response1": {"I think it is Argon"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 100.0)
+
+	result, err = Score(item, Response{"response1": {"oxygen"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0)
+}
+
+func TestSetvarActions(t *testing.T) {
+	item := loadItems(t)[0]
+	item.Resprocessing.Respcondition = item.Resprocessing.Respcondition[:1]
+	item.Resprocessing.Respcondition[0].Setvar.Action = "Add"
+	item.Resprocessing.Respcondition[0].Setvar.Text = "150"
+
+	result, err := Score(item, Response{})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 100.0, "score should be bound by maxvalue")
+
+	item.Resprocessing.Respcondition[0].Setvar.Action = "Unknown"
+	_, err = Score(item, Response{})
+	assert.NotNil(t, err)
+}
+
+func TestNumerical(t *testing.T) {
+	var item types.QTIItem
+	for _, i := range loadCanvasItems(t) {
+		if i.Ident == canvasQuestion {
+			item = i
+		}
+	}
+	require.Equal(t, item.Ident, canvasQuestion)
+
+	result, err := Score(item, Response{"response1": {"4"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 100.0)
+
+	result, err = Score(item, Response{"response1": {"4.0"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 100.0, "the range should accept equivalent numbers")
+
+	result, err = Score(item, Response{"response1": {"5"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0)
+
+	result, err = Score(item, Response{"response1": {"four"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0)
+}
+
+func TestNumericEqual(t *testing.T) {
+	var item types.QTIItem
+	err := xml.Unmarshal([]byte(`<item ident="numeric">
+		<presentation><response_str ident="response1" rcardinality="Single"><render_fib fibtype="Decimal"/></response_str></presentation>
+		<resprocessing>
+			<outcomes><decvar varname="SCORE" vartype="Decimal" minvalue="0" maxvalue="100"/></outcomes>
+			<respcondition><conditionvar><varequal respident="response1">3.00</varequal></conditionvar><setvar action="Set" varname="SCORE">100</setvar></respcondition>
+		</resprocessing>
+	</item>`), &item)
+	require.Nil(t, err)
+
+	for value, expected := range map[string]float64{"3": 100, "3.0": 100, " 3.00 ": 100, "3e0": 100, "3.01": 0, "three": 0, "": 0} {
+		result, err := Score(item, Response{"response1": {value}})
+		require.Nil(t, err)
+		assert.Equal(t, result.Score, expected, value)
+	}
+
+	item.Presentation.ResponseStr.RenderFib.Fibtype = "String"
+	result, err := Score(item, Response{"response1": {"3"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0, "text should be compared as text")
+
+	met, err := Evaluate(item.Resprocessing.Respcondition[0].Conditionvar, Response{"response1": {"3"}})
+	require.Nil(t, err)
+	assert.False(t, met, "Evaluate should compare as text")
+}
+
+func TestChoiceIdentsAreNotNumbers(t *testing.T) {
+	item := loadItems(t)[0]
+
+	result, err := Score(item, Response{"response1": {"02798"}})
+	require.Nil(t, err)
+	assert.Equal(t, result.Score, 0.0, "02798 is not the choice 2798")
+}
+
+func TestRange(t *testing.T) {
+	cond := types.Conditionvar{
+		Vargte: []types.Vartest{{Respident: "response1", Text: "1.5"}},
+		Varlte: []types.Vartest{{Respident: "response1", Text: "2.5"}},
+	}
+
+	for value, expected := range map[string]bool{"1.5": true, "2": true, "2.5": true, "3": false, "1": false, "": false} {
+		met, err := Evaluate(cond, Response{"response1": {value}})
+		require.Nil(t, err)
+		assert.Equal(t, met, expected, value)
+	}
+
+	cond.Varlte[0].Text = "two"
+	_, err := Evaluate(cond, Response{"response1": {"2"}})
+	assert.NotNil(t, err)
+}
+
+func TestOther(t *testing.T) {
+	var cond types.Conditionvar
+	require.Nil(t, xml.Unmarshal([]byte(`<conditionvar><other/></conditionvar>`), &cond))
+
+	met, err := Evaluate(cond, Response{})
+	require.Nil(t, err)
+	assert.Equal(t, met, true)
+
+	met, err = Evaluate(types.Conditionvar{}, Response{"response1": {"4"}})
+	require.Nil(t, err)
+	assert.Equal(t, met, false, "a condition without any test should never be met")
+}
+
+func TestUnsupported(t *testing.T) {
+	var cond types.Conditionvar
+	err := xml.Unmarshal([]byte(`<conditionvar><or><vargt respident="response1">4</vargt></or></conditionvar>`), &cond)
+	require.Nil(t, err)
+
+	item := loadItems(t)[0]
+	item.Resprocessing.Respcondition[0].Conditionvar = cond
+
+	_, err = Score(item, Response{"response1": {"5"}})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "vargt")
+}
+
+func loadItems(t *testing.T) []types.QTIItem {
+	var qti types.Questestinterop
+	bytes, err := os.ReadFile(qtiExample)
+	require.Nil(t, err)
+
+	err = xml.Unmarshal(bytes, &qti)
+	require.Nil(t, err)
+	return qti.Items()
+}
+
+func loadCanvasItems(t *testing.T) []types.QTIItem {
+	r, err := zip.OpenReader(canvasSample)
+	require.Nil(t, err)
+	defer r.Close()
+
+	f, err := r.Open(canvasQuiz)
+	require.Nil(t, err)
+	defer f.Close()
+
+	bytes, err := io.ReadAll(f)
+	require.Nil(t, err)
+
+	var qti types.Questestinterop
+	err = xml.Unmarshal(bytes, &qti)
+	require.Nil(t, err)
+	return qti.Items()
+}
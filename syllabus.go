@@ -0,0 +1,210 @@
+package commoncartridge
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// syllabusFiles are the files in which some exporters place the syllabus, looked up when no resource of the manifest is intended as the syllabus, e.g. Canvas.
+var syllabusFiles = []string{"course_settings/syllabus.html"}
+
+var (
+	headingPattern     = regexp.MustCompile(`(?is)<h([1-6])\b[^>]*>(.*?)</h[1-6]\s*>`)
+	paragraphPattern   = regexp.MustCompile(`(?is)<p\b[^>]*>(.*?)</p\s*>`)
+	tablePattern       = regexp.MustCompile(`(?is)<table\b[^>]*>(.*?)</table\s*>`)
+	captionPattern     = regexp.MustCompile(`(?is)<caption\b[^>]*>(.*?)</caption\s*>`)
+	rowPattern         = regexp.MustCompile(`(?is)<tr\b[^>]*>(.*?)</tr\s*>`)
+	tableCellPattern   = regexp.MustCompile(`(?is)<(t[hd])\b[^>]*>(.*?)</t[hd]\s*>`)
+	descriptionPattern = regexp.MustCompile(`(?i)\b(description|overview|about (this|the) course)\b`)
+	schedulePattern    = regexp.MustCompile(`(?i)\b(week|date|day|session|class|lecture|schedule|calendar)s?\b`)
+)
+
+// Syllabus is the syllabus of the course, as found in the cartridge.
+type Syllabus struct {
+	// Identifier is the identifier of the resource of the syllabus, if any.
+	Identifier string
+	// Path is the path of the HTML file of the syllabus in the archive.
+	Path  string
+	Title string
+	// HTML is the body of the syllabus, in which the links to files of the cartridge are replaced with their path in the archive.
+	HTML string
+	// Text is the plain text of the syllabus.
+	Text string
+	// Links are the links and images of the syllabus, resolved to the files and resources of the cartridge.
+	Links   []Link
+	Outline Outline
+}
+
+// Outline is the structure of a syllabus.
+type Outline struct {
+	Headings []Heading
+	// Description is the plain text of the section whose heading mentions a description or an overview of the course or, when there is none, of the first paragraph.
+	Description string
+	// Schedule are the tables whose header, caption or heading mentions weeks, dates, sessions or a schedule.
+	Schedule []Table
+}
+
+// Heading is a title of a section of a syllabus.
+type Heading struct {
+	// Level is the level of the heading, from 1 for `<h1>` to 6.
+	Level int
+	Text  string
+}
+
+// Table is an HTML table, whose cells are reduced to their plain text.
+type Table struct {
+	Caption string
+	// Header is the first row of the table, when it is made of `<th>` cells.
+	Header []string
+	Rows   [][]string
+}
+
+// Syllabus returns the syllabus of the cartridge, from the resource whose intended use is `syllabus` or, failing that, from the `course_settings/syllabus.html` of Canvas exports.
+func (cc IMSCC) Syllabus() (Syllabus, error) {
+	syllabus := Syllabus{Links: make([]Link, 0)}
+
+	for _, r := range cc.manifest.Resources.Resource {
+		if strings.EqualFold(r.Intendeduse, "syllabus") {
+			syllabus.Identifier, syllabus.Path = r.Identifier, ResourceHref(r)
+			break
+		}
+	}
+
+	if syllabus.Path == "" {
+		for _, f := range syllabusFiles {
+			if cc.HasFile(f) {
+				syllabus.Identifier, syllabus.Path = cc.findResourceByFile(f), f
+				break
+			}
+		}
+	}
+
+	if syllabus.Path == "" {
+		return syllabus, fmt.Errorf("could not find a syllabus in the cartridge")
+	}
+
+	bytesArray, err := cc.ReadFile(syllabus.Path)
+	if err != nil {
+		return syllabus, err
+	}
+
+	content := string(bytesArray)
+	syllabus.Title = HTMLTitle(content)
+	content = HTMLBody(content)
+
+	lr := cc.NewLinkResolver(path.Dir(syllabus.Path))
+	syllabus.Links = lr.Links(content)
+	syllabus.HTML = strings.TrimSpace(lr.RewriteLinks(content, func(link Link) string {
+		if link.Path != "" {
+			return link.Path
+		}
+		return link.Raw
+	}))
+	syllabus.Text = PlainText(content)
+	syllabus.Outline = ParseOutline(content)
+
+	return syllabus, nil
+}
+
+// ParseOutline returns the headings, the course description and the schedule tables of the given HTML.
+func ParseOutline(content string) Outline {
+	outline := Outline{Headings: make([]Heading, 0), Schedule: make([]Table, 0)}
+
+	//-- the positions of the headings in the content, leaving out the empty ones, e.g. those holding an image
+	headings := make([][]int, 0)
+	for _, h := range headingPattern.FindAllStringSubmatchIndex(content, -1) {
+		text := SingleLine(content[h[4]:h[5]])
+		if text == "" {
+			continue
+		}
+
+		headings = append(headings, h)
+		outline.Headings = append(outline.Headings, Heading{Level: int(content[h[2]] - '0'), Text: text})
+	}
+
+	for i, h := range outline.Headings {
+		if !descriptionPattern.MatchString(h.Text) {
+			continue
+		}
+
+		end := len(content)
+		if i+1 < len(headings) {
+			end = headings[i+1][0]
+		}
+		outline.Description = PlainText(content[headings[i][1]:end])
+		break
+	}
+
+	if outline.Description == "" {
+		for _, m := range paragraphPattern.FindAllStringSubmatch(content, -1) {
+			if text := PlainText(m[1]); text != "" {
+				outline.Description = text
+				break
+			}
+		}
+	}
+
+	for _, t := range tablePattern.FindAllStringSubmatchIndex(content, -1) {
+		table := parseTable(content[t[2]:t[3]])
+
+		heading := ""
+		for i, h := range headings {
+			if h[0] < t[0] {
+				heading = outline.Headings[i].Text
+			}
+		}
+
+		if isSchedule(table, heading) {
+			outline.Schedule = append(outline.Schedule, table)
+		}
+	}
+
+	return outline
+}
+
+// parseTable returns the caption, header and rows of the inner HTML of a table.
+func parseTable(content string) Table {
+	table := Table{Rows: make([][]string, 0)}
+	if m := captionPattern.FindStringSubmatch(content); m != nil {
+		table.Caption = SingleLine(m[1])
+	}
+
+	for i, r := range rowPattern.FindAllStringSubmatch(content, -1) {
+		row := make([]string, 0)
+		header := true
+		for _, c := range tableCellPattern.FindAllStringSubmatch(r[1], -1) {
+			row = append(row, SingleLine(c[2]))
+			header = header && strings.EqualFold(c[1], "th")
+		}
+
+		if len(row) == 0 {
+			continue
+		}
+
+		if i == 0 && header {
+			table.Header = row
+		} else {
+			table.Rows = append(table.Rows, row)
+		}
+	}
+
+	return table
+}
+
+// isSchedule returns true when the header, or the first row, of the table, its caption or the heading preceding it mentions weeks, dates, sessions or a schedule.
+func isSchedule(table Table, heading string) bool {
+	labels := table.Header
+	if len(labels) == 0 && len(table.Rows) > 0 {
+		labels = table.Rows[0]
+	}
+
+	for _, l := range labels {
+		if schedulePattern.MatchString(l) {
+			return true
+		}
+	}
+
+	return schedulePattern.MatchString(table.Caption) || schedulePattern.MatchString(heading)
+}
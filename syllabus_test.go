@@ -0,0 +1,88 @@
+package commoncartridge
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyllabus(t *testing.T) {
+	cc := load(t, singleTestFile)
+	syllabus, err := cc.Syllabus()
+	require.Nil(t, err)
+
+	assert.Equal(t, syllabus.Identifier, "i4874e552ee3da37b383af53dd9429827_syllabus")
+	assert.Equal(t, syllabus.Path, "course_settings/syllabus.html")
+	assert.Equal(t, syllabus.Title, "Syllabus")
+	assert.True(t, strings.HasPrefix(syllabus.HTML, "<p>Lorem ipsum"))
+	assert.True(t, strings.HasPrefix(syllabus.Text, "Lorem ipsum"))
+	assert.Equal(t, len(strings.Split(syllabus.Text, "\n")), 5)
+	assert.Empty(t, syllabus.Outline.Headings)
+	assert.True(t, strings.HasPrefix(syllabus.Outline.Description, "Lorem ipsum dolor sit amet"))
+
+	cc = load(t, filepath.Join(allTestFilesDir, "canvas-fall-spring-template-export.imscc"))
+	syllabus, err = cc.Syllabus()
+	require.Nil(t, err)
+
+	assert.Equal(t, syllabus.Outline.Headings[0], Heading{Level: 2, Text: "Course Title"})
+	assert.Equal(t, syllabus.Outline.Headings[1], Heading{Level: 3, Text: "Course Description:"})
+	assert.Equal(t, syllabus.Outline.Description, "Insert course description here.")
+	assert.Empty(t, syllabus.Outline.Schedule)
+	assert.NotContains(t, syllabus.Text, "<")
+
+	cc = load(t, filepath.Join(allTestFilesDir, "py4e_export.imscc"))
+	_, err = cc.Syllabus()
+	assert.NotNil(t, err)
+}
+
+func TestSyllabusLinks(t *testing.T) {
+	cc := load(t, filepath.Join(allTestFilesDir, "sample-public-sandbox-course-export.imscc"))
+	syllabus, err := cc.Syllabus()
+	require.Nil(t, err)
+
+	require.Equal(t, len(syllabus.Links), 5)
+	assert.Equal(t, syllabus.Links[0].Attribute, "src")
+	assert.Equal(t, syllabus.Links[0].Path, "web_resources/cmc_blue_logo.png")
+	assert.Contains(t, syllabus.HTML, `<img src="web_resources/cmc_blue_logo.png"`)
+	assert.Contains(t, syllabus.HTML, `href="%24CANVAS_COURSE_REFERENCE%24/modules"`)
+
+	//-- the heading holding the logo has no text
+	assert.Equal(t, syllabus.Outline.Headings[0].Text, "Welcome to this public Canvas sandbox course.")
+}
+
+func TestParseOutline(t *testing.T) {
+	content := `<h1>Intro to Chemistry</h1>
+<h2>Course overview</h2>
+<p>Atoms &amp; molecules.</p>
+<p>Lab work.</p>
+<h2>Grading</h2>
+<table><tr><th>Grade</th><th>Percent</th></tr><tr><td>A</td><td>90</td></tr></table>
+<h2>Schedule</h2>
+<table><caption>Fall term</caption><tr><td>1</td><td>Atoms</td></tr><tr><td>2</td><td>Bonds</td></tr></table>
+<table><thead><tr><th>Week</th><th>Topic</th></tr></thead><tbody><tr><td>1</td><td><b>Atoms</b></td></tr></tbody></table>`
+
+	outline := ParseOutline(content)
+	assert.Equal(t, outline.Headings, []Heading{{1, "Intro to Chemistry"}, {2, "Course overview"}, {2, "Grading"}, {2, "Schedule"}})
+	assert.Equal(t, outline.Description, "Atoms & molecules.\nLab work.")
+
+	require.Equal(t, len(outline.Schedule), 2)
+	assert.Equal(t, outline.Schedule[0].Caption, "Fall term")
+	assert.Nil(t, outline.Schedule[0].Header)
+	assert.Equal(t, outline.Schedule[0].Rows, [][]string{{"1", "Atoms"}, {"2", "Bonds"}})
+	assert.Equal(t, outline.Schedule[1].Header, []string{"Week", "Topic"})
+	assert.Equal(t, outline.Schedule[1].Rows, [][]string{{"1", "Atoms"}})
+}
+
+func TestPlainText(t *testing.T) {
+	assert.Equal(t, PlainText("<head><title>T</title></head><p>a&nbsp;b</p><table><tr><td>1</td><td>2</td></tr></table>"), "a b\n1 2")
+	assert.Equal(t, SingleLine("<p>a</p>\n<p>b  c</p>"), "a b c")
+	assert.Equal(t, HTMLBody("<html><body class=\"c\"><p>a</p></body></html>"), "<p>a</p>")
+	assert.Equal(t, HTMLBody("<p>a</p>"), "<p>a</p>")
+	assert.Equal(t, HTMLTitle("<head><title>A &amp; B</title></head>"), "A & B")
+	assert.Equal(t, HTMLTitle("<p>a</p>"), "")
+	assert.True(t, IsHTML("a <em>b</em>"))
+	assert.False(t, IsHTML("a < b"))
+}
@@ -0,0 +1,60 @@
+package commoncartridge
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	tagPattern        = regexp.MustCompile(`<[^>]*>`)
+	blockPattern      = regexp.MustCompile(`(?i)<(br|/p|/div|/li|/h[1-6]|/tr|/table)[^>]*>`)
+	cellPattern       = regexp.MustCompile(`(?i)</t[dh]\s*>`)
+	hiddenPattern     = regexp.MustCompile(`(?is)<(head|script|style)\b.*?</(head|script|style)\s*>`)
+	whitespacePattern = regexp.MustCompile(`[ \t\r\f\v]+`)
+	newlinesPattern   = regexp.MustCompile(`\s*\n\s*`)
+	titlePattern      = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title\s*>`)
+	bodyPattern       = regexp.MustCompile(`(?is)<body[^>]*>(.*)</body\s*>`)
+)
+
+// PlainText strips the HTML tags and entities of the given content, keeping line breaks between block elements and spaces between table cells. The content of the `<head>`, `<script>` and `<style>` elements is left out.
+func PlainText(content string) string {
+	text := hiddenPattern.ReplaceAllString(content, "")
+	text = blockPattern.ReplaceAllString(text, "\n")
+	text = cellPattern.ReplaceAllString(text, " ")
+	text = tagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = strings.ReplaceAll(text, "\u00a0", " ")
+	text = whitespacePattern.ReplaceAllString(text, " ")
+	text = newlinesPattern.ReplaceAllString(text, "\n")
+
+	return strings.TrimSpace(text)
+}
+
+// SingleLine returns the plain text of the given content on a single line, e.g. for a title or a table cell.
+func SingleLine(content string) string {
+	return strings.Join(strings.Fields(PlainText(content)), " ")
+}
+
+// HTMLTitle returns the plain text of the `<title>` of an HTML page, or an empty string when it has none.
+func HTMLTitle(content string) string {
+	if m := titlePattern.FindStringSubmatch(content); m != nil {
+		return PlainText(m[1])
+	}
+
+	return ""
+}
+
+// HTMLBody returns the content of the `<body>` of an HTML page, or the whole content when it is a fragment without a body.
+func HTMLBody(content string) string {
+	if m := bodyPattern.FindStringSubmatch(content); m != nil {
+		return m[1]
+	}
+
+	return content
+}
+
+// IsHTML returns true when the given content holds any HTML tag, rather than plain text.
+func IsHTML(content string) bool {
+	return tagPattern.MatchString(content)
+}
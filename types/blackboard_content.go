@@ -0,0 +1,64 @@
+package types
+
+import "encoding/xml"
+
+// BlackboardManifest is the `imsmanifest.xml` of a Blackboard export, decoded for the attributes of the `bb` namespace that Blackboard adds to its resources.
+type BlackboardManifest struct {
+	XMLName   xml.Name `xml:"manifest"`
+	Text      string   `xml:",chardata"`
+	Resources struct {
+		Text     string               `xml:",chardata"`
+		Resource []BlackboardResource `xml:"resource"`
+	} `xml:"resources"`
+}
+
+// BlackboardResource is a resource of a Blackboard export, whose document is given by its `bb:file` attribute rather than by its href.
+type BlackboardResource struct {
+	Text       string `xml:",chardata"`
+	Identifier string `xml:"identifier,attr"`
+	Type       string `xml:"type,attr"`
+	Href       string `xml:"href,attr"`
+	// File and Title are in the `bb` namespace.
+	File  string `xml:"file,attr"`
+	Title string `xml:"title,attr"`
+}
+
+// BlackboardContent is the `.dat` document of a content item of a Blackboard course, such as a document, an external link or a folder.
+type BlackboardContent struct {
+	XMLName xml.Name        `xml:"CONTENT"`
+	Text    string          `xml:",chardata"`
+	ID      string          `xml:"id,attr"`
+	Title   BlackboardValue `xml:"TITLE"`
+	Body    struct {
+		Text string `xml:",chardata"`
+		// Content is the HTML of the body, when Type is `H`.
+		Content string          `xml:"TEXT"`
+		Type    BlackboardValue `xml:"TYPE"`
+	} `xml:"BODY"`
+	ContentHandler BlackboardValue `xml:"CONTENTHANDLER"`
+	URL            BlackboardValue `xml:"URL"`
+	Flags          struct {
+		Text        string          `xml:",chardata"`
+		IsAvailable BlackboardValue `xml:"ISAVAILABLE"`
+		IsFolder    BlackboardValue `xml:"ISFOLDER"`
+	} `xml:"FLAGS"`
+	Files struct {
+		Text string           `xml:",chardata"`
+		File []BlackboardFile `xml:"FILE"`
+	} `xml:"FILES"`
+}
+
+// BlackboardFile is a file attached to a content item of a Blackboard course.
+type BlackboardFile struct {
+	Text string `xml:",chardata"`
+	ID   string `xml:"id,attr"`
+	// Name is the location of the file in the content collection, e.g. `/xid-1234_1`.
+	Name     string          `xml:"NAME"`
+	LinkName BlackboardValue `xml:"LINKNAME"`
+}
+
+// BlackboardValue is an element of a Blackboard document whose value is held by its `value` attribute.
+type BlackboardValue struct {
+	Text  string `xml:",chardata"`
+	Value string `xml:"value,attr"`
+}
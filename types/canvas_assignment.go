@@ -0,0 +1,122 @@
+package types
+
+import "encoding/xml"
+
+// CanvasAssignment holds the settings of a Canvas assignment. It is the root of the `assignment_settings.xml` document of an assignment, and the `<assignment>` element through which a graded quiz or discussion topic takes part in the gradebook. Older exports put it in the `<extensions>` of the `assignment.xml` of the assignment.
+type CanvasAssignment struct {
+	Text                           string `xml:",chardata"`
+	Identifier                     string `xml:"identifier,attr"`
+	Title                          string `xml:"title"`
+	DueAt                          string `xml:"due_at"`
+	LockAt                         string `xml:"lock_at"`
+	UnlockAt                       string `xml:"unlock_at"`
+	PeerReviewsDueAt               string `xml:"peer_reviews_due_at"`
+	ModuleLocked                   string `xml:"module_locked"`
+	AssignmentGroupIdentifierref   string `xml:"assignment_group_identifierref"`
+	GradingStandardIdentifierref   string `xml:"grading_standard_identifierref"`
+	RubricIdentifierref            string `xml:"rubric_identifierref"`
+	RubricUseForGrading            string `xml:"rubric_use_for_grading"`
+	QuizIdentifierref              string `xml:"quiz_identifierref"`
+	WorkflowState                  string `xml:"workflow_state"`
+	AllowedExtensions              string `xml:"allowed_extensions"`
+	HasGroupCategory               string `xml:"has_group_category"`
+	PointsPossible                 string `xml:"points_possible"`
+	GradingType                    string `xml:"grading_type"`
+	AllDay                         string `xml:"all_day"`
+	AllDayDate                     string `xml:"all_day_date"`
+	SubmissionTypes                string `xml:"submission_types"`
+	Position                       string `xml:"position"`
+	TurnitinEnabled                string `xml:"turnitin_enabled"`
+	PeerReviewCount                string `xml:"peer_review_count"`
+	PeerReviews                    string `xml:"peer_reviews"`
+	PeerReviewsAssigned            string `xml:"peer_reviews_assigned"`
+	AutomaticPeerReviews           string `xml:"automatic_peer_reviews"`
+	AnonymousPeerReviews           string `xml:"anonymous_peer_reviews"`
+	GradeGroupStudentsIndividually string `xml:"grade_group_students_individually"`
+	OmitFromFinalGrade             string `xml:"omit_from_final_grade"`
+	Muted                          string `xml:"muted"`
+	ModeratedGrading               string `xml:"moderated_grading"`
+	AnonymousGrading               string `xml:"anonymous_grading"`
+}
+
+// CanvasAssignmentExtension is the part of the `assignment.xml` of older Canvas exports which holds the Canvas settings of the assignment.
+type CanvasAssignmentExtension struct {
+	XMLName    xml.Name `xml:"assignment"`
+	Text       string   `xml:",chardata"`
+	Identifier string   `xml:"identifier,attr"`
+	Extensions struct {
+		Text       string           `xml:",chardata"`
+		Assignment CanvasAssignment `xml:"assignment"`
+	} `xml:"extensions"`
+}
+
+// CanvasAssignmentGroups is the `course_settings/assignment_groups.xml` document of a Canvas export, which holds the categories of the gradebook.
+type CanvasAssignmentGroups struct {
+	XMLName         xml.Name                `xml:"assignmentGroups"`
+	Text            string                  `xml:",chardata"`
+	AssignmentGroup []CanvasAssignmentGroup `xml:"assignmentGroup"`
+}
+
+// CanvasAssignmentGroup is a category of the gradebook of a Canvas course, with its weight and the rules to drop some of its grades.
+type CanvasAssignmentGroup struct {
+	Text        string `xml:",chardata"`
+	Identifier  string `xml:"identifier,attr"`
+	Title       string `xml:"title"`
+	Position    string `xml:"position"`
+	GroupWeight string `xml:"group_weight"`
+	Rules       struct {
+		Text string `xml:",chardata"`
+		Rule []struct {
+			Text      string `xml:",chardata"`
+			DropType  string `xml:"drop_type"`
+			DropCount string `xml:"drop_count"`
+		} `xml:"rule"`
+	} `xml:"rules"`
+}
+
+// CanvasQuiz is the `assessment_meta.xml` document of a Canvas quiz, which holds the settings of the quiz and, when it is graded, of its assignment.
+type CanvasQuiz struct {
+	XMLName                       xml.Name         `xml:"quiz"`
+	Text                          string           `xml:",chardata"`
+	Identifier                    string           `xml:"identifier,attr"`
+	Title                         string           `xml:"title"`
+	Description                   string           `xml:"description"`
+	DueAt                         string           `xml:"due_at"`
+	LockAt                        string           `xml:"lock_at"`
+	UnlockAt                      string           `xml:"unlock_at"`
+	ShuffleAnswers                string           `xml:"shuffle_answers"`
+	ScoringPolicy                 string           `xml:"scoring_policy"`
+	HideResults                   string           `xml:"hide_results"`
+	QuizType                      string           `xml:"quiz_type"`
+	PointsPossible                string           `xml:"points_possible"`
+	RequireLockdownBrowser        string           `xml:"require_lockdown_browser"`
+	AccessCode                    string           `xml:"access_code"`
+	IPFilter                      string           `xml:"ip_filter"`
+	ShowCorrectAnswers            string           `xml:"show_correct_answers"`
+	ShowCorrectAnswersAt          string           `xml:"show_correct_answers_at"`
+	HideCorrectAnswersAt          string           `xml:"hide_correct_answers_at"`
+	ShowCorrectAnswersLastAttempt string           `xml:"show_correct_answers_last_attempt"`
+	TimeLimit                     string           `xml:"time_limit"`
+	AllowedAttempts               string           `xml:"allowed_attempts"`
+	OneQuestionAtATime            string           `xml:"one_question_at_a_time"`
+	CantGoBack                    string           `xml:"cant_go_back"`
+	Available                     string           `xml:"available"`
+	OneTimeResults                string           `xml:"one_time_results"`
+	AssignmentGroupIdentifierref  string           `xml:"assignment_group_identifierref"`
+	Assignment                    CanvasAssignment `xml:"assignment"`
+}
+
+// CanvasTopicMeta is the document holding the Canvas settings of a discussion topic, and of its assignment when it is graded.
+type CanvasTopicMeta struct {
+	XMLName        xml.Name         `xml:"topicMeta"`
+	Text           string           `xml:",chardata"`
+	Identifier     string           `xml:"identifier,attr"`
+	TopicID        string           `xml:"topic_id"`
+	Title          string           `xml:"title"`
+	PostedAt       string           `xml:"posted_at"`
+	Position       string           `xml:"position"`
+	Type           string           `xml:"type"`
+	DiscussionType string           `xml:"discussion_type"`
+	WorkflowState  string           `xml:"workflow_state"`
+	Assignment     CanvasAssignment `xml:"assignment"`
+}
@@ -0,0 +1,31 @@
+package types
+
+import "encoding/xml"
+
+// CanvasCourse is the `course_settings/course_settings.xml` document of a Canvas export. Its settings which have no field of their own are kept in Settings, in document order.
+type CanvasCourse struct {
+	XMLName                      xml.Name        `xml:"course"`
+	Text                         string          `xml:",chardata"`
+	Identifier                   string          `xml:"identifier,attr"`
+	Xmlns                        string          `xml:"xmlns,attr"`
+	Title                        string          `xml:"title"`
+	CourseCode                   string          `xml:"course_code"`
+	StartAt                      string          `xml:"start_at"`
+	ConcludeAt                   string          `xml:"conclude_at"`
+	TabConfiguration             string          `xml:"tab_configuration"`
+	GroupWeightingScheme         string          `xml:"group_weighting_scheme"`
+	DefaultView                  string          `xml:"default_view"`
+	License                      string          `xml:"license"`
+	Locale                       string          `xml:"locale"`
+	GradingStandardEnabled       string          `xml:"grading_standard_enabled"`
+	GradingStandardIdentifierRef string          `xml:"grading_standard_identifier_ref"`
+	StorageQuota                 string          `xml:"storage_quota"`
+	Settings                     []CanvasSetting `xml:",any"`
+}
+
+// CanvasSetting is an element of a Canvas settings document, holding either a value or, like `<default_post_policy>`, other settings.
+type CanvasSetting struct {
+	XMLName  xml.Name
+	Value    string          `xml:",chardata"`
+	Settings []CanvasSetting `xml:",any"`
+}
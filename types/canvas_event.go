@@ -0,0 +1,26 @@
+package types
+
+import "encoding/xml"
+
+// CanvasEvents is the `course_settings/events.xml` document of a Canvas export, which holds the events of the calendar of the course.
+type CanvasEvents struct {
+	XMLName xml.Name      `xml:"events"`
+	Text    string        `xml:",chardata"`
+	Event   []CanvasEvent `xml:"event"`
+}
+
+// CanvasEvent is an event of the calendar of a Canvas course.
+type CanvasEvent struct {
+	Text            string `xml:",chardata"`
+	Identifier      string `xml:"identifier,attr"`
+	Title           string `xml:"title"`
+	Description     string `xml:"description"`
+	StartAt         string `xml:"start_at"`
+	EndAt           string `xml:"end_at"`
+	LocationName    string `xml:"location_name"`
+	LocationAddress string `xml:"location_address"`
+	AllDay          string `xml:"all_day"`
+	AllDayDate      string `xml:"all_day_date"`
+	Rrule           string `xml:"rrule"`
+	SeriesUUID      string `xml:"series_uuid"`
+}
@@ -0,0 +1,61 @@
+package types
+
+import "encoding/xml"
+
+// CanvasFileMeta is the `course_settings/files_meta.xml` document of a Canvas export, which holds the settings of the folders and files of the course that differ from the defaults.
+type CanvasFileMeta struct {
+	XMLName xml.Name `xml:"fileMeta"`
+	Text    string   `xml:",chardata"`
+	Folders struct {
+		Text   string             `xml:",chardata"`
+		Folder []CanvasFolderMeta `xml:"folder"`
+	} `xml:"folders"`
+	Files struct {
+		Text string           `xml:",chardata"`
+		File []CanvasFileInfo `xml:"file"`
+	} `xml:"files"`
+}
+
+// CanvasFolderMeta holds the settings of a folder of the files of a Canvas course, whose path starts with `course files`.
+type CanvasFolderMeta struct {
+	Text     string `xml:",chardata"`
+	Path     string `xml:"path,attr"`
+	Locked   string `xml:"locked"`
+	Hidden   string `xml:"hidden"`
+	LockAt   string `xml:"lock_at"`
+	UnlockAt string `xml:"unlock_at"`
+}
+
+// CanvasFileInfo holds the settings of a file of a Canvas course, whose identifier is the one of its resource.
+type CanvasFileInfo struct {
+	Text        string `xml:",chardata"`
+	Identifier  string `xml:"identifier,attr"`
+	Locked      string `xml:"locked"`
+	Hidden      string `xml:"hidden"`
+	LockAt      string `xml:"lock_at"`
+	UnlockAt    string `xml:"unlock_at"`
+	DisplayName string `xml:"display_name"`
+	Category    string `xml:"category"`
+	UsageRights struct {
+		Text             string `xml:",chardata"`
+		UseJustification string `xml:"use_justification,attr"`
+		LegalCopyright   string `xml:"legal_copyright"`
+		License          string `xml:"license"`
+	} `xml:"usage_rights"`
+}
+
+// CanvasMediaTracks is the `course_settings/media_tracks.xml` document of a Canvas export, which lists the caption and subtitle tracks of the audio and video files of the course.
+type CanvasMediaTracks struct {
+	XMLName xml.Name `xml:"media_tracks"`
+	Text    string   `xml:",chardata"`
+	Media   []struct {
+		Text          string `xml:",chardata"`
+		Identifierref string `xml:"identifierref,attr"`
+		Track         []struct {
+			Text          string `xml:",chardata"`
+			Kind          string `xml:"kind,attr"`
+			Locale        string `xml:"locale,attr"`
+			Identifierref string `xml:"identifierref,attr"`
+		} `xml:"track"`
+	} `xml:"media"`
+}
@@ -0,0 +1,59 @@
+package types
+
+import "encoding/xml"
+
+// CanvasModules is the `course_settings/module_meta.xml` document of a Canvas export, which holds the settings of the modules of the course and of their items.
+type CanvasModules struct {
+	XMLName xml.Name       `xml:"modules"`
+	Text    string         `xml:",chardata"`
+	Module  []CanvasModule `xml:"module"`
+}
+
+// CanvasModule is a module of a Canvas course.
+type CanvasModule struct {
+	Text                      string `xml:",chardata"`
+	Identifier                string `xml:"identifier,attr"`
+	Title                     string `xml:"title"`
+	WorkflowState             string `xml:"workflow_state"`
+	Position                  string `xml:"position"`
+	UnlockAt                  string `xml:"unlock_at"`
+	RequireSequentialProgress string `xml:"require_sequential_progress"`
+	Locked                    string `xml:"locked"`
+	Prerequisites             struct {
+		Text         string `xml:",chardata"`
+		Prerequisite []struct {
+			Text          string `xml:",chardata"`
+			Type          string `xml:"type,attr"`
+			Title         string `xml:"title"`
+			Identifierref string `xml:"identifierref"`
+		} `xml:"prerequisite"`
+	} `xml:"prerequisites"`
+	Items struct {
+		Text string             `xml:",chardata"`
+		Item []CanvasModuleItem `xml:"item"`
+	} `xml:"items"`
+	CompletionRequirements struct {
+		Text                  string `xml:",chardata"`
+		CompletionRequirement []struct {
+			Text          string `xml:",chardata"`
+			Type          string `xml:"type,attr"`
+			MinScore      string `xml:"min_score"`
+			Identifierref string `xml:"identifierref"`
+		} `xml:"completionRequirement"`
+	} `xml:"completionRequirements"`
+}
+
+// CanvasModuleItem is an item of a Canvas module, whose content is the resource it refers to.
+type CanvasModuleItem struct {
+	Text                string `xml:",chardata"`
+	Identifier          string `xml:"identifier,attr"`
+	ContentType         string `xml:"content_type"`
+	WorkflowState       string `xml:"workflow_state"`
+	Title               string `xml:"title"`
+	Identifierref       string `xml:"identifierref"`
+	URL                 string `xml:"url"`
+	GlobalIdentifierref string `xml:"global_identifierref"`
+	Position            string `xml:"position"`
+	NewTab              string `xml:"new_tab"`
+	Indent              string `xml:"indent"`
+}
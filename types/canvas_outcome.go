@@ -0,0 +1,60 @@
+package types
+
+import "encoding/xml"
+
+// CanvasLearningOutcomes is the `course_settings/learning_outcomes.xml` document of a Canvas export, which holds the learning outcomes of the course and the groups they are organized in.
+type CanvasLearningOutcomes struct {
+	XMLName xml.Name `xml:"learningOutcomes"`
+	CanvasOutcomeList
+}
+
+// CanvasOutcomeList holds the outcome groups and outcomes at one level of the tree of outcomes.
+type CanvasOutcomeList struct {
+	Text                 string               `xml:",chardata"`
+	LearningOutcomeGroup []CanvasOutcomeGroup `xml:"learningOutcomeGroup"`
+	LearningOutcome      []CanvasOutcome      `xml:"learningOutcome"`
+}
+
+// CanvasOutcomeGroup is a group of learning outcomes, which may hold other groups.
+type CanvasOutcomeGroup struct {
+	Text             string            `xml:",chardata"`
+	Identifier       string            `xml:"identifier,attr"`
+	Title            string            `xml:"title"`
+	Description      string            `xml:"description"`
+	VendorGUID       string            `xml:"vendor_guid"`
+	LearningOutcomes CanvasOutcomeList `xml:"learningOutcomes"`
+}
+
+// CanvasOutcome is a learning outcome of a Canvas course, with its rating scale and the content it is aligned with.
+type CanvasOutcome struct {
+	Text               string `xml:",chardata"`
+	Identifier         string `xml:"identifier,attr"`
+	Title              string `xml:"title"`
+	Description        string `xml:"description"`
+	CalculationMethod  string `xml:"calculation_method"`
+	CalculationInt     string `xml:"calculation_int"`
+	IsGlobalOutcome    string `xml:"is_global_outcome"`
+	ExternalIdentifier string `xml:"external_identifier"`
+	VendorGUID         string `xml:"vendor_guid"`
+	Alignments         struct {
+		Text      string `xml:",chardata"`
+		Alignment []struct {
+			Text         string `xml:",chardata"`
+			ContentType  string `xml:"content_type"`
+			ContentID    string `xml:"content_id"`
+			MasteryType  string `xml:"mastery_type"`
+			MasteryScore string `xml:"mastery_score"`
+			Position     string `xml:"position"`
+		} `xml:"alignment"`
+	} `xml:"alignments"`
+	PointsPossible string `xml:"points_possible"`
+	MasteryPoints  string `xml:"mastery_points"`
+	Ratings        struct {
+		Text   string `xml:",chardata"`
+		Rating []struct {
+			Text        string `xml:",chardata"`
+			Description string `xml:"description"`
+			Points      string `xml:"points"`
+		} `xml:"rating"`
+	} `xml:"ratings"`
+}
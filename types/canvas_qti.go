@@ -0,0 +1,141 @@
+package types
+
+import "encoding/xml"
+
+// CanvasQuestestinterop is a QTI 1.2 document of the `non_cc_assessments` folder of a Canvas export. It holds either a question bank (`<objectbank>`) or the full definition of a quiz (`<assessment>`), whose items may be of types that the CC profile cannot carry.
+type CanvasQuestestinterop struct {
+	XMLName    xml.Name `xml:"questestinterop"`
+	Text       string   `xml:",chardata"`
+	Objectbank struct {
+		Text        string       `xml:",chardata"`
+		Ident       string       `xml:"ident,attr"`
+		Qtimetadata Qtimetadata  `xml:"qtimetadata"`
+		Item        []CanvasItem `xml:"item"`
+	} `xml:"objectbank"`
+	Assessment struct {
+		Text        string          `xml:",chardata"`
+		Ident       string          `xml:"ident,attr"`
+		Title       string          `xml:"title,attr"`
+		Qtimetadata Qtimetadata     `xml:"qtimetadata"`
+		Section     []CanvasSection `xml:"section"`
+	} `xml:"assessment"`
+}
+
+// Items returns the items of the question bank, or all the items of the quiz, going recursively through its sections.
+func (q CanvasQuestestinterop) Items() []CanvasItem {
+	items := make([]CanvasItem, 0)
+	items = append(items, q.Objectbank.Item...)
+	for _, s := range q.Assessment.Section {
+		items = append(items, s.Items()...)
+	}
+
+	return items
+}
+
+// CanvasSection is a section of a Canvas quiz, i.e. the root section or a question group.
+type CanvasSection struct {
+	Text              string            `xml:",chardata"`
+	Ident             string            `xml:"ident,attr"`
+	Title             string            `xml:"title,attr"`
+	SelectionOrdering SelectionOrdering `xml:"selection_ordering"`
+	Section           []CanvasSection   `xml:"section"`
+	Item              []CanvasItem      `xml:"item"`
+}
+
+// Items returns the items of the section, then the ones of all its subsections, regardless of their order in the document.
+func (s CanvasSection) Items() []CanvasItem {
+	items := make([]CanvasItem, 0)
+	items = append(items, s.Item...)
+	for _, sub := range s.Section {
+		items = append(items, sub.Items()...)
+	}
+
+	return items
+}
+
+// CanvasItem is a question of a Canvas QTI document. Unlike a QTIItem, it can expect several responses (e.g. one per blank of a fill in multiple blanks question, or one per left-hand item of a matching question), and its `<itemproc_extension>` describes the formula of calculated questions.
+type CanvasItem struct {
+	Text         string `xml:",chardata"`
+	Ident        string `xml:"ident,attr"`
+	Title        string `xml:"title,attr"`
+	Itemmetadata struct {
+		Text        string      `xml:",chardata"`
+		Qtimetadata Qtimetadata `xml:"qtimetadata"`
+	} `xml:"itemmetadata"`
+	Presentation struct {
+		Text        string           `xml:",chardata"`
+		Material    Material         `xml:"material"`
+		ResponseLid []CanvasResponse `xml:"response_lid"`
+		ResponseStr []CanvasResponse `xml:"response_str"`
+	} `xml:"presentation"`
+	Resprocessing struct {
+		Text     string `xml:",chardata"`
+		Outcomes struct {
+			Text   string `xml:",chardata"`
+			Decvar struct {
+				Text       string `xml:",chardata"`
+				Defaultval string `xml:"defaultval,attr"`
+				Maxvalue   string `xml:"maxvalue,attr"`
+				Minvalue   string `xml:"minvalue,attr"`
+				Varname    string `xml:"varname,attr"`
+				Vartype    string `xml:"vartype,attr"`
+			} `xml:"decvar"`
+		} `xml:"outcomes"`
+		Respcondition []Respcondition `xml:"respcondition"`
+	} `xml:"resprocessing"`
+	Itemfeedback      []Itemfeedback `xml:"itemfeedback"`
+	ItemprocExtension struct {
+		Text       string           `xml:",chardata"`
+		Calculated CanvasCalculated `xml:"calculated"`
+	} `xml:"itemproc_extension"`
+}
+
+// CanvasResponse is a `<response_lid>` or a `<response_str>` of a CanvasItem. Its Material is the label of the response, such as the name of a blank or the left-hand side of a matching pair.
+type CanvasResponse struct {
+	Text         string   `xml:",chardata"`
+	Ident        string   `xml:"ident,attr"`
+	Rcardinality string   `xml:"rcardinality,attr"`
+	Material     Material `xml:"material"`
+	RenderChoice struct {
+		Text          string          `xml:",chardata"`
+		ResponseLabel []ResponseLabel `xml:"response_label"`
+	} `xml:"render_choice"`
+	RenderFib struct {
+		Text          string          `xml:",chardata"`
+		Fibtype       string          `xml:"fibtype,attr"`
+		ResponseLabel []ResponseLabel `xml:"response_label"`
+	} `xml:"render_fib"`
+}
+
+// CanvasCalculated describes a calculated (formula) question: the formula giving the answer, the tolerance of the answer, the ranges of its variables and the sets of values generated from them.
+type CanvasCalculated struct {
+	Text            string `xml:",chardata"`
+	AnswerTolerance string `xml:"answer_tolerance"`
+	Formulas        struct {
+		Text          string   `xml:",chardata"`
+		DecimalPlaces string   `xml:"decimal_places,attr"`
+		Formula       []string `xml:"formula"`
+	} `xml:"formulas"`
+	Vars struct {
+		Text string `xml:",chardata"`
+		Var  []struct {
+			Text  string `xml:",chardata"`
+			Name  string `xml:"name,attr"`
+			Scale string `xml:"scale,attr"`
+			Min   string `xml:"min"`
+			Max   string `xml:"max"`
+		} `xml:"var"`
+	} `xml:"vars"`
+	VarSets struct {
+		Text   string `xml:",chardata"`
+		VarSet []struct {
+			Text  string `xml:",chardata"`
+			Ident string `xml:"ident,attr"`
+			Var   []struct {
+				Text string `xml:",chardata"`
+				Name string `xml:"name,attr"`
+			} `xml:"var"`
+			Answer string `xml:"answer"`
+		} `xml:"var_set"`
+	} `xml:"var_sets"`
+}
@@ -0,0 +1,50 @@
+package types
+
+import "encoding/xml"
+
+// CanvasRubrics is the `course_settings/rubrics.xml` document of a Canvas export, which holds the rubrics of the course.
+type CanvasRubrics struct {
+	XMLName xml.Name       `xml:"rubrics"`
+	Text    string         `xml:",chardata"`
+	Rubric  []CanvasRubric `xml:"rubric"`
+}
+
+// CanvasRubric is a rubric of a Canvas course, made of criteria which are each scored with one of their ratings.
+type CanvasRubric struct {
+	Text                      string `xml:",chardata"`
+	Identifier                string `xml:"identifier,attr"`
+	ReadOnly                  string `xml:"read_only"`
+	Title                     string `xml:"title"`
+	Reusable                  string `xml:"reusable"`
+	Public                    string `xml:"public"`
+	PointsPossible            string `xml:"points_possible"`
+	HideScoreTotal            string `xml:"hide_score_total"`
+	FreeFormCriterionComments string `xml:"free_form_criterion_comments"`
+	Criteria                  struct {
+		Text      string            `xml:",chardata"`
+		Criterion []CanvasCriterion `xml:"criterion"`
+	} `xml:"criteria"`
+}
+
+// CanvasCriterion is a criterion of a Canvas rubric. Criteria taken from a learning outcome refer to it, and have mastery points.
+type CanvasCriterion struct {
+	Text                         string `xml:",chardata"`
+	CriterionID                  string `xml:"criterion_id"`
+	Points                       string `xml:"points"`
+	MasteryPoints                string `xml:"mastery_points"`
+	IgnoreForScoring             string `xml:"ignore_for_scoring"`
+	Description                  string `xml:"description"`
+	LongDescription              string `xml:"long_description"`
+	LearningOutcomeIdentifierref string `xml:"learning_outcome_identifierref"`
+	Ratings                      struct {
+		Text   string `xml:",chardata"`
+		Rating []struct {
+			Text            string `xml:",chardata"`
+			Description     string `xml:"description"`
+			LongDescription string `xml:"long_description"`
+			Points          string `xml:"points"`
+			CriterionID     string `xml:"criterion_id"`
+			ID              string `xml:"id"`
+		} `xml:"rating"`
+	} `xml:"ratings"`
+}
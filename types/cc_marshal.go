@@ -0,0 +1,397 @@
+package types
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// MarshalDocument returns the XML document of a Manifest, Topic, WebLink, Assignment, CartridgeBasicltiLink or Questestinterop, indented by two spaces, as the Writer of a cartridge writes it: with the namespaces of its version of the standard, and without the elements and attributes which are empty. The elements that the structs do not model, such as vendor extensions, are not written. Other values are marshaled as xml.MarshalIndent does, and so are these types when given to encoding/xml directly.
+func MarshalDocument(v interface{}) ([]byte, error) {
+	switch d := v.(type) {
+	case Manifest:
+		v = manifestDocument(d)
+	case Topic:
+		v = topicDocument(d)
+	case WebLink:
+		v = webLinkDocument(d)
+	case Assignment:
+		v = assignmentDocument(d)
+	case CartridgeBasicltiLink:
+		v = ltiDocument(d)
+	case Questestinterop:
+		v = qtiDocument(d)
+	}
+
+	return xml.MarshalIndent(v, "", "  ")
+}
+
+// manifestDocument, topicDocument, webLinkDocument, assignmentDocument and ltiDocument write their document for MarshalDocument, leaving the encoding of the types they wrap as is.
+type (
+	manifestDocument   Manifest
+	topicDocument      Topic
+	webLinkDocument    WebLink
+	assignmentDocument Assignment
+	ltiDocument        CartridgeBasicltiLink
+)
+
+// MarshalXML writes the manifest with the namespaces and schema locations of its version of the IMSCC standard, defaulting to version 1.3. Since the namespace prefixes of the LOM elements depend on the version, the namespace attributes of the struct are ignored. Elements and attributes which are empty are left out. The variants of the resources are written with the namespace of the extension of version 1.3, and make the other versions fail, since they have no such extension.
+func (m manifestDocument) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	w := xmlWriter{e: e}
+
+	schema, version := m.Metadata.Schema, m.Metadata.Schemaversion
+	if schema == "" {
+		schema = "IMS Common Cartridge"
+	}
+	if version == "" {
+		version = "1.3.0"
+	}
+
+	ns := CCNamespaces(version)
+	location := ns.Manifest + " " + ns.ManifestLocation
+	attrs := []string{"identifier", m.Identifier, "xmlns", ns.Manifest}
+	// lomimscc is the prefix of the metadata of the cartridge, and lom the one of the metadata of its resources and items.
+	lom, lomimscc := "lom", "lomimscc"
+	if ns.Version == "1p0" {
+		lom, lomimscc = "imsmd", "imsmd"
+		attrs = append(attrs, "xmlns:imsmd", ns.LOMManifest)
+		location += " " + ns.LOMManifest + " " + ns.LOMManifestLocation
+	} else {
+		attrs = append(attrs, "xmlns:lom", ns.LOMResource, "xmlns:lomimscc", ns.LOMManifest)
+		location += " " + ns.LOMResource + " " + ns.LOMResourceLocation + " " + ns.LOMManifest + " " + ns.LOMManifestLocation
+	}
+	for _, r := range m.Resources.Resource {
+		if r.Variant.Identifier == "" && r.Variant.Identifierref == "" {
+			continue
+		}
+		if ns.Extension == "" {
+			return fmt.Errorf("the resource %s has a variant, which version %s of the standard cannot hold", r.Identifier, version)
+		}
+		attrs = append(attrs, "xmlns:cpx", ns.Extension)
+		location += " " + ns.Extension + " " + ns.ExtensionLocation
+		break
+	}
+	attrs = append(attrs, "xmlns:xsi", XSINamespace, "xsi:schemaLocation", location)
+
+	w.start("manifest", attrs...)
+
+	w.start("metadata")
+	w.element("schema", schema)
+	w.element("schemaversion", version)
+	w.lom(lomimscc, Manifest(m))
+	w.end("metadata")
+
+	w.start("organizations")
+	org := m.Organizations.Organization
+	if org.Identifier != "" || org.Item.Identifier != "" {
+		structure := org.Structure
+		if structure == "" {
+			structure = "rooted-hierarchy"
+		}
+		w.start("organization", "identifier", org.Identifier, "structure", structure)
+		w.manifestItem(lomimscc, org.Item)
+		w.end("organization")
+	}
+	w.end("organizations")
+
+	w.start("resources")
+	for _, r := range m.Resources.Resource {
+		w.resource(lom, r)
+	}
+	w.end("resources")
+
+	w.end("manifest")
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return e.Flush()
+}
+
+// lom writes the LOM metadata of the cartridge, with the given namespace prefix, if it has any.
+func (w *xmlWriter) lom(prefix string, m Manifest) {
+	lom := m.Metadata.Lom
+	general, contribute, rights := lom.General, lom.LifeCycle.Contribute, lom.Rights
+	hasGeneral := general.Title.String.Text != "" || general.Language != "" || general.Description.String.Text != "" || general.Keyword.String.Text != ""
+	hasContribute := contribute.Date.DateTime != "" || contribute.Entity.String != "" || contribute.Role.String != ""
+	hasRights := rights.CopyrightAndOtherRestrictions.Value != "" || rights.Description.String != ""
+	if !hasGeneral && !hasContribute && !hasRights {
+		return
+	}
+
+	p := func(name string) string { return prefix + ":" + name }
+
+	// langString writes an element holding a `string` element, if its text is not empty.
+	langString := func(name, text, language string) {
+		if text == "" {
+			return
+		}
+		w.start(p(name))
+		w.element(p("string"), text, "language", language)
+		w.end(p(name))
+	}
+
+	w.start(p("lom"))
+	if hasGeneral {
+		w.start(p("general"))
+		langString("title", general.Title.String.Text, general.Title.String.Language)
+		w.optional(p("language"), general.Language)
+		langString("description", general.Description.String.Text, general.Description.String.Language)
+		langString("keyword", general.Keyword.String.Text, general.Keyword.String.Language)
+		w.end(p("general"))
+	}
+	if hasContribute {
+		w.start(p("lifeCycle"))
+		w.start(p("contribute"))
+		if contribute.Date.DateTime != "" {
+			w.start(p("date"))
+			w.element(p("dateTime"), contribute.Date.DateTime)
+			w.end(p("date"))
+		}
+		langString("entity", contribute.Entity.String, "")
+		langString("role", contribute.Role.String, "")
+		w.end(p("contribute"))
+		w.end(p("lifeCycle"))
+	}
+	if hasRights {
+		w.start(p("rights"))
+		if rights.CopyrightAndOtherRestrictions.Value != "" {
+			w.start(p("copyrightAndOtherRestrictions"))
+			w.element(p("value"), rights.CopyrightAndOtherRestrictions.Value)
+			w.end(p("copyrightAndOtherRestrictions"))
+		}
+		langString("description", rights.Description.String, "")
+		w.end(p("rights"))
+	}
+	w.end(p("lom"))
+}
+
+// manifestItem writes an item of the organization and its children, with the given namespace prefix for its metadata.
+func (w *xmlWriter) manifestItem(prefix string, item Item) {
+	w.start("item", "identifier", item.Identifier, "identifierref", item.Identifierref)
+	w.optional("title", item.Title)
+
+	if structure := item.Metadata.Lom.General.Structure; structure.Value != "" {
+		w.start("metadata")
+		w.start(prefix + ":lom")
+		w.start(prefix + ":general")
+		w.start(prefix + ":structure")
+		w.optional(prefix+":source", structure.Source)
+		w.element(prefix+":value", structure.Value)
+		w.end(prefix + ":structure")
+		w.end(prefix + ":general")
+		w.end(prefix + ":lom")
+		w.end("metadata")
+	}
+
+	for _, child := range item.Item {
+		w.manifestItem(prefix, child)
+	}
+	w.end("item")
+}
+
+// resource writes a resource of the manifest, with the given namespace prefix for its metadata.
+func (w *xmlWriter) resource(prefix string, r Resource) {
+	w.start("resource", "identifier", r.Identifier, "type", r.Type, "href", r.Href, "intendeduse", r.Intendeduse)
+
+	if role := r.Metadata.Lom.Educational.IntendedEndUserRole; role.Value != "" {
+		w.start("metadata")
+		w.start(prefix + ":lom")
+		w.start(prefix + ":educational")
+		w.start(prefix + ":intendedEndUserRole")
+		w.optional(prefix+":source", role.Source)
+		w.element(prefix+":value", role.Value)
+		w.end(prefix + ":intendedEndUserRole")
+		w.end(prefix + ":educational")
+		w.end(prefix + ":lom")
+		w.end("metadata")
+	}
+
+	for _, f := range r.File {
+		w.element("file", "", "href", f.Href)
+	}
+	for _, d := range r.Dependency {
+		w.element("dependency", "", "identifierref", d.Identifierref)
+	}
+	if v := r.Variant; v.Identifier != "" || v.Identifierref != "" {
+		w.start("cpx:variant", "identifier", v.Identifier, "identifierref", v.Identifierref)
+		w.element("cpx:metadata", v.Metadata)
+		w.end("cpx:variant")
+	}
+	w.end("resource")
+}
+
+// MarshalXML writes the discussion topic, defaulting to the namespace of version 1.3 of the standard.
+func (t topicDocument) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	w := xmlWriter{e: e}
+
+	ns := CCNamespaces("")
+	xmlns, xsi, location := t.Xmlns, t.Xsi, t.SchemaLocation
+	if xmlns == "" {
+		xmlns, location = ns.Topic, ns.Topic+" "+ns.TopicLocation
+	}
+	if xsi == "" {
+		xsi = XSINamespace
+	}
+
+	w.start("topic", "xmlns", xmlns, "xmlns:xsi", xsi, "xsi:schemaLocation", normalize(location))
+	w.element("title", t.Title)
+	w.element("text", t.Text.Text, "texttype", defaultTextType(t.Text.Texttype))
+	if len(t.Attachments.Attachment) > 0 {
+		w.start("attachments")
+		for _, a := range t.Attachments.Attachment {
+			w.element("attachment", "", "href", a.Href)
+		}
+		w.end("attachments")
+	}
+	w.end("topic")
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return e.Flush()
+}
+
+// MarshalXML writes the web link, defaulting to the namespace of version 1.3 of the standard.
+func (wl webLinkDocument) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	w := xmlWriter{e: e}
+
+	ns := CCNamespaces("")
+	xmlns, xsi, location := wl.Xmlns, wl.Xsi, wl.SchemaLocation
+	if xmlns == "" {
+		xmlns, location = ns.WebLink, ns.WebLink+" "+ns.WebLinkLocation
+	}
+	if xsi == "" {
+		xsi = XSINamespace
+	}
+
+	w.start("webLink", "xmlns", xmlns, "xmlns:xsi", xsi, "xsi:schemaLocation", normalize(location))
+	w.element("title", wl.Title)
+	w.element("url", "", "href", wl.URL.Href, "target", wl.URL.Target, "windowFeatures", wl.URL.WindowFeatures)
+	w.end("webLink")
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return e.Flush()
+}
+
+// MarshalXML writes the assignment, defaulting to the namespace of the assignment extension of the standard.
+func (a assignmentDocument) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	w := xmlWriter{e: e}
+
+	xmlns, xsi, location := a.Xmlns, a.Xsi, a.SchemaLocation
+	if xmlns == "" {
+		xmlns, location = AssignmentNamespace, AssignmentSchemaLocation
+	}
+	if xsi == "" {
+		xsi = XSINamespace
+	}
+
+	w.start("assignment", "identifier", a.Identifier, "xmlns", xmlns, "xmlns:xsi", xsi, "xsi:schemaLocation", normalize(location))
+	w.element("title", a.Title)
+	w.element("text", a.Text.Text, "texttype", defaultTextType(a.Text.Texttype))
+	if a.Gradable.Text != "" || a.Gradable.PointsPossible != "" {
+		w.element("gradable", a.Gradable.Text, "points_possible", a.Gradable.PointsPossible)
+	}
+	if len(a.SubmissionFormats.Format) > 0 {
+		w.start("submission_formats")
+		for _, f := range a.SubmissionFormats.Format {
+			w.element("format", "", "type", f.Type)
+		}
+		w.end("submission_formats")
+	}
+	w.end("assignment")
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return e.Flush()
+}
+
+// MarshalXML writes the basic LTI link, with the elements of the `blti`, `lticm` and `lticp` namespaces under these prefixes.
+func (l ltiDocument) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	w := xmlWriter{e: e}
+
+	xmlns, xsi, location := l.Xmlns, l.Xsi, l.SchemaLocation
+	if xmlns == "" {
+		xmlns, location = LTINamespace, LTISchemaLocation
+	}
+	if xsi == "" {
+		xsi = XSINamespace
+	}
+
+	w.start("cartridge_basiclti_link", "xmlns", xmlns, "xmlns:blti", BLTINamespace, "xmlns:lticm", LTICMNamespace, "xmlns:lticp", LTICPNamespace, "xmlns:xsi", xsi, "xsi:schemaLocation", normalize(location))
+	w.element("blti:title", l.Title)
+	w.optional("blti:description", l.Description)
+	if l.Custom.Property.Name != "" {
+		w.start("blti:custom")
+		w.element("lticm:property", l.Custom.Property.Text, "name", l.Custom.Property.Name)
+		w.end("blti:custom")
+	}
+	if l.Extensions.Platform != "" || l.Extensions.Property.Name != "" {
+		w.start("blti:extensions", "platform", l.Extensions.Platform)
+		if l.Extensions.Property.Name != "" {
+			w.element("lticm:property", l.Extensions.Property.Text, "name", l.Extensions.Property.Name)
+		}
+		w.end("blti:extensions")
+	}
+	w.optional("blti:launch_url", l.LaunchURL)
+	w.optional("blti:secure_launch_url", l.SecureLaunchURL)
+	w.optional("blti:icon", l.Icon)
+	w.optional("blti:secure_icon", l.SecureIcon)
+
+	v := l.Vendor
+	if v.Code != "" || v.Name != "" || v.Description != "" || v.URL != "" || v.Contact.Email != "" {
+		w.start("blti:vendor")
+		w.optional("lticp:code", v.Code)
+		w.optional("lticp:name", v.Name)
+		w.optional("lticp:description", v.Description)
+		w.optional("lticp:url", v.URL)
+		if v.Contact.Email != "" {
+			w.start("lticp:contact")
+			w.element("lticp:email", v.Contact.Email)
+			w.end("lticp:contact")
+		}
+		w.end("blti:vendor")
+	}
+
+	if l.CartridgeBundle.Identifierref != "" {
+		w.element("cartridge_bundle", "", "identifierref", l.CartridgeBundle.Identifierref)
+	}
+	if l.CartridgeIcon.Identifierref != "" {
+		w.element("cartridge_icon", "", "identifierref", l.CartridgeIcon.Identifierref)
+	}
+	w.end("cartridge_basiclti_link")
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return e.Flush()
+}
+
+// optional writes an element holding only text, if the text is not empty.
+func (w *xmlWriter) optional(name string, text string) {
+	if text != "" {
+		w.element(name, text)
+	}
+}
+
+// defaultTextType returns the given text type, or `text/html`.
+func defaultTextType(texttype string) string {
+	if texttype == "" {
+		return "text/html"
+	}
+
+	return texttype
+}
+
+// normalize collapses the whitespace of a schema location, which documents often spread over several lines.
+func normalize(location string) string {
+	return strings.Join(strings.Fields(location), " ")
+}
@@ -0,0 +1,23 @@
+package types
+
+import "encoding/xml"
+
+// D2LManifest is the `imsmanifest.xml` of a D2L Brightspace export, decoded for the attributes of the `d2l_2p0` namespace that D2L adds to its resources.
+type D2LManifest struct {
+	XMLName   xml.Name `xml:"manifest"`
+	Text      string   `xml:",chardata"`
+	Resources struct {
+		Text     string        `xml:",chardata"`
+		Resource []D2LResource `xml:"resource"`
+	} `xml:"resources"`
+}
+
+// D2LResource is a resource of a D2L Brightspace export, with the attributes that D2L adds to the ones of the standard.
+type D2LResource struct {
+	Resource
+	// Title, MaterialType and LinkTarget are in the `d2l_2p0` namespace.
+	Title string `xml:"title,attr"`
+	// MaterialType is the tool of the resource, e.g. `d2lquiz`, `d2ldropbox`, `d2ldiscussion` or `content`.
+	MaterialType string `xml:"material_type,attr"`
+	LinkTarget   string `xml:"link_target,attr"`
+}
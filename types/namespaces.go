@@ -0,0 +1,88 @@
+package types
+
+import "strings"
+
+// Namespaces are the namespaces of the documents of a cartridge, and the locations of their schemas, which differ from one version of the IMSCC standard to the other.
+type Namespaces struct {
+	// Version is the version of the standard as it appears in the names of namespaces and resource types, e.g. `1p3`.
+	Version string
+	// Manifest is the namespace of the manifest.
+	Manifest         string
+	ManifestLocation string
+	// LOMManifest and LOMResource are the namespaces of the LOM metadata of the cartridge and of its resources. They are the same in version 1.0.
+	LOMManifest         string
+	LOMManifestLocation string
+	LOMResource         string
+	LOMResourceLocation string
+	Topic               string
+	TopicLocation       string
+	WebLink             string
+	WebLinkLocation     string
+	// QTILocation is the location of the CC profile of the QTI 1.2 schema, whose namespace is always QTINamespace.
+	QTILocation string
+	// Extension is the namespace of the extension of IMS Content Packaging which declares the variants of resources. It only exists from version 1.3 on, and is empty otherwise.
+	Extension         string
+	ExtensionLocation string
+}
+
+const (
+	// LTINamespace is the namespace of the basic LTI links of a cartridge, in all versions of the standard.
+	LTINamespace = "http://www.imsglobal.org/xsd/imslticc_v1p0"
+	// LTISchemaLocation is the location of the schemas of the basic LTI links, and of the namespaces they borrow elements from.
+	LTISchemaLocation = "http://www.imsglobal.org/xsd/imslticc_v1p0 http://www.imsglobal.org/xsd/lti/ltiv1p0/imslticc_v1p0.xsd http://www.imsglobal.org/xsd/imslticp_v1p0 http://www.imsglobal.org/xsd/lti/ltiv1p0/imslticp_v1p0.xsd http://www.imsglobal.org/xsd/imslticm_v1p0 http://www.imsglobal.org/xsd/lti/ltiv1p0/imslticm_v1p0.xsd http://www.imsglobal.org/xsd/imsbasiclti_v1p0 http://www.imsglobal.org/xsd/lti/ltiv1p0/imsbasiclti_v1p0p1.xsd"
+	// BLTINamespace, LTICMNamespace and LTICPNamespace are the namespaces of the elements of the basic LTI links.
+	BLTINamespace  = "http://www.imsglobal.org/xsd/imsbasiclti_v1p0"
+	LTICMNamespace = "http://www.imsglobal.org/xsd/imslticm_v1p0"
+	LTICPNamespace = "http://www.imsglobal.org/xsd/imslticp_v1p0"
+	// AssignmentNamespace is the namespace of the assignment extension of the standard.
+	AssignmentNamespace = "http://www.imsglobal.org/xsd/imscc_extensions/assignment"
+	// AssignmentSchemaLocation is the location of the schema of the assignment extension.
+	AssignmentSchemaLocation = "http://www.imsglobal.org/xsd/imscc_extensions/assignment http://www.imsglobal.org/profile/cc/cc_extensions/cc_extresource_assignmentv1p0_v1p0.xsd"
+)
+
+// CCNamespaces returns the namespaces of the given version of the IMSCC standard (e.g. `1.1.0`), defaulting to the ones of version 1.3.
+func CCNamespaces(schemaVersion string) Namespaces {
+	version := "1p3"
+	if parts := strings.Split(schemaVersion, "."); len(parts) >= 2 && parts[0] == "1" && len(parts[1]) == 1 && parts[1] >= "0" && parts[1] <= "3" {
+		version = "1p" + parts[1]
+	}
+
+	if version == "1p0" {
+		return Namespaces{
+			Version:             version,
+			Manifest:            "http://www.imsglobal.org/xsd/imscc/imscp_v1p1",
+			ManifestLocation:    "http://www.imsglobal.org/profile/cc/ccv1p0/derived_schema/imscp_v1p2_localised.xsd",
+			LOMManifest:         "http://ltsc.ieee.org/xsd/imscc/LOM",
+			LOMManifestLocation: "http://www.imsglobal.org/profile/cc/ccv1p0/derived_schema/domainProfile_1/lomLoose_localised.xsd",
+			LOMResource:         "http://ltsc.ieee.org/xsd/imscc/LOM",
+			LOMResourceLocation: "http://www.imsglobal.org/profile/cc/ccv1p0/derived_schema/domainProfile_1/lomLoose_localised.xsd",
+			Topic:               "http://www.imsglobal.org/xsd/imsdt_v1p0",
+			TopicLocation:       "http://www.imsglobal.org/profile/cc/ccv1p0/derived_schema/domainProfile_6/imsdt_v1p0_localised.xsd",
+			WebLink:             "http://www.imsglobal.org/xsd/imswl_v1p0",
+			WebLinkLocation:     "http://www.imsglobal.org/profile/cc/ccv1p0/derived_schema/domainProfile_5/imswl_v1p0_localised.xsd",
+			QTILocation:         "http://www.imsglobal.org/profile/cc/ccv1p0/derived_schema/domainProfile_4/ims_qtiasiv1p2_localised.xsd",
+		}
+	}
+
+	profile := "http://www.imsglobal.org/profile/cc/ccv" + version + "/ccv" + version
+	ns := Namespaces{
+		Version:             version,
+		Manifest:            "http://www.imsglobal.org/xsd/imsccv" + version + "/imscp_v1p1",
+		ManifestLocation:    profile + "_imscp_v1p2_v1p0.xsd",
+		LOMManifest:         "http://ltsc.ieee.org/xsd/imsccv" + version + "/LOM/manifest",
+		LOMManifestLocation: "http://www.imsglobal.org/profile/cc/ccv" + version + "/LOM/ccv" + version + "_lommanifest_v1p0.xsd",
+		LOMResource:         "http://ltsc.ieee.org/xsd/imsccv" + version + "/LOM/resource",
+		LOMResourceLocation: "http://www.imsglobal.org/profile/cc/ccv" + version + "/LOM/ccv" + version + "_lomresource_v1p0.xsd",
+		Topic:               "http://www.imsglobal.org/xsd/imsccv" + version + "/imsdt_v" + version,
+		TopicLocation:       profile + "_imsdt_v" + version + ".xsd",
+		WebLink:             "http://www.imsglobal.org/xsd/imsccv" + version + "/imswl_v" + version,
+		WebLinkLocation:     profile + "_imswl_v" + version + ".xsd",
+		QTILocation:         profile + "_qtiasiv1p2p1_v1p0.xsd",
+	}
+	if version == "1p3" {
+		ns.Extension = "http://www.imsglobal.org/xsd/imsccv1p3/imscp_extensionv1p2"
+		ns.ExtensionLocation = profile + "_cpextensionv1p2_v1p0.xsd"
+	}
+
+	return ns
+}
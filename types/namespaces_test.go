@@ -0,0 +1,34 @@
+package types
+
+import "testing"
+
+func TestCCNamespaces(t *testing.T) {
+	ns := CCNamespaces("1.1.0")
+	pairs := [][2]string{
+		{ns.Version, "1p1"},
+		{ns.Manifest, "http://www.imsglobal.org/xsd/imsccv1p1/imscp_v1p1"},
+		{ns.ManifestLocation, "http://www.imsglobal.org/profile/cc/ccv1p1/ccv1p1_imscp_v1p2_v1p0.xsd"},
+		{ns.TopicLocation, "http://www.imsglobal.org/profile/cc/ccv1p1/ccv1p1_imsdt_v1p1.xsd"},
+		{ns.QTILocation, "http://www.imsglobal.org/profile/cc/ccv1p1/ccv1p1_qtiasiv1p2p1_v1p0.xsd"},
+	}
+	for _, p := range pairs {
+		if p[0] != p[1] {
+			t.Errorf("expected %s, got %s", p[1], p[0])
+		}
+	}
+
+	if ns := CCNamespaces("1.0.0"); ns.Manifest != "http://www.imsglobal.org/xsd/imscc/imscp_v1p1" || ns.LOMManifest != ns.LOMResource {
+		t.Errorf("expected the namespaces of version 1.0, got %v", ns)
+	}
+
+	if ns := CCNamespaces(""); ns.WebLink != "http://www.imsglobal.org/xsd/imsccv1p3/imswl_v1p3" {
+		t.Errorf("expected the namespaces to default to version 1.3, got %v", ns)
+	}
+
+	if ns := CCNamespaces("1.3.0"); ns.ExtensionLocation != "http://www.imsglobal.org/profile/cc/ccv1p3/ccv1p3_cpextensionv1p2_v1p0.xsd" {
+		t.Errorf("expected the location of the extension of version 1.3, got %s", ns.ExtensionLocation)
+	}
+	if ns.Extension != "" {
+		t.Errorf("expected no extension in version 1.1, got %s", ns.Extension)
+	}
+}
@@ -0,0 +1,254 @@
+package types
+
+import "encoding/xml"
+
+// Questestinterop was originally generated by zek from `examples/qti.xml`. It is now maintained by hand, since sections are recursive and items, choices and conditions need to be named types to be processed and built on their own (see Section, QTIItem, ResponseLabel and Respcondition).
+type Questestinterop struct {
+	XMLName        xml.Name `xml:"questestinterop"`
+	Text           string   `xml:",chardata"`
+	Xmlns          string   `xml:"xmlns,attr"`
+	Xsi            string   `xml:"xsi,attr"`
+	SchemaLocation string   `xml:"schemaLocation,attr"`
+	Assessment     struct {
+		Text        string      `xml:",chardata"`
+		Ident       string      `xml:"ident,attr"`
+		Title       string      `xml:"title,attr"`
+		Qtimetadata Qtimetadata `xml:"qtimetadata"`
+		Section     []Section   `xml:"section"`
+	} `xml:"assessment"`
+}
+
+// Items returns all the items of the assessment, going recursively through its sections. The items of a section come before the ones of its subsections, wherever they appear in the document.
+func (q Questestinterop) Items() []QTIItem {
+	items := make([]QTIItem, 0)
+	for _, s := range q.Assessment.Section {
+		items = append(items, s.Items()...)
+	}
+
+	return items
+}
+
+// QuestionBankQTI is a QTI 1.2 question bank of the CC profile, i.e. the document of a resource of type `imsqti_xmlv1p2/imscc_xmlv1p1/question-bank`. Its sections may group its items, e.g. by the question categories of the learning management system which exported it.
+type QuestionBankQTI struct {
+	XMLName    xml.Name `xml:"questestinterop"`
+	Text       string   `xml:",chardata"`
+	Objectbank struct {
+		Text        string      `xml:",chardata"`
+		Ident       string      `xml:"ident,attr"`
+		Qtimetadata Qtimetadata `xml:"qtimetadata"`
+		Section     []Section   `xml:"section"`
+		Item        []QTIItem   `xml:"item"`
+	} `xml:"objectbank"`
+}
+
+// Section groups items and other sections. Its selection and ordering rules describe how many items are drawn from it (e.g. Canvas question groups), and in which order they are presented. Its items and subsections are held apart, so their relative order in the document is lost: the items are written first.
+type Section struct {
+	Text              string            `xml:",chardata"`
+	Ident             string            `xml:"ident,attr"`
+	Title             string            `xml:"title,attr"`
+	SelectionOrdering SelectionOrdering `xml:"selection_ordering"`
+	Section           []Section         `xml:"section"`
+	Item              []QTIItem         `xml:"item"`
+}
+
+// SelectionOrdering holds the rules to select and order the items of a section.
+type SelectionOrdering struct {
+	Text      string `xml:",chardata"`
+	Selection struct {
+		Text               string `xml:",chardata"`
+		SourcebankRef      string `xml:"sourcebank_ref"`
+		SelectionNumber    string `xml:"selection_number"`
+		SelectionExtension struct {
+			Text          string `xml:",chardata"`
+			PointsPerItem string `xml:"points_per_item"`
+		} `xml:"selection_extension"`
+	} `xml:"selection"`
+	Order struct {
+		Text      string `xml:",chardata"`
+		OrderType string `xml:"order_type,attr"`
+	} `xml:"order"`
+}
+
+// Items returns the items of the section, then the ones of all its subsections.
+func (s Section) Items() []QTIItem {
+	items := make([]QTIItem, 0)
+	items = append(items, s.Item...)
+	for _, sub := range s.Section {
+		items = append(items, sub.Items()...)
+	}
+
+	return items
+}
+
+// QTIItem is a single question of an assessment, along with its presentation, response processing and feedback.
+type QTIItem struct {
+	Text         string `xml:",chardata"`
+	Ident        string `xml:"ident,attr"`
+	Title        string `xml:"title,attr"`
+	Itemmetadata struct {
+		Text        string      `xml:",chardata"`
+		Qtimetadata Qtimetadata `xml:"qtimetadata"`
+	} `xml:"itemmetadata"`
+	Presentation struct {
+		Text        string   `xml:",chardata"`
+		Material    Material `xml:"material"`
+		ResponseLid struct {
+			Text         string `xml:",chardata"`
+			Ident        string `xml:"ident,attr"`
+			Rcardinality string `xml:"rcardinality,attr"`
+			RenderChoice struct {
+				Text          string          `xml:",chardata"`
+				ResponseLabel []ResponseLabel `xml:"response_label"`
+			} `xml:"render_choice"`
+		} `xml:"response_lid"`
+		ResponseStr struct {
+			Text         string `xml:",chardata"`
+			Ident        string `xml:"ident,attr"`
+			Rcardinality string `xml:"rcardinality,attr"`
+			RenderFib    struct {
+				Text string `xml:",chardata"`
+				// Fibtype is the kind of text expected, e.g. `String`, or `Integer`, `Decimal` and `Scientific` for numbers.
+				Fibtype       string `xml:"fibtype,attr"`
+				ResponseLabel struct {
+					Text     string `xml:",chardata"`
+					Ident    string `xml:"ident,attr"`
+					Rshuffle string `xml:"rshuffle,attr"`
+				} `xml:"response_label"`
+			} `xml:"render_fib"`
+		} `xml:"response_str"`
+	} `xml:"presentation"`
+	Resprocessing struct {
+		Text     string `xml:",chardata"`
+		Outcomes struct {
+			Text   string `xml:",chardata"`
+			Decvar struct {
+				Text       string `xml:",chardata"`
+				Defaultval string `xml:"defaultval,attr"`
+				Maxvalue   string `xml:"maxvalue,attr"`
+				Minvalue   string `xml:"minvalue,attr"`
+				Varname    string `xml:"varname,attr"`
+				Vartype    string `xml:"vartype,attr"`
+			} `xml:"decvar"`
+		} `xml:"outcomes"`
+		Respcondition []Respcondition `xml:"respcondition"`
+	} `xml:"resprocessing"`
+	Itemfeedback []Itemfeedback `xml:"itemfeedback"`
+}
+
+// ResponseLabel is one of the choices of a `<render_choice>`.
+type ResponseLabel struct {
+	Text     string   `xml:",chardata"`
+	Ident    string   `xml:"ident,attr"`
+	Material Material `xml:"material"`
+}
+
+// Itemfeedback is a feedback of an item, displayed by the response conditions which refer to its Ident.
+type Itemfeedback struct {
+	Text    string `xml:",chardata"`
+	Ident   string `xml:"ident,attr"`
+	FlowMat struct {
+		Text     string   `xml:",chardata"`
+		Material Material `xml:"material"`
+	} `xml:"flow_mat"`
+}
+
+// Material is the content of a question, a choice or a feedback. Its Texttype is either `text/plain` or `text/html`.
+type Material struct {
+	Text    string `xml:",chardata"`
+	Mattext struct {
+		Text     string `xml:",chardata"`
+		Texttype string `xml:"texttype,attr"`
+	} `xml:"mattext"`
+}
+
+// Respcondition is a condition on the learner's response, which sets the outcome variables and displays feedback when it is met.
+type Respcondition struct {
+	Text            string       `xml:",chardata"`
+	Continue        string       `xml:"continue,attr"`
+	Conditionvar    Conditionvar `xml:"conditionvar"`
+	Displayfeedback struct {
+		Text         string `xml:",chardata"`
+		Feedbacktype string `xml:"feedbacktype,attr"`
+		Linkrefid    string `xml:"linkrefid,attr"`
+	} `xml:"displayfeedback"`
+	Setvar struct {
+		Text    string `xml:",chardata"`
+		Action  string `xml:"action,attr"`
+		Varname string `xml:"varname,attr"`
+	} `xml:"setvar"`
+}
+
+// Conditionvar holds the tests on the learner's response.
+type Conditionvar struct {
+	Text string `xml:",chardata"`
+	// Other is set when the condition holds `<other/>`, which matches any response.
+	Other        *struct{} `xml:"other"`
+	Varequal     []Vartest `xml:"varequal"`
+	Varsubstring []Vartest `xml:"varsubstring"`
+	Not          []Not     `xml:"not"`
+	// Vargte and Varlte compare a numeric response to a lower and upper bound, as exported by Canvas for numerical questions.
+	Vargte []Vartest `xml:"vargte"`
+	Varlte []Vartest `xml:"varlte"`
+	And    struct {
+		Text     string        `xml:",chardata"`
+		Varequal []Vartest     `xml:"varequal"`
+		Not      []Not         `xml:"not"`
+		Vargte   []Vartest     `xml:"vargte"`
+		Varlte   []Vartest     `xml:"varlte"`
+		Unknown  []UnknownTest `xml:",any"`
+	} `xml:"and"`
+	// Or holds alternative tests, e.g. an exact value or a range for Canvas numerical questions.
+	Or []Conditionvar `xml:"or"`
+	// Unknown holds the tests which are not modelled, e.g. `<vargt>` or `<unanswered>`.
+	Unknown []UnknownTest `xml:",any"`
+}
+
+// Not negates the test it holds.
+type Not struct {
+	Text     string        `xml:",chardata"`
+	Varequal Vartest       `xml:"varequal"`
+	Unknown  []UnknownTest `xml:",any"`
+}
+
+// UnknownTest is an element of a condition which is not modelled, kept with its response identifier and text content.
+type UnknownTest struct {
+	XMLName   xml.Name
+	Text      string `xml:",chardata"`
+	Respident string `xml:"respident,attr"`
+}
+
+// Vartest compares the response with the given identifier to its text content, e.g. `<varequal>` or `<varsubstring>`. The comparison is case insensitive unless Case is `Yes`.
+type Vartest struct {
+	Text      string `xml:",chardata"`
+	Respident string `xml:"respident,attr"`
+	Case      string `xml:"case,attr"`
+}
+
+// Qtimetadata is a list of label and entry pairs describing an assessment or an item (e.g. `cc_profile`, `cc_maxattempts`).
+type Qtimetadata struct {
+	Text             string             `xml:",chardata"`
+	Qtimetadatafield []Qtimetadatafield `xml:"qtimetadatafield"`
+}
+
+// Qtimetadatafield is a single label and entry pair of a Qtimetadata.
+type Qtimetadatafield struct {
+	Text       string `xml:",chardata"`
+	Fieldlabel string `xml:"fieldlabel"`
+	Fieldentry string `xml:"fieldentry"`
+}
+
+// Field returns the entry of the first metadata field with the given label, or an empty string.
+func (m Qtimetadata) Field(label string) string {
+	for _, f := range m.Qtimetadatafield {
+		if f.Fieldlabel == label {
+			return f.Fieldentry
+		}
+	}
+
+	return ""
+}
+
+// IsOther returns true when the Conditionvar holds `<other/>`, and therefore matches any response.
+func (c Conditionvar) IsOther() bool {
+	return c.Other != nil
+}
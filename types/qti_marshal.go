@@ -0,0 +1,271 @@
+package types
+
+import "encoding/xml"
+
+const (
+	// QTINamespace is the namespace of the QTI 1.2 documents of a cartridge.
+	QTINamespace = "http://www.imsglobal.org/xsd/ims_qtiasiv1p2"
+	// QTISchemaLocation is the location of the CC profile of the QTI 1.2 schema.
+	QTISchemaLocation = "http://www.imsglobal.org/xsd/ims_qtiasiv1p2 http://www.imsglobal.org/profile/cc/ccv1p1/ccv1p1_qtiasiv1p2p1_v1p0.xsd"
+	// XSINamespace is the XML Schema instance namespace, in which the schema location is declared.
+	XSINamespace = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// qtiDocument writes its assessment for MarshalDocument.
+type qtiDocument Questestinterop
+
+// MarshalXML writes the assessment as a CC-profile QTI 1.2 document. Since the structs mirror the elements of all question types, elements and attributes which are empty are left out, and the namespaces default to the ones of the CC profile.
+func (q qtiDocument) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	w := xmlWriter{e: e}
+
+	ns, xsi, location := q.Xmlns, q.Xsi, q.SchemaLocation
+	if ns == "" {
+		ns = QTINamespace
+	}
+	if xsi == "" {
+		xsi = XSINamespace
+	}
+	if location == "" {
+		location = QTISchemaLocation
+	}
+
+	w.start("questestinterop", "xmlns", ns, "xmlns:xsi", xsi, "xsi:schemaLocation", location)
+	w.start("assessment", "ident", q.Assessment.Ident, "title", q.Assessment.Title)
+	w.metadata(q.Assessment.Qtimetadata)
+	for _, s := range q.Assessment.Section {
+		w.section(s)
+	}
+	w.end("assessment")
+	w.end("questestinterop")
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return e.Flush()
+}
+
+// xmlWriter encodes the elements of a document one token at a time, keeping the first error it encounters.
+type xmlWriter struct {
+	e   *xml.Encoder
+	err error
+}
+
+// start opens an element with the given name and attribute pairs, skipping the attributes whose value is empty.
+func (w *xmlWriter) start(name string, attrs ...string) {
+	if w.err != nil {
+		return
+	}
+
+	el := xml.StartElement{Name: xml.Name{Local: name}}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if attrs[i+1] != "" {
+			el.Attr = append(el.Attr, xml.Attr{Name: xml.Name{Local: attrs[i]}, Value: attrs[i+1]})
+		}
+	}
+
+	w.err = w.e.EncodeToken(el)
+}
+
+func (w *xmlWriter) end(name string) {
+	if w.err != nil {
+		return
+	}
+
+	w.err = w.e.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+// element writes an element holding only text.
+func (w *xmlWriter) element(name string, text string, attrs ...string) {
+	w.start(name, attrs...)
+	if w.err == nil && text != "" {
+		w.err = w.e.EncodeToken(xml.CharData(text))
+	}
+	w.end(name)
+}
+
+func (w *xmlWriter) metadata(m Qtimetadata) {
+	if len(m.Qtimetadatafield) == 0 {
+		return
+	}
+
+	w.start("qtimetadata")
+	for _, f := range m.Qtimetadatafield {
+		w.start("qtimetadatafield")
+		w.element("fieldlabel", f.Fieldlabel)
+		w.element("fieldentry", f.Fieldentry)
+		w.end("qtimetadatafield")
+	}
+	w.end("qtimetadata")
+}
+
+func (w *xmlWriter) section(s Section) {
+	w.start("section", "ident", s.Ident, "title", s.Title)
+
+	so := s.SelectionOrdering
+	if so.Selection.SelectionNumber != "" || so.Selection.SourcebankRef != "" || so.Order.OrderType != "" {
+		w.start("selection_ordering")
+		if so.Selection.SelectionNumber != "" || so.Selection.SourcebankRef != "" {
+			w.start("selection")
+			if so.Selection.SourcebankRef != "" {
+				w.element("sourcebank_ref", so.Selection.SourcebankRef)
+			}
+			if so.Selection.SelectionNumber != "" {
+				w.element("selection_number", so.Selection.SelectionNumber)
+			}
+			if so.Selection.SelectionExtension.PointsPerItem != "" {
+				w.start("selection_extension")
+				w.element("points_per_item", so.Selection.SelectionExtension.PointsPerItem)
+				w.end("selection_extension")
+			}
+			w.end("selection")
+		}
+		if so.Order.OrderType != "" {
+			w.element("order", "", "order_type", so.Order.OrderType)
+		}
+		w.end("selection_ordering")
+	}
+
+	for _, item := range s.Item {
+		w.item(item)
+	}
+	for _, sub := range s.Section {
+		w.section(sub)
+	}
+	w.end("section")
+}
+
+func (w *xmlWriter) item(item QTIItem) {
+	w.start("item", "ident", item.Ident, "title", item.Title)
+
+	if len(item.Itemmetadata.Qtimetadata.Qtimetadatafield) > 0 {
+		w.start("itemmetadata")
+		w.metadata(item.Itemmetadata.Qtimetadata)
+		w.end("itemmetadata")
+	}
+
+	p := item.Presentation
+	w.start("presentation")
+	w.material(p.Material)
+	if p.ResponseLid.Ident != "" {
+		w.start("response_lid", "ident", p.ResponseLid.Ident, "rcardinality", p.ResponseLid.Rcardinality)
+		w.start("render_choice")
+		for _, label := range p.ResponseLid.RenderChoice.ResponseLabel {
+			w.start("response_label", "ident", label.Ident)
+			w.material(label.Material)
+			w.end("response_label")
+		}
+		w.end("render_choice")
+		w.end("response_lid")
+	}
+	if p.ResponseStr.Ident != "" {
+		label := p.ResponseStr.RenderFib.ResponseLabel
+		w.start("response_str", "ident", p.ResponseStr.Ident, "rcardinality", p.ResponseStr.Rcardinality)
+		w.start("render_fib", "fibtype", p.ResponseStr.RenderFib.Fibtype)
+		if label.Ident != "" {
+			w.element("response_label", "", "ident", label.Ident, "rshuffle", label.Rshuffle)
+		}
+		w.end("render_fib")
+		w.end("response_str")
+	}
+	w.end("presentation")
+
+	rp := item.Resprocessing
+	w.start("resprocessing")
+	if d := rp.Outcomes.Decvar; d.Varname != "" || d.Vartype != "" {
+		w.start("outcomes")
+		w.element("decvar", "", "defaultval", d.Defaultval, "maxvalue", d.Maxvalue, "minvalue", d.Minvalue, "varname", d.Varname, "vartype", d.Vartype)
+		w.end("outcomes")
+	}
+	for _, rc := range rp.Respcondition {
+		w.respcondition(rc)
+	}
+	w.end("resprocessing")
+
+	for _, fb := range item.Itemfeedback {
+		w.start("itemfeedback", "ident", fb.Ident)
+		w.start("flow_mat")
+		w.material(fb.FlowMat.Material)
+		w.end("flow_mat")
+		w.end("itemfeedback")
+	}
+
+	w.end("item")
+}
+
+func (w *xmlWriter) material(m Material) {
+	if m.Mattext.Text == "" && m.Mattext.Texttype == "" {
+		return
+	}
+
+	w.start("material")
+	w.element("mattext", m.Mattext.Text, "texttype", m.Mattext.Texttype)
+	w.end("material")
+}
+
+func (w *xmlWriter) respcondition(rc Respcondition) {
+	w.start("respcondition", "continue", rc.Continue)
+
+	w.start("conditionvar")
+	w.conditionvar(rc.Conditionvar)
+	w.end("conditionvar")
+
+	if rc.Setvar.Action != "" || rc.Setvar.Varname != "" {
+		w.element("setvar", rc.Setvar.Text, "action", rc.Setvar.Action, "varname", rc.Setvar.Varname)
+	}
+	if rc.Displayfeedback.Linkrefid != "" {
+		w.element("displayfeedback", "", "feedbacktype", rc.Displayfeedback.Feedbacktype, "linkrefid", rc.Displayfeedback.Linkrefid)
+	}
+
+	w.end("respcondition")
+}
+
+// conditionvar writes the tests of a condition, including `<other/>` and the ones which are not modelled.
+func (w *xmlWriter) conditionvar(c Conditionvar) {
+	if c.IsOther() {
+		w.element("other", "")
+	}
+	if len(c.And.Varequal) > 0 || len(c.And.Not) > 0 || len(c.And.Vargte) > 0 || len(c.And.Varlte) > 0 || len(c.And.Unknown) > 0 {
+		w.start("and")
+		w.vartests("varequal", c.And.Varequal)
+		w.nots(c.And.Not)
+		w.vartests("vargte", c.And.Vargte)
+		w.vartests("varlte", c.And.Varlte)
+		w.unknownTests(c.And.Unknown)
+		w.end("and")
+	}
+	for _, or := range c.Or {
+		w.start("or")
+		w.conditionvar(or)
+		w.end("or")
+	}
+	w.vartests("varequal", c.Varequal)
+	w.vartests("varsubstring", c.Varsubstring)
+	w.vartests("vargte", c.Vargte)
+	w.vartests("varlte", c.Varlte)
+	w.nots(c.Not)
+	w.unknownTests(c.Unknown)
+}
+
+func (w *xmlWriter) vartests(name string, tests []Vartest) {
+	for _, t := range tests {
+		w.element(name, t.Text, "respident", t.Respident, "case", t.Case)
+	}
+}
+
+func (w *xmlWriter) unknownTests(tests []UnknownTest) {
+	for _, t := range tests {
+		w.element(t.XMLName.Local, t.Text, "respident", t.Respident)
+	}
+}
+
+func (w *xmlWriter) nots(nots []Not) {
+	for _, n := range nots {
+		w.start("not")
+		if n.Varequal.Respident != "" || n.Varequal.Text != "" {
+			w.vartests("varequal", []Vartest{n.Varequal})
+		}
+		w.unknownTests(n.Unknown)
+		w.end("not")
+	}
+}
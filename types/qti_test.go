@@ -0,0 +1,64 @@
+package types
+
+import (
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMarshalQTI(t *testing.T) {
+	bytes, err := os.ReadFile("./examples/qti.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var qti Questestinterop
+	if err = xml.Unmarshal(bytes, &qti); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := MarshalDocument(qti)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(string(out), `<questestinterop xmlns="`+QTINamespace+`" xmlns:xsi="`+XSINamespace+`" xsi:schemaLocation="`) {
+		t.Errorf("expected the namespaces to be declared, got %s", out[:120])
+	}
+
+	for _, empty := range []string{"<response_lid></response_lid>", "<response_str></response_str>", "<displayfeedback></displayfeedback>", `ident=""`} {
+		if strings.Contains(string(out), empty) {
+			t.Errorf("expected no empty %s", empty)
+		}
+	}
+
+	var back Questestinterop
+	if err = xml.Unmarshal(out, &back); err != nil {
+		t.Fatal(err)
+	}
+
+	items, backItems := qti.Items(), back.Items()
+	if len(backItems) != len(items) {
+		t.Fatalf("expected %d items, got %d", len(items), len(backItems))
+	}
+
+	for i := range items {
+		if backItems[i].Ident != items[i].Ident || len(backItems[i].Resprocessing.Respcondition) != len(items[i].Resprocessing.Respcondition) {
+			t.Errorf("expected item %s to be preserved", items[i].Ident)
+		}
+	}
+
+	if !back.Items()[0].Resprocessing.Respcondition[0].Conditionvar.IsOther() {
+		t.Errorf("expected <other/> to be preserved")
+	}
+
+	again, err := MarshalDocument(back)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(again) != string(out) {
+		t.Errorf("expected marshaling to be stable")
+	}
+}
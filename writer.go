@@ -0,0 +1,214 @@
+package commoncartridge
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+)
+
+// writerModified is the modification time of all the files written by a Writer, so that the same cartridge is always written to the same bytes. It is the earliest time that a zip archive can hold.
+var writerModified = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// writerVersions are the versions of the IMSCC standard that a Writer can write.
+var writerVersions = map[string]bool{"1.0.0": true, "1.1.0": true, "1.2.0": true, "1.3.0": true}
+
+// Writer assembles a cartridge from its manifest, the XML documents of its resources and its other files, such as the pages of its `webcontent` resources, and writes it as an `.imscc` archive.
+type Writer struct {
+	manifest *types.Manifest
+	// files are the contents of the files of the cartridge, by their path in the archive.
+	files map[string][]byte
+}
+
+// NewWriter returns a Writer for a cartridge with the given manifest, whose schema and version default to `IMS Common Cartridge` and `1.3.0`. The manifest is written with the namespaces of its version, from 1.0 to 1.3, regardless of the ones it was read with, and with the resources added to the Writer after its own. Only the elements that types.Manifest models are written: the extensions of vendors to the manifest are not.
+func NewWriter(manifest types.Manifest) (Writer, error) {
+	manifest.Resources.Resource = append([]types.Resource(nil), manifest.Resources.Resource...)
+	w := Writer{manifest: &manifest, files: make(map[string][]byte)}
+
+	if manifest.Identifier == "" {
+		return w, fmt.Errorf("the manifest has no identifier")
+	}
+
+	if w.manifest.Metadata.Schema == "" {
+		w.manifest.Metadata.Schema = "IMS Common Cartridge"
+	}
+	if w.manifest.Metadata.Schemaversion == "" {
+		w.manifest.Metadata.Schemaversion = "1.3.0"
+	}
+
+	if version := w.manifest.Metadata.Schemaversion; !writerVersions[version] {
+		return w, fmt.Errorf("unsupported version of the IMSCC standard: %s", version)
+	}
+
+	return w, nil
+}
+
+// AddFile adds a file to the cartridge at the given path, relative to the root of the archive, without declaring a resource for it, e.g. a file of a resource of the manifest given to the Writer.
+func (w Writer) AddFile(name string, content []byte) error {
+	if name == "" || name != path.Clean(name) || path.IsAbs(name) || strings.HasPrefix(name, "../") || name == ".." {
+		return fmt.Errorf("the path %q is not a relative path within the archive", name)
+	}
+
+	if name == "imsmanifest.xml" {
+		return fmt.Errorf("the manifest is written from the one given to the writer")
+	}
+
+	if _, ok := w.files[name]; ok {
+		return fmt.Errorf("the file %s was already added", name)
+	}
+
+	w.files[name] = content
+	return nil
+}
+
+// AddWebContent adds a `webcontent` resource to the cartridge, whose only file is the given one.
+func (w Writer) AddWebContent(identifier string, name string, content []byte) error {
+	if err := w.AddFile(name, content); err != nil {
+		return err
+	}
+
+	w.addResource(identifier, "webcontent", name, name)
+	return nil
+}
+
+// AddResource adds a resource to the cartridge, whose XML document is written at the given path with the namespace and schema location of the version of the cartridge, and whose type follows from the document. The document is a types.Topic, types.WebLink, types.Questestinterop, or, from version 1.1 on, a types.Assignment or types.CartridgeBasicltiLink.
+func (w Writer) AddResource(identifier string, name string, doc interface{}) error {
+	ns := types.CCNamespaces(w.manifest.Metadata.Schemaversion)
+
+	resourceType := ""
+	switch d := doc.(type) {
+	case types.Topic:
+		d.Xmlns, d.Xsi, d.SchemaLocation = ns.Topic, types.XSINamespace, ns.Topic+" "+ns.TopicLocation
+		doc, resourceType = d, "imsdt_xmlv"+ns.Version
+	case types.WebLink:
+		d.Xmlns, d.Xsi, d.SchemaLocation = ns.WebLink, types.XSINamespace, ns.WebLink+" "+ns.WebLinkLocation
+		doc, resourceType = d, "imswl_xmlv"+ns.Version
+	case types.Questestinterop:
+		d.Xmlns, d.Xsi, d.SchemaLocation = types.QTINamespace, types.XSINamespace, types.QTINamespace+" "+ns.QTILocation
+		doc, resourceType = d, AssessmentType(w.manifest.Metadata.Schemaversion)
+	case types.Assignment:
+		d.Xmlns, d.Xsi, d.SchemaLocation = types.AssignmentNamespace, types.XSINamespace, types.AssignmentSchemaLocation
+		doc, resourceType = d, "assignment_xmlv1p0"
+	case types.CartridgeBasicltiLink:
+		d.Xmlns, d.Xsi, d.SchemaLocation = types.LTINamespace, types.XSINamespace, types.LTISchemaLocation
+		doc, resourceType = d, "imsbasiclti_xmlv1p0"
+	default:
+		return fmt.Errorf("cannot write a resource of type %T", doc)
+	}
+
+	if ns.Version == "1p0" && (resourceType == "assignment_xmlv1p0" || resourceType == "imsbasiclti_xmlv1p0") {
+		return fmt.Errorf("version 1.0 of the IMSCC standard has no resources of type %s", resourceType)
+	}
+
+	content, err := types.MarshalDocument(doc)
+	if err != nil {
+		return err
+	}
+
+	if err := w.AddFile(name, append([]byte(xml.Header), content...)); err != nil {
+		return err
+	}
+
+	w.addResource(identifier, resourceType, "", name)
+	return nil
+}
+
+// addResource appends to the manifest a resource whose only file is the given one.
+func (w Writer) addResource(identifier string, resourceType string, href string, file string) {
+	r := types.Resource{Identifier: identifier, Type: resourceType, Href: href}
+	r.File = append(r.File, struct {
+		Text string `xml:",chardata"`
+		Href string `xml:"href,attr"`
+	}{Href: file})
+
+	w.manifest.Resources.Resource = append(w.manifest.Resources.Resource, r)
+}
+
+// Write writes the cartridge as a zip archive to dst: the manifest first, then the other files in the order of their paths. It first checks that the identifiers of the resources are unique and that their files were added, but not that the items of the organization refer to existing resources, since Canvas exports items of external tools which do not: Validate checks that as well.
+func (w Writer) Write(dst io.Writer) error {
+	if err := w.validate(); err != nil {
+		return err
+	}
+
+	manifest, err := types.MarshalDocument(*w.manifest)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(dst)
+	if err := writeFile(zw, "imsmanifest.xml", writerModified, append([]byte(xml.Header), manifest...)); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(w.files))
+	for name := range w.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := writeFile(zw, name, writerModified, w.files[name]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// Validate returns an error when Write would, or when an item of the organization refers to a resource which does not exist.
+func (w Writer) Validate() error {
+	if err := w.validate(); err != nil {
+		return err
+	}
+
+	resources := make(map[string]bool)
+	for _, r := range w.manifest.Resources.Resource {
+		resources[r.Identifier] = true
+	}
+
+	var check func(item types.Item) error
+	check = func(item types.Item) error {
+		if item.Identifierref != "" && !resources[item.Identifierref] {
+			return fmt.Errorf("the item %s refers to the resource %s, which does not exist", item.Identifier, item.Identifierref)
+		}
+		for _, child := range item.Item {
+			if err := check(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return check(w.manifest.Organizations.Organization.Item)
+}
+
+// validate returns an error when a resource has no identifier or shares it with another, or when a file of a resource is missing.
+func (w Writer) validate() error {
+	resources := make(map[string]bool)
+	for _, r := range w.manifest.Resources.Resource {
+		if r.Identifier == "" {
+			return fmt.Errorf("a resource of type %s has no identifier", r.Type)
+		}
+		if resources[r.Identifier] {
+			return fmt.Errorf("more than one resource has the identifier %s", r.Identifier)
+		}
+		resources[r.Identifier] = true
+
+		if _, ok := w.files[r.Href]; r.Href != "" && !ok {
+			return fmt.Errorf("the file %s of the resource %s was not added", r.Href, r.Identifier)
+		}
+		for _, f := range r.File {
+			if _, ok := w.files[f.Href]; !ok {
+				return fmt.Errorf("the file %s of the resource %s was not added", f.Href, r.Identifier)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,318 @@
+package commoncartridge
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/commonsyllabi/commoncartridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	var topic types.Topic
+	var weblink types.WebLink
+	var lti types.CartridgeBasicltiLink
+	var assignment types.Assignment
+	readExample(t, "./types/examples/topic.xml", &topic)
+	readExample(t, "./types/examples/weblink.xml", &weblink)
+	readExample(t, "./types/examples/lti.xml", &lti)
+	readExample(t, "./types/examples/assignment.xml", &assignment)
+	qti := loadQTI(t, qtiExample)
+
+	w := newTestWriter(t, "1.2.0")
+	require.Nil(t, w.AddWebContent("R_1", "R_1/page.html", []byte("<p>Welcome</p>")))
+	require.Nil(t, w.AddResource("R_2", "R_2/topic.xml", topic))
+	require.Nil(t, w.AddResource("R_3", "R_3/weblink.xml", weblink))
+	require.Nil(t, w.AddResource("R_4", "R_4/assessment_qti.xml", qti))
+	require.Nil(t, w.AddResource("R_5", "R_5/lti.xml", lti))
+	require.Nil(t, w.AddResource("R_6", "R_6/assignment.xml", assignment))
+
+	cc := load(t, write(t, w)).(IMSCC)
+	assert.Equal(t, cc.Title(), "Written cartridge")
+
+	manifest, err := cc.Manifest()
+	require.Nil(t, err)
+	assert.Equal(t, marshal(t, manifest), marshal(t, *w.manifest))
+
+	resourceTypes := make([]string, 0)
+	for _, r := range manifest.Resources.Resource {
+		resourceTypes = append(resourceTypes, r.Type)
+	}
+	assert.Equal(t, resourceTypes, []string{"webcontent", "imsdt_xmlv1p2", "imswl_xmlv1p2", "imsqti_xmlv1p2/imscc_xmlv1p2/assessment", "imsbasiclti_xmlv1p0", "assignment_xmlv1p0"})
+
+	items, err := cc.Items()
+	require.Nil(t, err)
+	require.Equal(t, len(items), 6)
+	assert.Equal(t, items[1].Item.Title, "Topic")
+	assert.Equal(t, items[1].Resources[0].Identifier, "R_2")
+
+	file, err := cc.FindFile("R_1")
+	require.Nil(t, err)
+	page, err := io.ReadAll(file)
+	require.Nil(t, err)
+	assert.Equal(t, string(page), "<p>Welcome</p>")
+
+	topics, err := cc.Topics()
+	require.Nil(t, err)
+	require.Equal(t, len(topics), 1)
+	assert.Equal(t, topics[0].Title, topic.Title)
+	assert.Equal(t, topics[0].Text.Text, topic.Text.Text)
+	assert.Equal(t, len(topics[0].Attachments.Attachment), 2)
+	assert.Equal(t, topics[0].Xmlns, "http://www.imsglobal.org/xsd/imsccv1p2/imsdt_v1p2")
+	assert.Equal(t, topics[0].SchemaLocation, "http://www.imsglobal.org/xsd/imsccv1p2/imsdt_v1p2 http://www.imsglobal.org/profile/cc/ccv1p2/ccv1p2_imsdt_v1p2.xsd")
+
+	weblinks, err := cc.Weblinks()
+	require.Nil(t, err)
+	require.Equal(t, len(weblinks), 1)
+	assert.Equal(t, weblinks[0].Title, weblink.Title)
+	assert.Equal(t, weblinks[0].URL, weblink.URL)
+	assert.Equal(t, weblinks[0].Xmlns, "http://www.imsglobal.org/xsd/imsccv1p2/imswl_v1p2")
+
+	qtis, err := cc.QTIs()
+	require.Nil(t, err)
+	require.Equal(t, len(qtis), 1)
+	assert.Equal(t, qtis[0].SchemaLocation, "http://www.imsglobal.org/xsd/ims_qtiasiv1p2 http://www.imsglobal.org/profile/cc/ccv1p2/ccv1p2_qtiasiv1p2p1_v1p0.xsd")
+	qti.Xmlns, qti.Xsi, qti.SchemaLocation = qtis[0].Xmlns, qtis[0].Xsi, qtis[0].SchemaLocation
+	assert.Equal(t, marshal(t, qtis[0]), marshal(t, qti))
+
+	ltis, err := cc.LTIs()
+	require.Nil(t, err)
+	require.Equal(t, len(ltis), 1)
+	assert.Equal(t, ltis[0].Title, lti.Title)
+	assert.Equal(t, ltis[0].SecureLaunchURL, lti.SecureLaunchURL)
+	assert.Equal(t, ltis[0].Custom.Property.Name, "keyname")
+	assert.Equal(t, ltis[0].Vendor.Contact.Email, lti.Vendor.Contact.Email)
+	assert.Equal(t, ltis[0].CartridgeBundle.Identifierref, lti.CartridgeBundle.Identifierref)
+
+	assignments, err := cc.Assignments()
+	require.Nil(t, err)
+	require.Equal(t, len(assignments), 1)
+	assert.Equal(t, assignments[0].Identifier, assignment.Identifier)
+	assert.Equal(t, assignments[0].Gradable.PointsPossible, "10")
+	assert.Equal(t, len(assignments[0].SubmissionFormats.Format), 3)
+}
+
+func TestWriterVersions(t *testing.T) {
+	var weblink types.WebLink
+	readExample(t, "./types/examples/weblink.xml", &weblink)
+
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0", "1.3.0"} {
+		w := newTestWriter(t, version)
+		w.manifest.Organizations.Organization.Item.Item = w.manifest.Organizations.Organization.Item.Item[:1]
+		require.Nil(t, w.AddWebContent("R_1", "R_1/page.html", []byte("<p>Welcome</p>")))
+		require.Nil(t, w.AddResource("R_2", "R_2/weblink.xml", weblink))
+
+		cc := load(t, write(t, w)).(IMSCC)
+		ns := types.CCNamespaces(version)
+
+		manifest := readArchiveFile(t, cc, "imsmanifest.xml")
+		assert.Contains(t, manifest, fmt.Sprintf(`<manifest identifier="M_1" xmlns="%s"`, ns.Manifest))
+		assert.Contains(t, manifest, fmt.Sprintf(`xsi:schemaLocation="%s %s`, ns.Manifest, ns.ManifestLocation))
+		assert.Contains(t, manifest, fmt.Sprintf(`<schemaversion>%s</schemaversion>`, version))
+		assert.Contains(t, readArchiveFile(t, cc, "R_2/weblink.xml"), fmt.Sprintf(`<webLink xmlns="%s"`, ns.WebLink))
+
+		assert.Equal(t, cc.manifest.Metadata.Schemaversion, version)
+		assert.Equal(t, cc.manifest.Resources.Resource[1].Type, "imswl_xmlv"+ns.Version)
+		assert.Equal(t, cc.Title(), "Written cartridge")
+	}
+
+	w := newTestWriter(t, "1.0.0")
+	var lti types.CartridgeBasicltiLink
+	assert.NotNil(t, w.AddResource("R_5", "R_5/lti.xml", lti))
+
+	manifest := types.Manifest{Identifier: "M_1"}
+	manifest.Metadata.Schemaversion = "1.4.0"
+	_, err := NewWriter(manifest)
+	assert.NotNil(t, err)
+}
+
+func TestWriterDeterministic(t *testing.T) {
+	cc := load(t, filepath.Join(allTestFilesDir, "sample-public-sandbox-course-export.imscc")).(IMSCC)
+
+	first, second := copyCartridge(t, cc), copyCartridge(t, cc)
+	a, err := os.ReadFile(write(t, first))
+	require.Nil(t, err)
+	b, err := os.ReadFile(write(t, second))
+	require.Nil(t, err)
+	assert.True(t, bytes.Equal(a, b))
+
+	written := load(t, write(t, first)).(IMSCC)
+	require.Equal(t, written.Reader.File[0].Name, "imsmanifest.xml")
+	for i := 2; i < len(written.Reader.File); i++ {
+		assert.Less(t, written.Reader.File[i-1].Name, written.Reader.File[i].Name)
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	names, err := filepath.Glob(filepath.Join(allTestFilesDir, "*"))
+	require.Nil(t, err)
+	names = append(names, singleTestFile)
+
+	for _, name := range names {
+		cc := load(t, name).(IMSCC)
+		written := load(t, write(t, copyCartridge(t, cc))).(IMSCC)
+
+		original, err := cc.Manifest()
+		require.Nil(t, err)
+		manifest, err := written.Manifest()
+		require.Nil(t, err)
+		assert.Equal(t, marshal(t, manifest), marshal(t, original), name)
+		assert.Equal(t, written.Title(), cc.Title(), name)
+
+		items, err := cc.Items()
+		require.Nil(t, err)
+		writtenItems, err := written.Items()
+		require.Nil(t, err)
+		assert.Equal(t, titles(writtenItems), titles(items), name)
+
+		for _, f := range cc.Reader.File {
+			if f.Name != "imsmanifest.xml" && !f.FileInfo().IsDir() {
+				assert.Equal(t, readArchiveFile(t, written, f.Name), readArchiveFile(t, cc, f.Name), name)
+			}
+		}
+
+		weblinks, err := cc.Weblinks()
+		require.Nil(t, err)
+		writtenWeblinks, err := written.Weblinks()
+		require.Nil(t, err)
+		assert.Equal(t, writtenWeblinks, weblinks, name)
+	}
+
+	cc := load(t, filepath.Join(allTestFilesDir, "Falconer_Liz-Computers_Canvas_Community-941267a5971248daa62d3196014d1e65.zip")).(IMSCC)
+	manifest := readArchiveFile(t, load(t, write(t, copyCartridge(t, cc))).(IMSCC), "imsmanifest.xml")
+	assert.Contains(t, manifest, `xmlns:cpx="http://www.imsglobal.org/xsd/imsccv1p3/imscp_extensionv1p2"`)
+	assert.Contains(t, manifest, "http://www.imsglobal.org/xsd/imsccv1p3/imscp_extensionv1p2 http://www.imsglobal.org/profile/cc/ccv1p3/ccv1p3_cpextensionv1p2_v1p0.xsd")
+	assert.Contains(t, manifest, `<cpx:variant identifier=`)
+}
+
+func TestWriterErrors(t *testing.T) {
+	_, err := NewWriter(types.Manifest{})
+	assert.NotNil(t, err)
+
+	cc := load(t, singleTestFile).(IMSCC)
+	assert.Nil(t, copyCartridge(t, cc).Write(io.Discard))
+	assert.NotNil(t, copyCartridge(t, cc).Validate(), "an item of the cartridge refers to a resource which does not exist")
+
+	manifest, err := cc.Manifest()
+	require.Nil(t, err)
+	manifest.Metadata.Schemaversion = "1.1.0"
+	manifest.Resources.Resource[0].Variant.Identifier = "V_1"
+	w, err := NewWriter(manifest)
+	require.Nil(t, err)
+	assert.NotNil(t, w.Write(io.Discard), "version 1.1 has no variants")
+
+	w = newTestWriter(t, "1.3.0")
+	assert.NotNil(t, w.AddFile("../outside.html", nil))
+	assert.NotNil(t, w.AddFile("/absolute.html", nil))
+	assert.NotNil(t, w.AddFile("imsmanifest.xml", nil))
+	assert.NotNil(t, w.AddResource("R_1", "R_1/resource.xml", types.Resource{}))
+
+	require.Nil(t, w.AddWebContent("R_1", "R_1/page.html", nil))
+	assert.NotNil(t, w.AddFile("R_1/page.html", nil))
+	assert.Nil(t, w.Write(io.Discard))
+	assert.NotNil(t, w.Validate(), "the items refer to resources which were not added")
+
+	w = newTestWriter(t, "1.3.0")
+	w.manifest.Organizations.Organization.Item.Item = nil
+	require.Nil(t, w.AddWebContent("R_1", "R_1/page.html", nil))
+	require.Nil(t, w.AddWebContent("R_1", "R_1/other.html", nil))
+	assert.NotNil(t, w.Write(io.Discard), "two resources have the same identifier")
+
+	missing := w.manifest
+	missing.Resources.Resource = missing.Resources.Resource[:1]
+	missing.Resources.Resource[0].File[0].Href = "R_1/missing.html"
+	w, err = NewWriter(*missing)
+	require.Nil(t, err)
+	assert.NotNil(t, w.Write(io.Discard), "the file of the resource was not added")
+}
+
+// newTestWriter returns a Writer for a cartridge of the given version, whose organization has an item for each of the resources R_1 to R_6.
+func newTestWriter(t *testing.T, version string) Writer {
+	manifest := types.Manifest{Identifier: "M_1"}
+	manifest.Metadata.Schemaversion = version
+	manifest.Metadata.Lom.General.Title.String.Text = "Written cartridge"
+	manifest.Metadata.Lom.General.Title.String.Language = "en-US"
+
+	manifest.Organizations.Organization.Identifier = "O_1"
+	manifest.Organizations.Organization.Item.Identifier = "I_root"
+	for i, title := range []string{"Page", "Topic", "Link", "Quiz", "Tool", "Assignment"} {
+		manifest.Organizations.Organization.Item.Item = append(manifest.Organizations.Organization.Item.Item, types.Item{
+			Identifier:    fmt.Sprintf("I_%d", i+1),
+			Identifierref: fmt.Sprintf("R_%d", i+1),
+			Title:         title,
+		})
+	}
+
+	w, err := NewWriter(manifest)
+	require.Nil(t, err)
+
+	return w
+}
+
+// copyCartridge returns a Writer holding the manifest and all the files of the given cartridge.
+func copyCartridge(t *testing.T, cc IMSCC) Writer {
+	manifest, err := cc.Manifest()
+	require.Nil(t, err)
+
+	w, err := NewWriter(manifest)
+	require.Nil(t, err)
+
+	for _, f := range cc.Reader.File {
+		if f.Name == "imsmanifest.xml" || f.FileInfo().IsDir() {
+			continue
+		}
+		require.Nil(t, w.AddFile(f.Name, []byte(readArchiveFile(t, cc, f.Name))))
+	}
+
+	return w
+}
+
+// write writes the cartridge of the Writer to a temporary file, and returns its path.
+func write(t *testing.T, w Writer) string {
+	dst := filepath.Join(t.TempDir(), "written.imscc")
+	file, err := os.Create(dst)
+	require.Nil(t, err)
+
+	require.Nil(t, w.Write(file))
+	require.Nil(t, file.Close())
+
+	return dst
+}
+
+func readExample(t *testing.T, p string, v interface{}) {
+	bytes, err := os.ReadFile(p)
+	require.Nil(t, err)
+	require.Nil(t, xml.Unmarshal(bytes, v))
+}
+
+func readArchiveFile(t *testing.T, cc IMSCC, name string) string {
+	file, err := cc.Reader.Open(name)
+	require.Nil(t, err)
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	require.Nil(t, err)
+	return string(content)
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	out, err := types.MarshalDocument(v)
+	require.Nil(t, err)
+	return string(out)
+}
+
+// titles returns the titles of the items and of their children, depth first.
+func titles(items []FullItem) []string {
+	all := make([]string, 0)
+	for _, item := range items {
+		all = append(all, item.Item.Title)
+		all = append(all, titles(item.Children)...)
+	}
+	return all
+}